@@ -6,15 +6,26 @@ import (
 	"log/slog"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/p3rtang/org-mcp/mcp"
 	"github.com/p3rtang/org-mcp/orgmcp"
 	"github.com/p3rtang/org-mcp/tools"
+	"github.com/p3rtang/org-mcp/utils/option"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 func init() {
+	rootCmd.PersistentFlags().String("now", "", "Fixed current time used for relative dates, CLOSED and clock times, formatted as \"2006-01-02 15:04\" or \"2006-01-02\"")
+	rootCmd.PersistentFlags().String("id-format", string(orgmcp.IDNumeric), "Format of generated header IDs, numeric or uuid like org-id")
+	rootCmd.PersistentFlags().Uint64("id-seed", 0, "Seed for generated header IDs, the same seed generates the same IDs. Meant for tests")
+	rootCmd.PersistentFlags().Bool("lossless", false, "Keep the original text of everything an operation did not change, including blank lines, indentation and alignment")
+	rootCmd.PersistentFlags().Bool("respect-locks", false, "Refuse to write files with unsaved changes in Emacs (a .#file.org lock file exists) instead of only warning")
+	rootCmd.PersistentFlags().String("backup-dir", "", "Directory to keep a copy of a file in before it is overwritten, relative to the file. Backups are disabled when empty")
+	rootCmd.PersistentFlags().Int("backups", 10, "Number of backups kept per file in --backup-dir")
+	rootCmd.PersistentFlags().Int("journal-size", 50, "Number of changes per file that can be undone, 0 disables the journal")
+	rootCmd.PersistentFlags().String("todo-keywords", "", "Default TODO sequence for files without a #+TODO line, e.g. \"TODO WAIT | DONE CANCELLED\"")
 	rootCmd.AddCommand(&serveCmd)
 
 	exportCmd.Flags().StringP("input", "i", ".tasks.org", "Input Org file")
@@ -24,6 +35,28 @@ func init() {
 
 	embedCommand.Flags().StringP("input", "i", ".tasks.org", "Input Org file")
 	rootCmd.AddCommand(&embedCommand)
+
+	repairIDsCmd.Flags().StringP("input", "i", ".tasks.org", "Input Org file")
+	repairIDsCmd.Flags().Bool("dry-run", false, "Only list the duplicate IDs, do not change the file")
+	rootCmd.AddCommand(&repairIDsCmd)
+
+	sortCmd.Flags().StringP("input", "i", ".tasks.org", "Input Org file")
+	sortCmd.Flags().StringP("uid", "u", "0", "UID of the header whose sub headers are sorted, 0 sorts the top level headers")
+	sortCmd.Flags().StringP("by", "b", string(orgmcp.SortAlpha), "Sort key: alpha, status, priority, scheduled, deadline, closed or property")
+	sortCmd.Flags().StringP("property", "p", "", "Property to sort by when --by is property")
+	sortCmd.Flags().Bool("desc", false, "Sort in descending order")
+	sortCmd.Flags().Bool("stable", false, "Keep the order of headers with the same value instead of ordering them by headline")
+	sortCmd.Flags().BoolP("recursive", "r", false, "Sort the sub headers of the sorted headers as well")
+	rootCmd.AddCommand(&sortCmd)
+
+	restoreBackupCmd.Flags().StringP("input", "i", ".tasks.org", "Input Org file")
+	rootCmd.AddCommand(&restoreBackupCmd)
+
+	undoCmd.Flags().StringP("input", "i", ".tasks.org", "Input Org file")
+	undoCmd.Flags().IntP("count", "n", 1, "Number of changes to undo or redo")
+	undoCmd.Flags().Bool("redo", false, "Apply undone changes again instead of undoing")
+	undoCmd.Flags().Bool("list", false, "Only list the changes that can be undone and redone")
+	rootCmd.AddCommand(&undoCmd)
 }
 
 var rootCmd = cobra.Command{
@@ -33,6 +66,53 @@ var rootCmd = cobra.Command{
 org-mcp is a command-line tool that implements the MCP protocol to manage Org files.
 It can run as a server that listens for MCP messages and responds accordingly, or it can export Org files to Markdown format.
 `,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		config := orgmcp.DefaultConfig()
+
+		if todo, _ := cmd.Flags().GetString("todo-keywords"); todo != "" {
+			keywords, err := orgmcp.ParseTodoKeywords(todo)
+			if err != nil {
+				return fmt.Errorf("invalid --todo-keywords: %w", err)
+			}
+
+			config.TodoKeywords = keywords
+		}
+
+		config.Lossless, _ = cmd.Flags().GetBool("lossless")
+		config.RespectLocks, _ = cmd.Flags().GetBool("respect-locks")
+		config.BackupDir, _ = cmd.Flags().GetString("backup-dir")
+		config.Backups, _ = cmd.Flags().GetInt("backups")
+		config.JournalSize, _ = cmd.Flags().GetInt("journal-size")
+
+		format, _ := cmd.Flags().GetString("id-format")
+		seed := option.None[uint64]()
+		if cmd.Flags().Changed("id-seed") {
+			value, _ := cmd.Flags().GetUint64("id-seed")
+			seed = option.Some(value)
+		}
+
+		ids, err := orgmcp.NewIDGenerator(orgmcp.IDFormat(format), seed)
+		if err != nil {
+			return fmt.Errorf("invalid --id-format: %w", err)
+		}
+
+		config.IDs = ids
+
+		if now, _ := cmd.Flags().GetString("now"); now != "" {
+			fixed, err := time.ParseInLocation("2006-01-02 15:04", now, time.Local)
+			if err != nil {
+				if fixed, err = time.ParseInLocation("2006-01-02", now, time.Local); err != nil {
+					return fmt.Errorf("invalid --now: %w", err)
+				}
+			}
+
+			config.Now = func() time.Time { return fixed }
+		}
+
+		orgmcp.Configure(config)
+
+		return nil
+	},
 }
 
 var serveCmd = cobra.Command{
@@ -64,6 +144,15 @@ This command can be simply run without the "serve" subcommand, as it is the defa
 		server.AddTool(&tools.StatusTool)
 		server.AddTool(&tools.VectorSearch)
 		server.AddTool(&tools.TextTool)
+		server.AddTool(&tools.BlockTool)
+		server.AddTool(&tools.TableTool)
+		server.AddTool(&tools.ClockTool)
+		server.AddTool(&tools.MoveTool)
+		server.AddTool(&tools.CloneTool)
+		server.AddTool(&tools.RepairIDsTool)
+		server.AddTool(&tools.RestoreBackupTool)
+		server.AddTool(&tools.UndoTool)
+		server.AddTool(&tools.BatchTool)
 
 		if err := server.Run(ctx); err != nil {
 			logger.Error(fmt.Sprintf("Server error: %v", err))
@@ -171,3 +260,199 @@ The command reads the specified Org file, extracts relevant content, and puts th
 		}
 	},
 }
+
+var repairIDsCmd = cobra.Command{
+	Use:   "repair-ids",
+	Short: "Give headers with a duplicate ID a new ID",
+	Long: `
+Headers that share an ID with an earlier header of the file, usually because a subtree was copied, get a new ID.
+The first header keeps its ID. id: links inside a copied subtree that point at a header of that subtree are updated to the new ID.
+Every repaired header is printed as "old -> new: headline".
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if os.Getenv("SHOW_DEBUG") == "" {
+			os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+		}
+
+		ctx := cmd.Context()
+		logger := ctx.Value("logger").(*slog.Logger)
+
+		file, _ := cmd.Flags().GetString("input")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		orgFile, err := mcp.LoadOrgFile(ctx, file)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to parse %s: %v", file, err))
+			os.Exit(1)
+		}
+
+		if dryRun {
+			for _, warning := range orgFile.Warnings() {
+				fmt.Println(warning)
+			}
+
+			return
+		}
+
+		repairs, links := orgFile.RepairIDs()
+		for _, repair := range repairs {
+			fmt.Printf("%s -> %s: %s\n", repair.Old, repair.New, repair.Header.Content)
+		}
+
+		if len(repairs) == 0 {
+			fmt.Println("No duplicate IDs found")
+			return
+		}
+
+		fmt.Printf("Repaired %d IDs and updated %d links\n", len(repairs), links)
+
+		if _, err := mcp.WriteOrgFileToDisk(ctx, orgFile, file); err != nil {
+			logger.Error(fmt.Sprintf("Failed to write updated org file to disk: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+var sortCmd = cobra.Command{
+	Use:   "sort",
+	Short: "Sort the sub headers of a header",
+	Long: `
+Sorts the sub headers of a header, or the top level headers of the file, like org-sort.
+The body of the header stays above its sub headers and every header keeps its ID.
+Headers without the date or property that is sorted by go last.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if os.Getenv("SHOW_DEBUG") == "" {
+			os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+		}
+
+		ctx := cmd.Context()
+		logger := ctx.Value("logger").(*slog.Logger)
+
+		file, _ := cmd.Flags().GetString("input")
+		uid, _ := cmd.Flags().GetString("uid")
+		by, _ := cmd.Flags().GetString("by")
+
+		key, err := orgmcp.ParseSortKey(by)
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+
+		options := orgmcp.SortOptions{Key: key}
+		options.Property, _ = cmd.Flags().GetString("property")
+		options.Descending, _ = cmd.Flags().GetBool("desc")
+		options.Stable, _ = cmd.Flags().GetBool("stable")
+		options.Recursive, _ = cmd.Flags().GetBool("recursive")
+
+		orgFile, err := mcp.LoadOrgFile(ctx, file)
+		if err != nil {
+			logger.Error(fmt.Sprintf("Failed to parse %s: %v", file, err))
+			os.Exit(1)
+		}
+
+		if err := orgFile.Sort(orgmcp.NewUid(uid), options); err != nil {
+			logger.Error(fmt.Sprintf("Failed to sort %s: %v", uid, err))
+			os.Exit(1)
+		}
+
+		if _, err := mcp.WriteOrgFileToDisk(ctx, orgFile, file); err != nil {
+			logger.Error(fmt.Sprintf("Failed to write updated org file to disk: %v", err))
+			os.Exit(1)
+		}
+	},
+}
+
+var restoreBackupCmd = cobra.Command{
+	Use:   "restore-backup [backup]",
+	Short: "List the backups of a file or restore one",
+	Long: `
+Without an argument the backups of the file in --backup-dir are listed, the newest first.
+With the name of a backup the file is replaced with it. The current content is backed up first,
+so restoring that backup undoes the restore.
+`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := cmd.Context().Value("logger").(*slog.Logger)
+
+		file, _ := cmd.Flags().GetString("input")
+
+		if len(args) == 0 {
+			backups, err := mcp.ListBackups(file)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to list the backups of %s: %v", file, err))
+				os.Exit(1)
+			}
+
+			for _, backup := range backups {
+				fmt.Printf("%s  %s  %d bytes\n", backup.Name, backup.Time.Format("2006-01-02 15:04:05"), backup.Size)
+			}
+
+			if len(backups) == 0 {
+				fmt.Printf("No backups of %s\n", file)
+			}
+
+			return
+		}
+
+		if _, err := mcp.RestoreBackup(file, args[0]); err != nil {
+			logger.Error(fmt.Sprintf("Failed to restore %s: %v", args[0], err))
+			os.Exit(1)
+		}
+
+		fmt.Printf("Restored %s from %s\n", file, args[0])
+	},
+}
+
+var undoCmd = cobra.Command{
+	Use:   "undo",
+	Short: "Undo or redo the last changes of manage_header, manage_bullet and manage_text",
+	Long: `
+Reverts the last changes the manage_header, manage_bullet and manage_text tools made to a file, they are recorded
+in a journal next to the file. Changes made afterwards by other tools or in Emacs are kept, when an undone change
+conflicts with them nothing is written.
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logger := cmd.Context().Value("logger").(*slog.Logger)
+
+		file, _ := cmd.Flags().GetString("input")
+		count, _ := cmd.Flags().GetInt("count")
+		redo, _ := cmd.Flags().GetBool("redo")
+		list, _ := cmd.Flags().GetBool("list")
+
+		if list {
+			journal, err := mcp.LoadJournal(file)
+			if err != nil {
+				logger.Error(fmt.Sprintf("Failed to read the journal of %s: %v", file, err))
+				os.Exit(1)
+			}
+
+			for i := len(journal.Undone) - 1; i >= 0; i-- {
+				entry := journal.Undone[i]
+				fmt.Printf("redo %d  %s  %s\n", entry.ID, entry.Time.Format("2006-01-02 15:04:05"), entry.Tool)
+			}
+
+			for i := len(journal.Done) - 1; i >= 0; i-- {
+				entry := journal.Done[i]
+				fmt.Printf("undo %d  %s  %s\n", entry.ID, entry.Time.Format("2006-01-02 15:04:05"), entry.Tool)
+			}
+
+			return
+		}
+
+		replay, action := mcp.Undo, "Undid"
+		if redo {
+			replay, action = mcp.Redo, "Redid"
+		}
+
+		_, entries, err := replay(file, count)
+		if err != nil {
+			logger.Error(err.Error())
+			os.Exit(1)
+		}
+
+		for _, entry := range entries {
+			fmt.Printf("%s %d  %s  %s\n", action, entry.ID, entry.Time.Format("2006-01-02 15:04:05"), entry.Tool)
+		}
+	},
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/diff"
+)
+
+// backupTimeFormat sorts the backups of a file by time when they are sorted by name.
+const backupTimeFormat = "20060102T150405.000000000"
+
+// Backup is a copy of a file made right before it was overwritten, see Config.BackupDir.
+type Backup struct {
+	Name string
+	Path string
+	Time time.Time
+	Size int64
+}
+
+// backupDir returns the directory with the backups of a file, a relative directory is relative to the file.
+func backupDir(filePath string) (string, error) {
+	dir := orgmcp.CurrentConfig().BackupDir
+	if dir == "" {
+		return "", fmt.Errorf("backups are disabled, start org-mcp with --backup-dir to keep backups")
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(filepath.Dir(filePath), dir)
+	}
+
+	return dir, nil
+}
+
+// backupPrefix starts the name of every backup of a file, files with the same name in different directories
+// share a backup directory so the prefix includes a hash of the directory.
+func backupPrefix(filePath string) string {
+	dir := filepath.Dir(filePath)
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+
+	sum := sha256.Sum256([]byte(dir))
+
+	return filepath.Base(filePath) + "." + hex.EncodeToString(sum[:4]) + "."
+}
+
+// backupFile keeps a copy of the content of a file before it is overwritten and removes the oldest copies,
+// only Config.Backups copies are kept. It does nothing when backups are disabled.
+func backupFile(filePath string, content string) error {
+	config := orgmcp.CurrentConfig()
+	if config.BackupDir == "" || config.Backups <= 0 {
+		return nil
+	}
+
+	dir, err := backupDir(filePath)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create the backup directory: %w", err)
+	}
+
+	name := backupPrefix(filePath) + time.Now().Format(backupTimeFormat)
+	if err := writeFileAtomic(filepath.Join(dir, name), content); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", filePath, err)
+	}
+
+	backups, err := ListBackups(filePath)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range backups[min(config.Backups, len(backups)):] {
+		os.Remove(backup.Path)
+	}
+
+	return nil
+}
+
+// ListBackups returns the backups of a file, the newest first.
+func ListBackups(filePath string) ([]Backup, error) {
+	dir, err := backupDir(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	prefix := backupPrefix(filePath)
+	backups := []Backup{}
+
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), prefix)
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		t, err := time.ParseInLocation(backupTimeFormat, stamp, time.Local)
+		if err != nil {
+			continue
+		}
+
+		backup := Backup{Name: entry.Name(), Path: filepath.Join(dir, entry.Name()), Time: t}
+		if info, err := entry.Info(); err == nil {
+			backup.Size = info.Size()
+		}
+
+		backups = append(backups, backup)
+	}
+
+	slices.SortFunc(backups, func(a, b Backup) int { return strings.Compare(b.Name, a.Name) })
+
+	return backups, nil
+}
+
+// RestoreBackup replaces a file with one of its backups and returns a diff of the changes.
+// The current content is backed up first, so a restore can be undone by restoring that backup.
+func RestoreBackup(filePath string, name string) (string, error) {
+	backups, err := ListBackups(filePath)
+	if err != nil {
+		return "", err
+	}
+
+	index := slices.IndexFunc(backups, func(b Backup) bool { return b.Name == name })
+	if index == -1 {
+		return "", fmt.Errorf("no backup named %s for %s", name, filePath)
+	}
+
+	content, err := os.ReadFile(backups[index].Path)
+	if err != nil {
+		return "", err
+	}
+
+	current, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	if err == nil {
+		if err := backupFile(filePath, string(current)); err != nil {
+			return "", err
+		}
+	}
+
+	if err := writeFileAtomic(filePath, string(content)); err != nil {
+		return "", err
+	}
+
+	return diff.GetDiff(filePath, string(current), string(content)), nil
+}
@@ -0,0 +1,123 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+)
+
+func useBackups(t *testing.T, dir string, backups int) {
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.BackupDir = dir
+	config.Backups = backups
+	orgmcp.Configure(config)
+}
+
+// TestAtomicWrite tests that a write replaces the file, keeps its mode and leaves no temporary files behind
+func TestAtomicWrite(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "atomic.org")
+
+	if err := os.WriteFile(path, []byte(conflictFile), 0600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// the file is written through a symbolic link, the link stays
+	link := filepath.Join(dir, "link.org")
+	if err := os.Symlink(path, link); err != nil {
+		t.Fatalf("failed to create link: %v", err)
+	}
+
+	of, err := LoadOrgFile(context.TODO(), link)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	of.GetUid(orgmcp.NewUid(1)).Unwrap().(*orgmcp.Header).Content = "First changed"
+
+	if _, err := WriteOrgFileToDisk(context.TODO(), of, link); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+
+	if info, _ := os.Lstat(link); info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("expected the symbolic link to stay")
+	}
+
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected the mode 0600 to be kept, got %v", info.Mode().Perm())
+	}
+
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "First changed") {
+		t.Errorf("expected the change to be written, got:\n%s", content)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("expected only the file and the link, got %d entries", len(entries))
+	}
+}
+
+// TestBackups tests that only the newest backups are kept and that a restore can be undone
+func TestBackups(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "backup.org")
+	useBackups(t, ".backups", 2)
+
+	if err := os.WriteFile(path, []byte(conflictFile), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	for _, headline := range []string{"One", "Two", "Three"} {
+		of, err := LoadOrgFile(context.TODO(), path)
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+		of.GetUid(orgmcp.NewUid(1)).Unwrap().(*orgmcp.Header).Content = headline
+
+		if _, err := WriteOrgFileToDisk(context.TODO(), of, path); err != nil {
+			t.Fatalf("failed to write: %v", err)
+		}
+	}
+
+	backups, err := ListBackups(path)
+	if err != nil {
+		t.Fatalf("failed to list backups: %v", err)
+	}
+
+	if len(backups) != 2 || filepath.Dir(backups[0].Path) != filepath.Join(dir, ".backups") {
+		t.Fatalf("expected two backups in .backups next to the file, got %#v", backups)
+	}
+
+	// the newest backup is the file before the last write
+	if _, err := RestoreBackup(path, backups[0].Name); err != nil {
+		t.Fatalf("failed to restore: %v", err)
+	}
+
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "* TODO Two\n") {
+		t.Errorf("expected the restored file, got:\n%s", content)
+	}
+
+	backups, _ = ListBackups(path)
+	if _, err := RestoreBackup(path, backups[0].Name); err != nil {
+		t.Fatalf("failed to undo the restore: %v", err)
+	}
+
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "* TODO Three\n") {
+		t.Errorf("expected the restore to be undone, got:\n%s", content)
+	}
+
+	if _, err := RestoreBackup(path, "missing"); err == nil {
+		t.Errorf("expected an error for an unknown backup")
+	}
+}
@@ -0,0 +1,193 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/diff"
+)
+
+// racyWindow is how close the modification time of a file may be to the moment it was cached before its
+// content is compared as well, a write within the same tick of the file system clock keeps the old time.
+const racyWindow = 2 * time.Second
+
+// FileCache keeps parsed Org files between tool calls so a file is only parsed again after it changed on disk.
+// Before a cached file is used its modification time and size are checked, and for a file that changed
+// recently its content hash as well, so edits made in Emacs are picked up by the next call.
+//
+// A file is handed out to one tool call at a time: Load takes it out of the cache and Write or Release puts
+// it back, a file a call never puts back is parsed again, so changes of a failed call never leak into the next.
+// A nil FileCache reads and writes the file on every call.
+type FileCache struct {
+	mu    sync.Mutex
+	files map[string]*cachedFile
+}
+
+type cachedFile struct {
+	of      orgmcp.OrgFile
+	content string
+	hash    [sha256.Size]byte
+	modTime time.Time
+	size    int64
+	cached  time.Time
+	// loaded is set while a tool call holds the file
+	loaded bool
+}
+
+func NewFileCache() *FileCache {
+	return &FileCache{files: map[string]*cachedFile{}}
+}
+
+func cacheKey(filePath string) string {
+	if abs, err := filepath.Abs(filePath); err == nil {
+		return abs
+	}
+
+	return filepath.Clean(filePath)
+}
+
+// Load returns the parsed file at filePath, from the cache when the file did not change since it was cached.
+func (c *FileCache) Load(ctx context.Context, filePath string) (orgmcp.OrgFile, error) {
+	if c == nil {
+		return LoadOrgFile(ctx, filePath)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey(filePath)
+
+	if entry, ok := c.files[key]; ok && !entry.loaded && entry.valid(filePath) {
+		entry.loaded = true
+		entry.of.SetName(filePath)
+
+		return entry.of, nil
+	}
+
+	delete(c.files, key)
+
+	of, err := LoadOrgFile(ctx, filePath)
+	if err != nil {
+		return of, err
+	}
+
+	if entry, ok := newCachedFile(of, of.Base().Unwrap(), filePath); ok {
+		entry.loaded = true
+		c.files[key] = entry
+	}
+
+	return of, nil
+}
+
+// Write renders the file, writes it to filePath when its content changed and returns a diff of the changes.
+// The file is kept in the cache for the next call.
+func (c *FileCache) Write(ctx context.Context, of orgmcp.OrgFile, filePath string) (string, error) {
+	if c == nil {
+		return WriteOrgFileToDisk(ctx, of, filePath)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	key := cacheKey(filePath)
+	newContent := renderOrgFile(of)
+
+	entry, ok := c.files[key]
+	delete(c.files, key)
+
+	// nothing changed, reads do not touch the file
+	if ok && entry.valid(filePath) && entry.content == newContent {
+		entry.of = of
+		entry.loaded = false
+		c.files[key] = entry
+
+		return diff.GetDiff(filePath, newContent, newContent), nil
+	}
+
+	res, content, err := writeOrgFile(of, filePath)
+	if err != nil {
+		return res, err
+	}
+
+	// the UIDs of bullets and text depend on their content, so the written file is parsed again to cache
+	// the same file the next call would read from disk
+	written, err := orgmcp.OrgFileFromReader(ctx, strings.NewReader(content)).Split()
+	if err != nil {
+		return res, nil
+	}
+	written.SetName(filePath)
+	written.SetBase(content)
+
+	if entry, ok := newCachedFile(written, content, filePath); ok {
+		c.files[key] = entry
+	}
+
+	return res, nil
+}
+
+// Release hands back a file that was loaded only to be read, so the next call can use it again.
+func (c *FileCache) Release(filePath string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.files[cacheKey(filePath)]; ok {
+		entry.loaded = false
+	}
+}
+
+// Invalidate drops a file from the cache, the next Load parses it again.
+func (c *FileCache) Invalidate(filePath string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.files, cacheKey(filePath))
+}
+
+func newCachedFile(of orgmcp.OrgFile, content string, filePath string) (*cachedFile, bool) {
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, false
+	}
+
+	return &cachedFile{
+		of:      of,
+		content: content,
+		hash:    sha256.Sum256([]byte(content)),
+		modTime: info.ModTime(),
+		size:    info.Size(),
+		cached:  time.Now(),
+	}, true
+}
+
+// valid reports whether the file on disk is still the file that was cached.
+func (f *cachedFile) valid(filePath string) bool {
+	info, err := os.Stat(filePath)
+	if err != nil || !info.ModTime().Equal(f.modTime) || info.Size() != f.size {
+		return false
+	}
+
+	if f.modTime.Before(f.cached.Add(-racyWindow)) {
+		return true
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return false
+	}
+
+	return sha256.Sum256(content) == f.hash
+}
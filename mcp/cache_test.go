@@ -0,0 +1,117 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+)
+
+func firstHeadline(t *testing.T, of orgmcp.OrgFile) *orgmcp.Header {
+	t.Helper()
+
+	for _, child := range of.Children() {
+		if header, ok := child.(*orgmcp.Header); ok {
+			return header
+		}
+	}
+
+	t.Fatalf("expected the file to have a header")
+	return nil
+}
+
+func TestFileCache(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	path := filepath.Join(t.TempDir(), "cache.org")
+	if err := os.WriteFile(path, []byte("* TODO First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	// pretend the file was written a while ago, so the cache does not need to compare the content
+	old := time.Now().Add(-time.Minute)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatalf("failed to set the modification time: %v", err)
+	}
+	written, _ := os.Stat(path)
+
+	ctx := context.TODO()
+	cache := NewFileCache()
+
+	of, err := cache.Load(ctx, path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	header := firstHeadline(t, of)
+
+	// a file that is still loaded is parsed again
+	other, err := cache.Load(ctx, path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if firstHeadline(t, other) == header {
+		t.Errorf("expected a file that is still loaded not to be handed out twice")
+	}
+
+	// nothing changed, the file is not written and stays cached
+	if _, err := cache.Write(ctx, of, path); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if info, _ := os.Stat(path); !info.ModTime().Equal(written.ModTime()) {
+		t.Errorf("expected an unchanged file not to be written")
+	}
+
+	of, err = cache.Load(ctx, path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if firstHeadline(t, of) != header {
+		t.Errorf("expected the cached file to be reused")
+	}
+
+	// a change made by the tool is written and cached
+	header.Content = "Second"
+	if _, err := cache.Write(ctx, of, path); err != nil {
+		t.Fatalf("failed to write: %v", err)
+	}
+	if content, _ := os.ReadFile(path); !strings.Contains(string(content), "Second") {
+		t.Errorf("expected the change to be written, got %s", content)
+	}
+
+	// the written file is cached as it would be read from disk
+	of, err = cache.Load(ctx, path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	header = firstHeadline(t, of)
+	if header.Content != "Second" {
+		t.Errorf("expected the written file to be cached, got '%s'", header.Content)
+	}
+	cache.Release(path)
+
+	if of, _ = cache.Load(ctx, path); firstHeadline(t, of) != header {
+		t.Errorf("expected a released file to be reused")
+	}
+	cache.Release(path)
+
+	// a change made outside of the server with the same size and modification time is found by its hash
+	info, _ := os.Stat(path)
+	if err := os.WriteFile(path, []byte(strings.Replace(renderOrgFile(of), "Second", "Fourth", 1)), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.Chtimes(path, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatalf("failed to set the modification time: %v", err)
+	}
+
+	of, err = cache.Load(ctx, path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	if got := firstHeadline(t, of); got == header || got.Content != "Fourth" {
+		t.Errorf("expected the changed file to be parsed again, got '%s'", got.Content)
+	}
+}
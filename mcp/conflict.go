@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// ErrorData is implemented by errors that carry structured data for the client,
+// it is sent as the data of the JSON-RPC error.
+type ErrorData interface {
+	error
+	Data() map[string]any
+}
+
+// ConflictError is returned when a file changed on disk after it was read and the changes of the tool
+// could not be merged with it. The file on disk is left as it is.
+type ConflictError struct {
+	Path string
+	// ExpectedHash is the hash of the content the file was read from, ActualHash the hash of the file on disk.
+	// ActualHash is empty when the file was removed.
+	ExpectedHash string
+	ActualHash   string
+	// Merged is the result of the three-way merge, the conflicts are marked like git does.
+	Merged    string
+	Conflicts int
+}
+
+func (e *ConflictError) Error() string {
+	if e.ActualHash == "" {
+		return fmt.Sprintf("%s was removed after it was read, nothing was written", e.Path)
+	}
+
+	return fmt.Sprintf("%s changed on disk after it was read and %d change(s) conflict, nothing was written. "+
+		"Read the file again and repeat the operation", e.Path, e.Conflicts)
+}
+
+func (e *ConflictError) Data() map[string]any {
+	return map[string]any{
+		"type":          "conflict",
+		"path":          e.Path,
+		"expected_hash": e.ExpectedHash,
+		"actual_hash":   e.ActualHash,
+		"conflicts":     e.Conflicts,
+		"merged":        e.Merged,
+	}
+}
+
+// LockedError is returned when a file is locked by Emacs and Config.RespectLocks is set.
+type LockedError struct {
+	Path  string
+	Owner string
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s has unsaved changes in Emacs (locked by %s), nothing was written", e.Path, e.Owner)
+}
+
+func (e *LockedError) Data() map[string]any {
+	return map[string]any{
+		"type":  "locked",
+		"path":  e.Path,
+		"owner": e.Owner,
+	}
+}
+
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// LockOwner returns the owner of the Emacs lock file of a file, like user@host.1234.
+// Emacs creates the lock file .#name next to a file as soon as its buffer has unsaved changes,
+// as a symbolic link or, where those are not available, as a regular file.
+func LockOwner(filePath string) option.Option[string] {
+	lockPath := filepath.Join(filepath.Dir(filePath), ".#"+filepath.Base(filePath))
+
+	target, err := os.Readlink(lockPath)
+	if err != nil {
+		content, err := os.ReadFile(lockPath)
+		if err != nil {
+			return option.None[string]()
+		}
+
+		target = string(content)
+	}
+
+	// the target is user@host.pid:boot-time
+	owner, _, _ := strings.Cut(strings.TrimSpace(target), ":")
+
+	return option.Some(owner)
+}
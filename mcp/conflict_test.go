@@ -0,0 +1,208 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+)
+
+const conflictFile = "* TODO First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n" +
+	"* TODO Second\n  :PROPERTIES:\n  :ID: 2\n  :END:\n" +
+	"* TODO Third\n  :PROPERTIES:\n  :ID: 3\n  :END:\n"
+
+// TestWriteConflict tests that a file changed on disk after it was read is merged or not written at all
+func TestWriteConflict(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	ctx := context.TODO()
+	path := filepath.Join(t.TempDir(), "conflict.org")
+
+	load := func() orgmcp.OrgFile {
+		if err := os.WriteFile(path, []byte(conflictFile), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		of, err := LoadOrgFile(ctx, path)
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+
+		return of
+	}
+
+	// an edit in Emacs while the tool runs
+	saveInEmacs := func(old, new string) string {
+		content := strings.Replace(conflictFile, old, new, 1)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		return content
+	}
+
+	t.Run("Merged", func(t *testing.T) {
+		of := load()
+		of.GetUid(orgmcp.NewUid(1)).Unwrap().(*orgmcp.Header).Content = "First changed"
+		saveInEmacs("TODO Third", "DONE Third")
+
+		if _, err := WriteOrgFileToDisk(ctx, of, path); err != nil {
+			t.Fatalf("expected the changes to be merged, got %v", err)
+		}
+
+		content, _ := os.ReadFile(path)
+		if !strings.Contains(string(content), "* TODO First changed\n") || !strings.Contains(string(content), "* DONE Third\n") {
+			t.Errorf("expected both changes in the file, got:\n%s", content)
+		}
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		of := load()
+		expected := saveInEmacs("TODO Third", "DONE Third")
+
+		if _, err := WriteOrgFileToDisk(ctx, of, path); err != nil {
+			t.Fatalf("expected no error for a tool that changed nothing, got %v", err)
+		}
+
+		if content, _ := os.ReadFile(path); string(content) != expected {
+			t.Errorf("expected the edit from Emacs to be kept, got:\n%s", content)
+		}
+	})
+
+	t.Run("Normalized", func(t *testing.T) {
+		// the render of a file without IDs differs from the file, but a read does not change it
+		content := "* TODO First\n* TODO Second\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		cache := NewFileCache()
+		of, err := cache.Load(ctx, path)
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+
+		expected := strings.Replace(content, "TODO First", "TODO First renamed", 1)
+		if err := os.WriteFile(path, []byte(expected), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if _, err := cache.Write(ctx, of, path); err != nil {
+			t.Fatalf("expected no error for a tool that changed nothing, got %v", err)
+		}
+
+		if content, _ := os.ReadFile(path); string(content) != expected {
+			t.Errorf("expected the edit from Emacs to be kept, got:\n%s", content)
+		}
+	})
+
+	t.Run("MergedWithoutIds", func(t *testing.T) {
+		// rendering drops the blank lines and the edited header gets its ID written,
+		// neither may count as a change next to the rename on disk
+		content := "* One\n\n* Two\n\n* Three\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		of, err := LoadOrgFile(ctx, path)
+		if err != nil {
+			t.Fatalf("failed to load: %v", err)
+		}
+
+		header := of.Children()[0].(*orgmcp.Header)
+		header.SetContent("One changed")
+
+		renamed := strings.Replace(content, "* Three", "* Three renamed", 1)
+		if err := os.WriteFile(path, []byte(renamed), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if _, err := WriteOrgFileToDisk(ctx, of, path); err != nil {
+			t.Fatalf("expected the changes to be merged, got %v", err)
+		}
+
+		expected := "* One changed\n  :PROPERTIES:\n  :ID: " + header.Uid().String() + "\n  :END:\n* Two\n* Three renamed\n"
+		if content, _ := os.ReadFile(path); string(content) != expected {
+			t.Errorf("expected both changes in the file\nExpected:\n%s\nGot:\n%s", expected, content)
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		of := load()
+		of.GetUid(orgmcp.NewUid(2)).Unwrap().(*orgmcp.Header).Content = "Second changed"
+		expected := saveInEmacs("TODO Second", "DONE Second")
+
+		_, err := WriteOrgFileToDisk(ctx, of, path)
+
+		var conflict *ConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected a conflict error, got %v", err)
+		}
+
+		if conflict.Conflicts != 1 || conflict.ExpectedHash == conflict.ActualHash ||
+			!strings.Contains(conflict.Merged, "<<<<<<< org-mcp\n* TODO Second changed\n") {
+			t.Errorf("unexpected conflict: %#v", conflict.Data())
+		}
+
+		if content, _ := os.ReadFile(path); string(content) != expected {
+			t.Errorf("expected the file not to be written on a conflict, got:\n%s", content)
+		}
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		of := load()
+		os.Remove(path)
+
+		var conflict *ConflictError
+		if _, err := WriteOrgFileToDisk(ctx, of, path); !errors.As(err, &conflict) {
+			t.Fatalf("expected a conflict error for a removed file, got %v", err)
+		}
+	})
+}
+
+// TestEmacsLock tests that the lock file of Emacs is found and that a locked file is not written with RespectLocks
+func TestEmacsLock(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	ctx := context.TODO()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "locked.org")
+
+	if err := os.WriteFile(path, []byte(conflictFile), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if LockOwner(path).IsSome() {
+		t.Fatalf("expected no lock owner without a lock file")
+	}
+
+	if err := os.Symlink("sam@laptop.4242:1760000000", filepath.Join(dir, ".#locked.org")); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+
+	if owner := LockOwner(path).UnwrapOr(""); owner != "sam@laptop.4242" {
+		t.Errorf("expected the lock owner sam@laptop.4242, got '%s'", owner)
+	}
+
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.RespectLocks = true
+	orgmcp.Configure(config)
+
+	of, err := LoadOrgFile(ctx, path)
+	if err != nil {
+		t.Fatalf("failed to load: %v", err)
+	}
+	of.GetUid(orgmcp.NewUid(1)).Unwrap().(*orgmcp.Header).Content = "First changed"
+
+	var locked *LockedError
+	if _, err := WriteOrgFileToDisk(ctx, of, path); !errors.As(err, &locked) {
+		t.Errorf("expected a locked error, got %v", err)
+	}
+}
@@ -0,0 +1,199 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/diff"
+)
+
+// JournalEntry is one tool call that changed a file. The entry only keeps the change as a patch,
+// the content of the file before and after the call is rebuilt from the head of the journal.
+type JournalEntry struct {
+	ID   int       `json:"id"`
+	Tool string    `json:"tool"`
+	Time time.Time `json:"time"`
+	// Gap changes the file after the previous entry into the file before this one,
+	// for changes made in between by tools that are not journaled or in Emacs.
+	Gap diff.Patch `json:"gap,omitempty"`
+	// Change changes the file before the call into the file after it.
+	Change diff.Patch `json:"change"`
+}
+
+// Journal holds the changes of a file that can be undone and the undone changes that can be redone.
+// It is stored next to the file, so changes can be undone after a restart of the server.
+//
+// Only Head is a full copy of the file, the content after the newest change that can be undone.
+// Every other state of the file is rebuilt from it with the patches of the entries, so the size of
+// the journal grows with the size of the changes and not with the size of the file.
+type Journal struct {
+	// Done is ordered from the oldest to the newest change.
+	Done []JournalEntry `json:"done"`
+	// Undone is ordered from the first undone to the last undone change, redo takes the last one.
+	Undone []JournalEntry `json:"undone"`
+	Head   string         `json:"head"`
+	NextID int            `json:"next_id"`
+}
+
+// journalPath returns the path of the journal of a file, a hidden file next to it.
+func journalPath(filePath string) string {
+	return filepath.Join(filepath.Dir(filePath), "."+filepath.Base(filePath)+".journal.json")
+}
+
+// LoadJournal reads the journal of a file, a file without a journal has an empty one.
+func LoadJournal(filePath string) (Journal, error) {
+	journal := Journal{NextID: 1}
+
+	content, err := os.ReadFile(journalPath(filePath))
+	if os.IsNotExist(err) {
+		return journal, nil
+	} else if err != nil {
+		return journal, err
+	}
+
+	if err := json.Unmarshal(content, &journal); err != nil {
+		return journal, fmt.Errorf("the journal of %s is damaged: %w", filePath, err)
+	}
+
+	return journal, nil
+}
+
+func (j *Journal) save(filePath string) error {
+	content, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(journalPath(filePath), string(content))
+}
+
+// Record adds a tool call that changed a file from before to after to the journal of the file.
+// A new change can not be redone after, so the undone changes are dropped. Only the newest
+// Config.JournalSize changes are kept, nothing is recorded when it is 0.
+func Record(filePath string, tool string, before string, after string) error {
+	size := orgmcp.CurrentConfig().JournalSize
+	if size <= 0 || before == after {
+		return nil
+	}
+
+	journal, err := LoadJournal(filePath)
+	if err != nil {
+		return err
+	}
+
+	entry := JournalEntry{
+		ID:     journal.NextID,
+		Tool:   tool,
+		Time:   time.Now(),
+		Change: diff.NewPatch(before, after),
+	}
+
+	if len(journal.Done) > 0 {
+		entry.Gap = diff.NewPatch(journal.Head, before)
+	}
+
+	journal.Done = append(journal.Done, entry)
+	journal.Done = journal.Done[max(0, len(journal.Done)-size):]
+	// the state before the oldest entry is never rebuilt
+	journal.Done[0].Gap = nil
+	journal.Undone = nil
+	journal.Head = after
+	journal.NextID++
+
+	return journal.save(filePath)
+}
+
+// Undo reverts the last count changes of the journal and returns a diff of the file and the reverted entries.
+// Changes made after an entry, by tools that are not journaled or in Emacs, are kept: every entry is reverted
+// with a three-way merge. When one of them conflicts nothing is written and a *ConflictError is returned.
+func Undo(filePath string, count int) (string, []JournalEntry, error) {
+	return replay(filePath, count, true)
+}
+
+// Redo applies the last count undone changes again, like Undo it merges them with the current file.
+func Redo(filePath string, count int) (string, []JournalEntry, error) {
+	return replay(filePath, count, false)
+}
+
+func replay(filePath string, count int, undo bool) (string, []JournalEntry, error) {
+	journal, err := LoadJournal(filePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	from, to := &journal.Done, &journal.Undone
+	if !undo {
+		from, to = to, from
+	}
+
+	if count <= 0 {
+		count = 1
+	}
+
+	if len(*from) < count {
+		action := "undo"
+		if !undo {
+			action = "redo"
+		}
+
+		return "", nil, fmt.Errorf("can not %s %d change(s) of %s, only %d are recorded", action, count, filePath, len(*from))
+	}
+
+	old, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", nil, err
+	}
+
+	content := string(old)
+	entries := []JournalEntry{}
+
+	for range count {
+		entry := (*from)[len(*from)-1]
+
+		// the head is the file after the newest done entry, undone entries follow it in the order they are redone
+		var base, target string
+		if undo {
+			base = journal.Head
+			target = entry.Change.Revert(base)
+			journal.Head = entry.Gap.Revert(target)
+		} else {
+			base = entry.Gap.Apply(journal.Head)
+			target = entry.Change.Apply(base)
+			journal.Head = target
+		}
+
+		merged, conflicts := diff.Merge3(base, target, content, "journal", "disk")
+		if conflicts > 0 {
+			return "", nil, &ConflictError{
+				Path:         filePath,
+				ExpectedHash: contentHash(base),
+				ActualHash:   contentHash(content),
+				Merged:       merged,
+				Conflicts:    conflicts,
+			}
+		}
+
+		content = merged
+		entries = append(entries, entry)
+		*from = (*from)[:len(*from)-1]
+		*to = append(*to, entry)
+	}
+
+	if err := backupFile(filePath, string(old)); err != nil {
+		return "", nil, err
+	}
+
+	if err := writeFileAtomic(filePath, content); err != nil {
+		return "", nil, err
+	}
+
+	if err := journal.save(filePath); err != nil {
+		return "", nil, err
+	}
+
+	return diff.GetDiff(filePath, string(old), content), entries, nil
+}
@@ -0,0 +1,163 @@
+package mcp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/orgmcp"
+)
+
+func useJournal(t *testing.T, size int) {
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.JournalSize = size
+	orgmcp.Configure(config)
+}
+
+// TestJournal tests that recorded changes are undone and redone and that later changes are kept
+func TestJournal(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useJournal(t, 2)
+
+	path := filepath.Join(t.TempDir(), "journal.org")
+
+	// change writes the file like a tool would and records it
+	content := conflictFile
+	change := func(tool string, old string, new string) {
+		changed := strings.Replace(content, old, new, 1)
+		if err := os.WriteFile(path, []byte(changed), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+
+		if err := Record(path, tool, content, changed); err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+
+		content = changed
+	}
+
+	expectFile := func(expected string) {
+		t.Helper()
+
+		if got, _ := os.ReadFile(path); string(got) != expected {
+			t.Errorf("unexpected file\nExpected:\n%s\nGot:\n%s", expected, got)
+		}
+	}
+
+	change("manage_header", "TODO First", "DONE First")
+	change("manage_header", "TODO Second", "DONE Second")
+	change("manage_text", "TODO Third", "DONE Third")
+
+	// only the two newest changes are kept
+	if journal, _ := LoadJournal(path); len(journal.Done) != 2 || journal.Done[0].ID != 2 {
+		t.Fatalf("expected the journal to keep the changes 2 and 3, got %#v", journal.Done)
+	}
+
+	// a change made in Emacs after the recorded changes is kept
+	emacs := strings.Replace(content, "* DONE First", "* DONE [#A] First", 1)
+	if err := os.WriteFile(path, []byte(emacs), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	_, entries, err := Undo(path, 2)
+	if err != nil {
+		t.Fatalf("failed to undo: %v", err)
+	}
+
+	if len(entries) != 2 || entries[0].ID != 3 || entries[1].ID != 2 {
+		t.Errorf("expected the changes 3 and 2 to be undone, got %#v", entries)
+	}
+
+	expectFile(strings.Replace(conflictFile, "TODO First", "DONE [#A] First", 1))
+
+	if _, _, err := Undo(path, 1); err == nil {
+		t.Errorf("expected an error when undoing more changes than recorded")
+	}
+
+	if _, _, err := Redo(path, 1); err != nil {
+		t.Fatalf("failed to redo: %v", err)
+	}
+
+	expectFile(strings.Replace(strings.Replace(conflictFile, "TODO First", "DONE [#A] First", 1), "TODO Second", "DONE Second", 1))
+
+	// an undone change that conflicts with a later change is not written
+	conflicting := strings.Replace(conflictFile, "TODO Second", "WAIT Second", 1)
+	if err := os.WriteFile(path, []byte(conflicting), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	var conflict *ConflictError
+	if _, _, err := Undo(path, 1); !errors.As(err, &conflict) {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+
+	expectFile(conflicting)
+
+	if journal, _ := LoadJournal(path); len(journal.Done) != 1 || len(journal.Undone) != 1 {
+		t.Errorf("expected a failed undo not to change the journal, got %#v", journal)
+	}
+}
+
+// TestJournalPatches tests that the journal keeps patches instead of copies of a large file
+// and that changes made between recorded changes are kept when they are undone
+func TestJournalPatches(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useJournal(t, 50)
+
+	path := filepath.Join(t.TempDir(), "large.org")
+
+	builder := strings.Builder{}
+	for i := range 3000 {
+		fmt.Fprintf(&builder, "* TODO Task %d\n", i)
+	}
+
+	original := builder.String()
+	content := original
+
+	for i := range 20 {
+		// a change by a tool that is not journaled
+		content = strings.Replace(content, fmt.Sprintf("* TODO Task %d\n", 1000+i), fmt.Sprintf("* WAIT Task %d\n", 1000+i), 1)
+
+		changed := strings.Replace(content, fmt.Sprintf("* TODO Task %d\n", i), fmt.Sprintf("* DONE Task %d\n", i), 1)
+		if err := Record(path, "manage_header", content, changed); err != nil {
+			t.Fatalf("failed to record: %v", err)
+		}
+
+		content = changed
+	}
+
+	if info, err := os.Stat(journalPath(path)); err != nil || info.Size() > int64(2*len(original)) {
+		t.Errorf("expected the journal to hold one copy of the file and patches, got %d bytes", info.Size())
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	if _, _, err := Undo(path, 20); err != nil {
+		t.Fatalf("failed to undo: %v", err)
+	}
+
+	expected := original
+	for i := range 20 {
+		expected = strings.Replace(expected, fmt.Sprintf("* TODO Task %d\n", 1000+i), fmt.Sprintf("* WAIT Task %d\n", 1000+i), 1)
+	}
+
+	if got, _ := os.ReadFile(path); string(got) != expected {
+		t.Errorf("expected every recorded change to be undone and the others to be kept")
+	}
+
+	if _, _, err := Redo(path, 20); err != nil {
+		t.Fatalf("failed to redo: %v", err)
+	}
+
+	if got, _ := os.ReadFile(path); string(got) != content {
+		t.Errorf("expected every recorded change to be redone")
+	}
+}
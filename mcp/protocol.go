@@ -170,6 +170,19 @@ func (ms *MessageSender) SendError(id any, code int, message string) error {
 	})
 }
 
+// SendErrorData sends a JSON-RPC error response with structured data about the error
+func (ms *MessageSender) SendErrorData(id any, code int, message string, data any) error {
+	return ms.Send(JSONRPCMessage{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error: map[string]any{
+			"code":    code,
+			"message": message,
+			"data":    data,
+		},
+	})
+}
+
 // SendNotification sends a JSON-RPC notification (no ID)
 func (ms *MessageSender) SendNotification(method string, params any) error {
 	return ms.Send(JSONRPCMessage{
@@ -134,6 +134,11 @@ func generateTypeSchema(t reflect.Type) map[string]any {
 						key, val := kv[0], kv[1]
 						switch key {
 						case "description":
+							// a type with its own schema can describe its values, that goes after the field
+							if typeDescription, ok := fieldSchema["description"].(string); ok && typeDescription != "" {
+								val += " " + typeDescription
+							}
+
 							fieldSchema["description"] = val
 						case "required":
 							isRequired = (val == "true")
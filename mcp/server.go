@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -17,6 +18,8 @@ import (
 type FuncOptions struct {
 	DefaultPath string
 	Logger      *slog.Logger
+	// Files is the cache of parsed files shared by all tool calls, when nil files are read on every call.
+	Files *FileCache
 }
 
 type ToolFunc func(map[string]any, FuncOptions) ([]any, error)
@@ -28,6 +31,7 @@ type Server struct {
 	log       *slog.Logger
 	state     ServerState
 	workspace string
+	files     *FileCache
 
 	tools map[string]McpTool
 }
@@ -77,6 +81,7 @@ func NewServer(reader io.Reader, sender *MessageSender, logger *slog.Logger) *Se
 			Initialized: false,
 		},
 		workspace: *workspace,
+		files:     NewFileCache(),
 		tools:     map[string]McpTool{},
 	}
 }
@@ -156,7 +161,7 @@ func (s *Server) handleToolCall(ctx context.Context, id any, params json.RawMess
 	if tool := s.tools[toolCall.Name]; tool != nil {
 		startTime := time.Now()
 
-		resp, error := tool.Execute(ctx, toolCall.Arguments, FuncOptions{DefaultPath: default_path, Logger: s.log})
+		resp, error := tool.Execute(ctx, toolCall.Arguments, FuncOptions{DefaultPath: default_path, Logger: s.log, Files: s.files})
 
 		if error != nil {
 			s.log.Warn(fmt.Sprintf("Tool error: %v", error))
@@ -164,7 +169,10 @@ func (s *Server) handleToolCall(ctx context.Context, id any, params json.RawMess
 
 		s.log.Info(fmt.Sprintf("Tool executed in %v, response: %v", time.Since(startTime), resp))
 
-		if error != nil {
+		var withData ErrorData
+		if errors.As(error, &withData) {
+			s.sender.SendErrorData(id, -32000, fmt.Sprintf("Tool error: %v", error), withData.Data())
+		} else if error != nil {
 			s.sender.SendError(id, -32000, fmt.Sprintf("Tool error: %v", error))
 		} else {
 			s.sender.SendMcpContent(id, resp)
@@ -3,55 +3,203 @@ package mcp
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/p3rtang/org-mcp/orgmcp"
 	"github.com/p3rtang/org-mcp/utils/diff"
+	"github.com/p3rtang/org-mcp/utils/option"
 )
 
 // LoadOrgFile loads an OrgFile from the given file path.
-// It opens the file, reads it using OrgFileFromReader, and returns the result.
+// It reads the file, parses it using OrgFileFromReader and records the content as the base of the file,
+// so a later write can tell whether the file changed on disk in the meantime.
 func LoadOrgFile(ctx context.Context, filePath string) (orgmcp.OrgFile, error) {
-	file, err := os.Open(filePath)
+	content, err := os.ReadFile(filePath)
 	if err != nil {
 		return orgmcp.OrgFile{}, err
 	}
-	defer file.Close()
 
-	of, err := orgmcp.OrgFileFromReader(ctx, file).Split()
+	of, err := orgmcp.OrgFileFromReader(ctx, strings.NewReader(string(content))).Split()
 	of.SetName(filePath)
+	of.SetBase(string(content))
 
 	return of, err
 }
 
 // writeOrgFileToDisk renders the OrgFile and writes it to the provided file path.
 // It returns a diff of the changes made to the file.
+//
+// The file is replaced atomically and, when backups are enabled, the old content is backed up first.
+//
+// When the file changed on disk since it was loaded, the changes are merged with a three-way merge,
+// if they conflict nothing is written and a *ConflictError is returned.
 func WriteOrgFileToDisk(ctx context.Context, of orgmcp.OrgFile, filePath string) (res string, err error) {
-	oldContent, err := os.ReadFile(filePath)
+	res, _, err = writeOrgFile(of, filePath)
+	return
+}
+
+// CheckWrite returns the error writing the file would fail with because of a conflict or a lock, without writing it.
+// A change of several files checks all of them first, so it does not stop after some of them were written.
+func CheckWrite(of orgmcp.OrgFile, filePath string) error {
+	_, _, err := prepareWrite(of, filePath)
+	return err
+}
+
+// writeOrgFile is WriteOrgFileToDisk, it also returns the content of the file after the write.
+func writeOrgFile(of orgmcp.OrgFile, filePath string) (res string, written string, err error) {
+	old, newContent, err := prepareWrite(of, filePath)
+	if err != nil {
+		return
+	}
+
+	oldContent, exists := old.Split()
+	if exists && newContent == oldContent {
+		return diff.GetDiff(filePath, oldContent, newContent), newContent, nil
+	}
+
+	if exists {
+		if err = backupFile(filePath, oldContent); err != nil {
+			return
+		}
+	}
+
+	if err = writeFileAtomic(filePath, newContent); err != nil {
+		return
+	}
+
+	return diff.GetDiff(filePath, oldContent, newContent), newContent, nil
+}
+
+// prepareWrite returns the content on disk, None when the file does not exist, and the content to write,
+// merged with the changes made on disk since the file was loaded.
+func prepareWrite(of orgmcp.OrgFile, filePath string) (old option.Option[string], newContent string, err error) {
+	content, err := os.ReadFile(filePath)
+	exists := err == nil
 	if err != nil && !os.IsNotExist(err) {
 		return
 	}
 
-	file, err := os.OpenFile(filePath, os.O_WRONLY|os.O_TRUNC|os.O_CREATE, 0666)
-	if err != nil {
+	err = nil
+	oldContent := string(content)
+	newContent = renderOrgFile(of)
+
+	if exists {
+		old = option.Some(oldContent)
+	}
+
+	if base, ok := of.Base().Split(); ok && oldContent != base {
+		if !exists {
+			return old, "", &ConflictError{Path: filePath, ExpectedHash: contentHash(base)}
+		}
+
+		// nothing to merge when the tool did not change the file, normalizing it on render is no change
+		if newContent == base || newContent == of.BaseRender() {
+			newContent = oldContent
+		} else {
+			// the change of the tool is what it did to the rendered file, so it is merged into the rendered
+			// file on disk, lines that only differ because rendering normalizes them are no edits
+			merged, conflicts := diff.Merge3(of.BaseRender(), newContent, renderContent(oldContent), "org-mcp", "disk")
+			if conflicts > 0 {
+				return old, "", &ConflictError{
+					Path:         filePath,
+					ExpectedHash: contentHash(base),
+					ActualHash:   contentHash(oldContent),
+					Merged:       merged,
+					Conflicts:    conflicts,
+				}
+			}
+
+			newContent = merged
+		}
+	}
+
+	if exists && newContent == oldContent {
 		return
 	}
-	defer file.Close()
 
-	builder := strings.Builder{}
-	of.Render(&builder, -1)
-	newContent := builder.String()
+	if owner, ok := LockOwner(filePath).Split(); ok && orgmcp.CurrentConfig().RespectLocks {
+		return old, "", &LockedError{Path: filePath, Owner: owner}
+	}
+
+	return
+}
 
-	if !strings.HasSuffix(newContent, "\n") {
-		newContent += "\n"
+// writeFileAtomic writes the content to a temporary file next to filePath and renames it over the file,
+// so a crash leaves either the old or the new file and never a truncated one. The mode of the file is kept.
+func writeFileAtomic(filePath string, content string) (err error) {
+	// replace the target of a symbolic link, not the link
+	if target, err := filepath.EvalSymlinks(filePath); err == nil {
+		filePath = target
 	}
 
-	_, err = file.WriteString(newContent)
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(filePath); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(filePath)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
 	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = tmp.WriteString(content); err != nil {
+		return
+	}
+
+	if err = tmp.Chmod(mode); err != nil {
 		return
 	}
 
-	res = diff.GetDiff(filePath, string(oldContent), newContent)
+	if err = tmp.Sync(); err != nil {
+		return
+	}
 
-	return
+	if err = tmp.Close(); err != nil {
+		return
+	}
+
+	if err = os.Rename(tmp.Name(), filePath); err != nil {
+		return
+	}
+
+	// make the rename itself durable, not every platform can sync a directory so errors are ignored
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+
+	return nil
+}
+
+// renderOrgFile renders the OrgFile the way it is written to disk, always ending in a newline.
+// renderContent returns how the given file content renders, or the content itself when it cannot be parsed.
+func renderContent(content string) string {
+	of, err := orgmcp.OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		return content
+	}
+
+	return renderOrgFile(of)
+}
+
+func renderOrgFile(of orgmcp.OrgFile) string {
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+	content := builder.String()
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+
+	return content
 }
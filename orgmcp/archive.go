@@ -0,0 +1,209 @@
+package orgmcp
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// DefaultArchiveLocation is the org-archive-location used when neither the header nor the file sets one,
+// top level in a file next to the original with `_archive` appended to its name.
+const DefaultArchiveLocation = "%s_archive::"
+
+// ArchiveLocation returns where a header is archived to in the format of org-archive-location, `file::heading`.
+// Like org mode the ARCHIVE property of the header or its closest ancestor wins over the `#+ARCHIVE:` keyword of the file.
+func (of *OrgFile) ArchiveLocation(header *Header) string {
+	for r := Render(header); r != nil; {
+		h, ok := r.(*Header)
+		if !ok {
+			break
+		}
+
+		if location, ok := h.GetProperty("ARCHIVE").Split(); ok {
+			return location
+		}
+
+		r = h.Parent.UnwrapOr(nil)
+	}
+
+	return of.Keyword("ARCHIVE").UnwrapOr(DefaultArchiveLocation)
+}
+
+// ArchiveTarget splits an archive location into the file and the heading to archive under.
+// `%s` in the file part is replaced by the name of the file and a relative path is relative to its directory.
+// The file is empty when the location points at this file, the heading is empty for the top level.
+func (of *OrgFile) ArchiveTarget(location string) (file string, heading string) {
+	file, heading, _ = strings.Cut(location, "::")
+	heading = strings.TrimSpace(heading)
+
+	file = strings.TrimSpace(strings.ReplaceAll(file, "%s", filepath.Base(of.name)))
+	if file == "" {
+		return
+	}
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(of.name), file)
+	}
+
+	if filepath.Clean(file) == filepath.Clean(of.name) {
+		file = ""
+	}
+
+	return
+}
+
+// Category returns the category of a header like org mode: the CATEGORY property of the header or an ancestor,
+// the `#+CATEGORY:` keyword of the file or the file name without its extension.
+func (of *OrgFile) Category(header *Header) string {
+	for r := Render(header); r != nil; {
+		h, ok := r.(*Header)
+		if !ok {
+			break
+		}
+
+		if category, ok := h.GetProperty("CATEGORY").Split(); ok {
+			return category
+		}
+
+		r = h.Parent.UnwrapOr(nil)
+	}
+
+	if category, ok := of.Keyword("CATEGORY").Split(); ok {
+		return category
+	}
+
+	base := filepath.Base(of.name)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// DoneSubtrees returns the done headers that are not inside another done header, in file order.
+// Archiving these moves every done subtree of the file. Headers that were archived before, those with an
+// ARCHIVE_TIME property or inside a header that has one, are left out so an archive heading in the same file stays as is.
+func (of *OrgFile) DoneSubtrees() (headers []*Header) {
+	for _, r := range of.ChildrenRec(-1) {
+		header, ok := r.(*Header)
+		if !ok || !header.TodoKeywords().IsDone(header.status) || archived(header) {
+			continue
+		}
+
+		if slices.ContainsFunc(headers, func(h *Header) bool { return slices.Contains(h.ChildrenRec(-1), r) }) {
+			continue
+		}
+
+		headers = append(headers, header)
+	}
+
+	return
+}
+
+// archived reports whether the header or one of its ancestors was archived before.
+func archived(header *Header) bool {
+	for r := Render(header); r != nil; {
+		h, ok := r.(*Header)
+		if !ok {
+			break
+		}
+
+		if h.GetProperty("ARCHIVE_TIME").IsSome() {
+			return true
+		}
+
+		r = h.Parent.UnwrapOr(nil)
+	}
+
+	return false
+}
+
+// Archive moves a header with its subtree under the heading of target like org-archive-subtree,
+// target can be the file itself. The header gets the ARCHIVE_TIME, ARCHIVE_FILE, ARCHIVE_OLPATH,
+// ARCHIVE_CATEGORY and ARCHIVE_TODO properties so it can be traced back to where it came from.
+// A heading like `* Archive` that does not exist yet is created at the end of target, an empty heading archives to the top level.
+func (of *OrgFile) Archive(uid Uid, target *OrgFile, heading string) error {
+	header, ok := option.Cast[Render, *Header](of.GetUid(uid)).Split()
+	if !ok {
+		return fmt.Errorf("Uid %s is not a header", uid)
+	}
+
+	title := strings.TrimSpace(strings.TrimLeft(heading, "*"))
+	level := len(heading) - len(strings.TrimLeft(heading, "*"))
+
+	archive, found := target.archiveHeading(title, level).Split()
+	if found && (archive == header || slices.Contains(header.ChildrenRec(-1), Render(archive))) {
+		return fmt.Errorf("cannot archive %s into its own subtree", uid)
+	}
+
+	if title != "" && !found && level > 1 {
+		return fmt.Errorf("archive heading '%s' not found, only a top level heading is created", heading)
+	}
+
+	olpath := []string{}
+	for r := header.Parent.UnwrapOr(nil); r != nil; {
+		h, ok := r.(*Header)
+		if !ok {
+			break
+		}
+
+		olpath = append([]string{h.Content}, olpath...)
+		r = h.Parent.UnwrapOr(nil)
+	}
+
+	file := of.name
+	if abs, err := filepath.Abs(file); err == nil {
+		file = abs
+	}
+
+	properties := [][2]string{
+		{"ARCHIVE_TIME", Now().Format("2006-01-02 Mon 15:04")},
+		{"ARCHIVE_FILE", file},
+		{"ARCHIVE_OLPATH", strings.Join(olpath, "/")},
+		{"ARCHIVE_CATEGORY", of.Category(header)},
+	}
+
+	if header.status != None {
+		properties = append(properties, [2]string{"ARCHIVE_TODO", string(header.status)})
+	}
+
+	// move to the top level first, the heading is only created once the move can not fail
+	if err := of.MoveToFile(header.Uid(), target, NewUid(0), -1); err != nil {
+		return err
+	}
+
+	if title != "" {
+		if !found {
+			created := target.NewHeader(None, title)
+			archive = &created
+			target.AddChildren(archive)
+			target.Reindex()
+		}
+
+		if err := target.MoveTo(header.Uid(), archive.Uid(), -1); err != nil {
+			return err
+		}
+	}
+
+	for _, prop := range properties {
+		if prop[1] != "" {
+			header.SetProperty(prop[0], prop[1])
+		}
+	}
+
+	return nil
+}
+
+// archiveHeading finds the header with the given title to archive under, at the given level when it is not 0.
+func (of *OrgFile) archiveHeading(title string, level int) option.Option[*Header] {
+	if title == "" {
+		return option.None[*Header]()
+	}
+
+	for _, r := range of.ChildrenRec(-1) {
+		if header, ok := r.(*Header); ok && header.Content == title && (level == 0 || header.Level() == level) {
+			return option.Some(header)
+		}
+	}
+
+	return option.None[*Header]()
+}
@@ -0,0 +1,364 @@
+package orgmcp
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+	"github.com/p3rtang/org-mcp/utils/reader"
+)
+
+var blockBeginRegex = regexp.MustCompile(`(?i)^#\+begin_(\S+)(?:\s+(.*))?$`)
+
+// lines that would be read as a headline or an in-buffer setting, escaped or not
+var needsCommaRegex = regexp.MustCompile(`^[ \t]*,*(\*|#\+)`)
+
+// lines with a comma escape, org mode removes one comma from them
+var commaEscapedRegex = regexp.MustCompile(`^[ \t]*,(,*)(\*|#\+)`)
+
+// Block is a `#+BEGIN_KIND` ... `#+END_KIND` block, e.g. a source or quote block.
+// The content of a block is kept verbatim, it is never parsed into other items.
+type Block struct {
+	kind       string
+	parameters string
+	lines      []string
+	lowercase  bool
+	indent     int
+	index      int
+
+	parent option.Option[Render]
+	source Source
+	uid    uidCache
+}
+
+// Enforce that Block implements the Render interface at compile time
+var _ Render = (*Block)(nil)
+
+// NewBlock creates a block of the given kind (SRC, EXAMPLE, QUOTE, VERSE or any custom kind).
+// The parameters are everything after the kind on the begin line, e.g. `go -n :results output`.
+func NewBlock(kind string, parameters string, content string) Block {
+	block := Block{kind: strings.ToUpper(strings.TrimSpace(kind)), parameters: strings.TrimSpace(parameters)}
+	block.SetContent(content)
+
+	return block
+}
+
+// IsBlockLine reports whether the given line (with or without indentation) starts a block.
+func IsBlockLine(line string) bool {
+	return blockBeginRegex.MatchString(strings.TrimSpace(line))
+}
+
+// NewBlockFromReader reads a block up to and including its end line.
+// When the end line is missing before the next headline or the end of the file
+// nothing is consumed and None is returned, like org mode the begin line is then just text.
+func NewBlockFromReader(r *reader.PeekReader) option.Option[*Block] {
+	bytes, err := r.PeekBytes('\n')
+	if err != nil && len(bytes) == 0 {
+		return option.None[*Block]()
+	}
+
+	matches := blockBeginRegex.FindStringSubmatch(strings.TrimSpace(string(bytes)))
+	if matches == nil {
+		return option.None[*Block]()
+	}
+
+	first, _ := r.ReadBytes('\n')
+	consumed := first
+	indent := len(first) - len(strings.TrimLeft(string(first), " "))
+	endLine := "#+end_" + strings.ToLower(matches[1])
+
+	block := Block{
+		kind:       strings.ToUpper(matches[1]),
+		parameters: strings.TrimSpace(matches[2]),
+		lowercase:  strings.HasPrefix(strings.TrimSpace(string(first)), "#+begin_"),
+		indent:     indent,
+	}
+
+	for {
+		line, err := r.ReadBytes('\n')
+		consumed = append(consumed, line...)
+
+		if IsHeaderLine(string(line)) || (err != nil && len(line) == 0) {
+			r.Unread(consumed)
+			return option.None[*Block]()
+		}
+
+		content := strings.TrimRight(string(line), "\r\n")
+
+		if strings.ToLower(strings.TrimSpace(content)) == endLine {
+			return option.Some(&block)
+		}
+
+		// strip the indentation of the block itself, but never more than the line has
+		lineIndent := len(content) - len(strings.TrimLeft(content, " "))
+		block.lines = append(block.lines, content[min(indent, lineIndent):])
+
+		if err != nil {
+			r.Unread(consumed)
+			return option.None[*Block]()
+		}
+	}
+}
+
+// Kind returns the upper case kind of the block, e.g. SRC or QUOTE.
+func (b *Block) Kind() string {
+	return b.kind
+}
+
+// Parameters returns everything after the kind on the begin line.
+func (b *Block) Parameters() string {
+	return b.parameters
+}
+
+func (b *Block) SetParameters(parameters string) {
+	b.parameters = strings.TrimSpace(parameters)
+}
+
+// Language returns the language of a source block, e.g. `go` for `#+BEGIN_SRC go -n`.
+func (b *Block) Language() option.Option[string] {
+	if b.kind != "SRC" {
+		return option.None[string]()
+	}
+
+	fields := strings.Fields(b.parameters)
+	if len(fields) == 0 || strings.HasPrefix(fields[0], "-") || strings.HasPrefix(fields[0], ":") {
+		return option.None[string]()
+	}
+
+	return option.Some(fields[0])
+}
+
+// Switches returns the switches of the block like `-n` or `-l "(ref:%s)"`, header arguments are not included.
+func (b *Block) Switches() (switches []string) {
+	fields := strings.Fields(b.parameters)
+
+	for i := 0; i < len(fields); i++ {
+		if strings.HasPrefix(fields[i], ":") {
+			// header arguments always come after the switches
+			break
+		}
+
+		if !strings.HasPrefix(fields[i], "-") && !strings.HasPrefix(fields[i], "+") {
+			continue
+		}
+
+		switches = append(switches, fields[i])
+
+		// the label format of -l is passed as a quoted argument
+		if fields[i] == "-l" && i+1 < len(fields) && strings.HasPrefix(fields[i+1], "\"") {
+			switches[len(switches)-1] += " " + fields[i+1]
+			i++
+		}
+	}
+
+	return
+}
+
+// Content returns the content of the block with the org mode comma escapes removed.
+func (b *Block) Content() string {
+	lines := make([]string, len(b.lines))
+
+	for i, line := range b.lines {
+		if commaEscapedRegex.MatchString(line) {
+			comma := strings.Index(line, ",")
+			line = line[:comma] + line[comma+1:]
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// SetContent replaces the content of the block, lines that would be read as a headline
+// or an in-buffer setting are escaped with a comma like org mode does.
+func (b *Block) SetContent(content string) {
+	b.lines = []string{}
+
+	if content == "" {
+		return
+	}
+
+	for line := range strings.SplitSeq(strings.TrimRight(content, "\n"), "\n") {
+		if needsCommaRegex.MatchString(line) {
+			trimmed := strings.TrimLeft(line, " \t")
+			line = line[:len(line)-len(trimmed)] + "," + trimmed
+		}
+
+		b.lines = append(b.lines, line)
+	}
+}
+
+func (b *Block) marker(marker string) string {
+	if b.lowercase {
+		return "#+" + strings.ToLower(marker) + "_" + strings.ToLower(b.kind)
+	}
+
+	return "#+" + marker + "_" + b.kind
+}
+
+func (b *Block) CheckProgress() option.Option[Progress] {
+	return option.None[Progress]()
+}
+
+func (b *Block) Render(builder *strings.Builder, depth int) {
+	b.source.write(builder, b.render)
+
+	if depth != 0 {
+		b.source.writeTrailing(builder)
+	}
+}
+
+func (b *Block) sourceRef() *Source {
+	return &b.source
+}
+
+func (b *Block) seal() {
+	b.source.seal(b.render)
+}
+
+func (b *Block) render(builder *strings.Builder) {
+	indent := strings.Repeat(" ", b.indent)
+
+	builder.WriteString(indent)
+	builder.WriteString(b.marker("BEGIN"))
+
+	if b.parameters != "" {
+		builder.WriteRune(' ')
+		builder.WriteString(b.parameters)
+	}
+
+	builder.WriteRune('\n')
+
+	for _, line := range b.lines {
+		if line != "" {
+			builder.WriteString(indent)
+			builder.WriteString(line)
+		}
+
+		builder.WriteRune('\n')
+	}
+
+	builder.WriteString(indent)
+	builder.WriteString(b.marker("END"))
+	builder.WriteRune('\n')
+}
+
+func (b *Block) IndentLevel() int {
+	return option.Map(b.parent, func(r Render) int {
+		return r.ChildIndentLevel()
+	}).UnwrapOr(0)
+}
+
+func (b *Block) ChildIndentLevel() int {
+	return b.IndentLevel()
+}
+
+func (b *Block) Level() int {
+	return option.Map(b.parent, func(r Render) int {
+		return r.Level() + 1
+	}).UnwrapOr(0)
+}
+
+func (b *Block) Location(table map[Uid]int) (loc int) {
+	if val, ok := table[b.Uid()]; ok {
+		return val
+	}
+
+	if parent, ok := b.parent.Split(); ok {
+		loc += parent.Location(table)
+
+		for i, child := range parent.Children() {
+			if child == b {
+				loc += i + 1
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func (b *Block) AddChildren(r ...Render) error {
+	return errors.New("Block cannot have children")
+}
+
+func (b *Block) SetParent(r Render) error {
+	b.parent = option.Some(r)
+	b.index = len(r.Children())
+	b.uid.reset()
+	b.indent = r.ChildIndentLevel()
+
+	return nil
+}
+
+func (b *Block) RemoveChildren(...Uid) error {
+	return errors.New("Block cannot have children")
+}
+
+func (b *Block) Children() []Render {
+	return []Render{}
+}
+
+func (b *Block) ChildrenRec(_ int) []Render {
+	return []Render{}
+}
+
+func (b *Block) Uid() Uid {
+	if b.parent.IsNone() {
+		return NewUid(-1)
+	}
+
+	return keyedUid(b.parent.Unwrap(), b)
+}
+
+// uidKey uses the begin line and the content, like bullets changing the content changes the UID.
+func (b *Block) uidKey() (string, string) {
+	return "blk", b.kind + " " + b.parameters + "\n" + strings.Join(b.lines, "\n")
+}
+
+func (b *Block) keyedUidCache() *uidCache {
+	return &b.uid
+}
+
+func (b *Block) ParentUid() Uid {
+	if b.parent.IsNone() {
+		return NewUid(0)
+	}
+
+	return b.parent.Unwrap().Uid()
+}
+
+func (b *Block) Status() RenderStatus {
+	return ""
+}
+
+func (b *Block) TagList() (list TagList) {
+	if parent, ok := b.parent.Split(); ok {
+		list = parent.TagList()
+	}
+
+	return
+}
+
+func (b *Block) Preview(length int) string {
+	preview := strings.TrimSpace(b.marker("BEGIN") + " " + b.parameters)
+	if content := b.Content(); content != "" {
+		preview += "\n" + content
+	}
+
+	if length < 0 || length >= len(preview) {
+		return preview
+	}
+
+	return preview[:length]
+}
+
+func (b *Block) Path() string {
+	if parent, ok := b.parent.Split(); ok {
+		return parent.Path() + "/" + b.Uid().String()
+	}
+
+	return b.Uid().String()
+}
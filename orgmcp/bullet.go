@@ -50,6 +50,8 @@ type Bullet struct {
 	location int
 
 	parent   o.Option[Render]
+	source   Source
+	uid      uidCache
 	children []Render
 }
 
@@ -61,11 +63,12 @@ func NewBulletFromReader(r *reader.PeekReader) o.Option[*Bullet] {
 
 	line, err := r.ReadBytes('\n')
 	// fmt.Fprintf(os.Stderr, "Parsing bullet: %s\n", string(line))
-	if err != nil {
+	// the last line of a file does not need to end with a newline
+	if err != nil && len(line) == 0 {
 		return o.None[*Bullet]()
 	}
 
-	str := strings.TrimLeft(string(line), " ")
+	str := strings.TrimLeft(string(line), " \t")
 
 	if len(str) < 2 {
 		return o.None[*Bullet]()
@@ -174,6 +177,29 @@ func (b *Bullet) CheckProgress() o.Option[Progress] {
 }
 
 func (b *Bullet) Render(builder *strings.Builder, depth int) {
+	b.source.write(builder, b.render)
+
+	if depth == 0 {
+		return
+	}
+
+	b.source.writeTrailing(builder)
+
+	for _, child := range b.children {
+		child.Render(builder, depth-1)
+	}
+}
+
+func (b *Bullet) sourceRef() *Source {
+	return &b.source
+}
+
+func (b *Bullet) seal() {
+	b.source.seal(b.render)
+}
+
+// render writes the line of the bullet itself.
+func (b *Bullet) render(builder *strings.Builder) {
 	builder.WriteString(strings.Repeat(" ", b.IndentLevel()))
 
 	// Render checkbox status
@@ -189,14 +215,6 @@ func (b *Bullet) Render(builder *strings.Builder, depth int) {
 	// Render content
 	builder.WriteString(b.content)
 	builder.WriteRune('\n')
-
-	if depth == 0 {
-		return
-	}
-
-	for _, child := range b.children {
-		child.Render(builder, depth-1)
-	}
 }
 
 func (b *Bullet) IndentLevel() int {
@@ -234,7 +252,7 @@ func (p *Bullet) Location(table map[Uid]int) (loc int) {
 		loc += parent.Location(table)
 
 		for i, child := range parent.ChildrenRec(-1) {
-			if child.Uid() == p.Uid() {
+			if child == p {
 				loc += i + 1
 				break
 			}
@@ -268,13 +286,14 @@ func (b *Bullet) SetParent(render Render) error {
 
 	b.index = len(render.Children())
 	b.parent = option.Some(render)
+	b.uid.reset()
 
 	return nil
 }
 
 func (b *Bullet) RemoveChildren(uids ...Uid) error {
 	b.children = slice.Filter(b.children, func(r Render) bool {
-		return slices.Contains(uids, b.Uid())
+		return !slices.Contains(uids, r.Uid())
 	})
 
 	return nil
@@ -299,10 +318,18 @@ func (b *Bullet) ChildrenRec(depth int) (children []Render) {
 
 func (b *Bullet) Uid() Uid {
 	return option.Map(b.parent, func(r Render) Uid {
-		return NewUid(fmt.Sprintf("%s.b%d", r.Uid(), b.index))
+		return keyedUid(r, b)
 	}).UnwrapOr(NewUid(-1))
 }
 
+func (b *Bullet) uidKey() (string, string) {
+	return "b", b.content
+}
+
+func (b *Bullet) keyedUidCache() *uidCache {
+	return &b.uid
+}
+
 func (b *Bullet) ParentUid() Uid {
 	return option.Map(b.parent, func(r Render) Uid {
 		return r.Uid()
@@ -310,7 +337,10 @@ func (b *Bullet) ParentUid() Uid {
 }
 
 func (b *Bullet) SetCheckbox(s BulletStatus) {
-	if s == NoCheck {
+	// in lossless mode the bullet keeps the prefix it was written with
+	if CurrentConfig().Lossless {
+		b.checkbox = s
+	} else if s == NoCheck {
 		b.checkbox = NoCheck
 		b.prefix = Star
 	} else {
@@ -0,0 +1,294 @@
+package orgmcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// TemplateTag marks a header as a template, Instantiate copies it with its subtree.
+const TemplateTag = "template"
+
+var placeholderRegex = regexp.MustCompile(`\{\{\s*([\w-]+)\s*\}\}`)
+
+// CloneOptions controls what changes in the copy of a subtree.
+type CloneOptions struct {
+	// ResetCheckboxes unchecks every checkbox of the copy.
+	ResetCheckboxes bool
+	// ResetStatus sets done headers back to the first active TODO state and drops their CLOSED date,
+	// LAST_REPEAT property and logbook.
+	ResetStatus bool
+	// Shift moves every SCHEDULED and DEADLINE date of the copy, like org-clone-subtree-with-time-shift.
+	Shift option.Option[Interval]
+	// Variables replace `{{name}}` placeholders in the copy. When set, a placeholder without a value is an error.
+	Variables map[string]string
+}
+
+// Clone copies a header with its subtree and inserts the copy right after the original.
+// Every header of the copy gets a new ID and `id:` links between headers of the copy point to the new IDs.
+func (of *OrgFile) Clone(uid Uid, options CloneOptions) (*Header, error) {
+	header, ok := option.Cast[Render, *Header](of.GetUid(uid)).Split()
+	if !ok {
+		return nil, fmt.Errorf("Uid %s is not a header", uid)
+	}
+
+	parent, ok := of.parentOf(header)
+	if !ok {
+		return nil, fmt.Errorf("Parent of %s not found", uid)
+	}
+
+	return of.CloneTo(header.Uid(), of, parent.Uid(), siblingIndex(parent.Children(), header)+1, options)
+}
+
+// CloneTo copies a header with its subtree under a parent in target, which can be the file itself.
+// The index counts the sub headers of the parent like MoveTo, -1 appends the copy.
+func (of *OrgFile) CloneTo(uid Uid, target *OrgFile, parentUid Uid, index int, options CloneOptions) (*Header, error) {
+	header, ok := option.Cast[Render, *Header](of.GetUid(uid)).Split()
+	if !ok {
+		return nil, fmt.Errorf("Uid %s is not a header", uid)
+	}
+
+	parent, ok := target.GetUid(parentUid).Split()
+	if !ok {
+		return nil, fmt.Errorf("Parent uid %s not found", parentUid)
+	}
+
+	if _, ok := parent.(*Header); !ok && parent != Render(target) {
+		return nil, errors.New("a header can only be added under another header or the root of the file")
+	}
+
+	clone, err := copySubtree(header)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := clone.apply(options); err != nil {
+		return nil, err
+	}
+
+	at, err := childIndex(parent.Children(), clone, index)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := parent.Insert(at, clone); err != nil {
+		return nil, err
+	}
+
+	reparent(clone, parent)
+	target.Reindex()
+
+	return clone, nil
+}
+
+// copySubtree returns a deep copy of a header and everything below it, with new IDs for all headers.
+// The copy is made by rendering the subtree and reading it back, so it is exactly what would be written to disk.
+func copySubtree(header *Header) (*Header, error) {
+	// the TODO keywords of the file decide which words of a headline are a status
+	builder := strings.Builder{}
+	builder.WriteString("#+TODO: " + header.TodoKeywords().String() + "\n")
+
+	subtree := strings.Builder{}
+	header.Render(&subtree, -1)
+
+	// the copy is read back as a top level header, a nested header would have no parent in the file
+	shift := strings.Repeat("*", header.Level()-1)
+	for line := range strings.Lines(subtree.String()) {
+		if IsHeaderLine(line) {
+			line = strings.TrimPrefix(line, shift)
+		}
+
+		builder.WriteString(line)
+	}
+
+	of, err := OrgFileFromReader(context.Background(), strings.NewReader(builder.String())).Split()
+	if err != nil {
+		return nil, err
+	}
+
+	index := slices.IndexFunc(of.children, func(r Render) bool { _, ok := r.(*Header); return ok })
+	if index == -1 {
+		return nil, fmt.Errorf("failed to copy %s", header.Uid())
+	}
+
+	clone := of.children[index].(*Header)
+
+	ids := map[string]string{}
+	items := append([]Render{clone}, clone.ChildrenRec(-1)...)
+
+	for _, r := range items {
+		if h, ok := r.(*Header); ok {
+			old := h.Uid().String()
+			h.properties.generateID()
+			ids[old] = h.Uid().String()
+		}
+	}
+
+	for _, r := range items {
+		rewriteIDLinks(r, ids)
+	}
+
+	return clone, nil
+}
+
+// apply changes the copy of a subtree as set by the options.
+func (h *Header) apply(options CloneOptions) error {
+	items := append([]Render{h}, h.ChildrenRec(-1)...)
+
+	if options.Variables != nil {
+		missing := []string{}
+
+		substitute := func(text string) string {
+			return placeholderRegex.ReplaceAllStringFunc(text, func(placeholder string) string {
+				name := placeholderRegex.FindStringSubmatch(placeholder)[1]
+				if value, ok := options.Variables[name]; ok {
+					return value
+				}
+
+				if !slices.Contains(missing, name) {
+					missing = append(missing, name)
+				}
+
+				return placeholder
+			})
+		}
+
+		for _, r := range items {
+			rewriteText(r, substitute)
+
+			switch item := r.(type) {
+			case *Header:
+				for _, key := range item.properties.keys {
+					if key != "ID" {
+						item.properties.content[key] = ParsePropValue(substitute(item.properties.content[key].String()))
+					}
+				}
+			case *Block:
+				for i, line := range item.lines {
+					item.lines[i] = substitute(line)
+				}
+			}
+		}
+
+		if len(missing) > 0 {
+			return fmt.Errorf("no value for the template variables %s", strings.Join(missing, ", "))
+		}
+	}
+
+	for _, r := range items {
+		switch item := r.(type) {
+		case *Header:
+			item.applySchedule(options)
+		case *Bullet:
+			if options.ResetCheckboxes && item.checkbox == Checked {
+				item.SetCheckbox(Unchecked)
+			}
+		}
+	}
+
+	return nil
+}
+
+// applySchedule resets the status and shifts the dates of a single copied header.
+func (h *Header) applySchedule(options CloneOptions) {
+	schedule, hasSchedule := h.schedule.Split()
+
+	if options.ResetStatus && h.TodoKeywords().IsDone(h.status) {
+		h.status = h.TodoKeywords().DefaultActive()
+		h.properties.Remove("LAST_REPEAT")
+		h.logbook = option.None[Logbook]()
+
+		if hasSchedule {
+			delete(schedule.Values, Closed)
+		}
+	}
+
+	if interval, ok := options.Shift.Split(); ok && hasSchedule {
+		for _, status := range []ScheduleStatus{Scheduled, Deadline} {
+			if ts, ok := schedule.Values[status]; ok {
+				ts.Shift(interval)
+				schedule.Values[status] = ts
+			}
+		}
+	}
+
+	if hasSchedule && len(schedule.Values) == 0 {
+		h.schedule = option.None[Schedule]()
+	} else if hasSchedule {
+		h.schedule = option.Some(schedule)
+	}
+}
+
+// Templates returns the headers tagged :template: that are not inside another template, in file order.
+func (of *OrgFile) Templates() (templates []*Header) {
+	for _, r := range of.ChildrenRec(-1) {
+		header, ok := r.(*Header)
+		if !ok || !slices.Contains(header.Tags.UnwrapOr(nil), TemplateTag) {
+			continue
+		}
+
+		if slices.ContainsFunc(templates, func(t *Header) bool { return slices.Contains(t.ChildrenRec(-1), r) }) {
+			continue
+		}
+
+		templates = append(templates, header)
+	}
+
+	return
+}
+
+// Template finds a template by its UID or its headline.
+func (of *OrgFile) Template(name string) option.Option[*Header] {
+	for _, template := range of.Templates() {
+		if template.Uid() == NewUid(name) || template.Content == name {
+			return option.Some(template)
+		}
+	}
+
+	return option.None[*Header]()
+}
+
+// Instantiate copies a template of this file under a parent in target like CloneTo, which can be this file.
+// The copy loses the template tag and every `{{name}}` placeholder must have a value in the variables of the options.
+func (of *OrgFile) Instantiate(name string, target *OrgFile, parentUid Uid, index int, options CloneOptions) (*Header, error) {
+	template, ok := of.Template(name).Split()
+	if !ok {
+		return nil, fmt.Errorf("template %s not found, templates are headers tagged :%s:", name, TemplateTag)
+	}
+
+	if options.Variables == nil {
+		options.Variables = map[string]string{}
+	}
+
+	clone, err := of.CloneTo(template.Uid(), target, parentUid, index, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if tags := slices.DeleteFunc(clone.Tags.UnwrapOr(nil), func(tag string) bool { return tag == TemplateTag }); len(tags) > 0 {
+		clone.Tags = option.Some(tags)
+	} else {
+		clone.Tags = option.None[TagList]()
+	}
+
+	return clone, nil
+}
+
+// Placeholders returns the names of the `{{name}}` placeholders in a header and its subtree, in order of appearance.
+func (h *Header) Placeholders() (names []string) {
+	builder := strings.Builder{}
+	h.Render(&builder, -1)
+
+	for _, match := range placeholderRegex.FindAllStringSubmatch(builder.String(), -1) {
+		if !slices.Contains(names, match[1]) {
+			names = append(names, match[1])
+		}
+	}
+
+	return
+}
@@ -0,0 +1,52 @@
+package orgmcp
+
+import "time"
+
+// Config holds the server wide defaults, files can override some of these with in-buffer settings.
+type Config struct {
+	// TodoKeywords is used for files without a `#+TODO:` line.
+	TodoKeywords TodoKeywords
+	// Now returns the current time, relative dates and CLOSED or clock times are based on it.
+	// When nil the system clock is used.
+	Now func() time.Time
+	// Lossless keeps the original text of every item an operation did not change,
+	// including blank lines, indentation and alignment.
+	Lossless bool
+	// IDs generates the ID property of new headers, when nil numeric IDs are used.
+	IDs IDGenerator
+	// RespectLocks refuses to write a file that has unsaved changes in Emacs, otherwise tools only warn about it.
+	RespectLocks bool
+	// BackupDir is where a copy of a file is kept before it is overwritten, relative to the directory of the file.
+	// Backups are disabled when it is empty.
+	BackupDir string
+	// Backups is how many copies of each file are kept in BackupDir, the oldest are removed.
+	Backups int
+	// JournalSize is how many changes of each file can be undone, the journal is disabled when it is 0.
+	JournalSize int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		TodoKeywords: DefaultTodoKeywords,
+	}
+}
+
+var config = DefaultConfig()
+
+// Configure replaces the current configuration, it should be called before any file is parsed.
+func Configure(c Config) {
+	config = c
+}
+
+func CurrentConfig() Config {
+	return config
+}
+
+// Now returns the current time according to the configuration.
+func Now() time.Time {
+	if config.Now != nil {
+		return config.Now()
+	}
+
+	return time.Now()
+}
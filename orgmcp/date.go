@@ -0,0 +1,110 @@
+package orgmcp
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+var relativeDateRegex = regexp.MustCompile(`^([+-])(\d+)([hdwmy])$`)
+
+var weekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// ParseDate resolves a date expression against now. Besides timestamps like `2026-10-20 14:00`
+// or `<2026-10-20 Tue +1w>` it accepts relative dates similar to the org mode date prompt:
+// `+3d`, `-1w`, `today`, `tomorrow`, `monday`, `next monday`, `next week` and `end of month`.
+// A relative date can be followed by a time of day or time range, e.g. `tomorrow 14:00-15:00`.
+func ParseDate(expr string, now time.Time) (ts Timestamp, err error) {
+	expr = strings.TrimSpace(expr)
+
+	if ts, err = ParseTimestamp(expr); err == nil {
+		return
+	}
+
+	fields := strings.Fields(strings.ToLower(expr))
+	clock := ""
+
+	if len(fields) > 1 && (timestampTimeRegex.MatchString(fields[len(fields)-1]) || timestampTimeRangeRegex.MatchString(fields[len(fields)-1])) {
+		clock = fields[len(fields)-1]
+		fields = fields[:len(fields)-1]
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	date, ok := relativeDate(strings.Join(fields, " "), today, now).Split()
+	if !ok {
+		err = fmt.Errorf("invalid date %s", expr)
+		return
+	}
+
+	if clock == "" {
+		withTime := date.Hour() != 0 || date.Minute() != 0
+		return NewTimestamp(date, withTime), nil
+	}
+
+	return ParseTimestamp(date.Format("2006-01-02") + " " + clock)
+}
+
+func relativeDate(expr string, today time.Time, now time.Time) option.Option[time.Time] {
+	if matches := relativeDateRegex.FindStringSubmatch(expr); matches != nil {
+		value, _ := strconv.Atoi(matches[2])
+		if matches[1] == "-" {
+			value = -value
+		}
+
+		interval := Interval{Value: value, Unit: rune(matches[3][0])}
+		if interval.Unit == 'h' {
+			return option.Some(interval.AddTo(now.Truncate(time.Minute), 1))
+		}
+
+		return option.Some(interval.AddTo(today, 1))
+	}
+
+	switch expr {
+	case "now":
+		return option.Some(now.Truncate(time.Minute))
+	case "today":
+		return option.Some(today)
+	case "tomorrow":
+		return option.Some(today.AddDate(0, 0, 1))
+	case "yesterday":
+		return option.Some(today.AddDate(0, 0, -1))
+	case "next week":
+		return option.Some(today.AddDate(0, 0, 7))
+	case "next month":
+		return option.Some(today.AddDate(0, 1, 0))
+	case "next year":
+		return option.Some(today.AddDate(1, 0, 0))
+	case "end of week":
+		// weeks end on sunday, like the org mode agenda
+		return option.Some(today.AddDate(0, 0, (7-int(today.Weekday()))%7))
+	case "end of month":
+		return option.Some(time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location()))
+	case "end of year":
+		return option.Some(time.Date(today.Year(), time.December, 31, 0, 0, 0, 0, today.Location()))
+	}
+
+	// a weekday is the first one from today on, `next monday` skips today
+	next := strings.HasPrefix(expr, "next ")
+	if weekday, ok := weekdays[strings.TrimPrefix(expr, "next ")]; ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if days == 0 && next {
+			days = 7
+		}
+
+		return option.Some(today.AddDate(0, 0, days))
+	}
+
+	return option.None[time.Time]()
+}
@@ -0,0 +1,294 @@
+package orgmcp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+	"github.com/p3rtang/org-mcp/utils/reader"
+)
+
+var drawerBeginRegex = regexp.MustCompile(`^:([\w-]+):$`)
+
+// Drawer is a `:NAME:` ... `:END:` drawer in the body of a header, e.g. `:NOTES:` or `:RESULTS:`.
+// Like blocks the content of a drawer is kept verbatim.
+type Drawer struct {
+	name      string
+	lines     []string
+	lowercase bool
+	indent    int
+	index     int
+
+	parent option.Option[Render]
+	source Source
+	uid    uidCache
+}
+
+// Enforce that Drawer implements the Render interface at compile time
+var _ Render = (*Drawer)(nil)
+
+// NewDrawer creates a drawer with the given name and content, the name is used as is, e.g. NOTES.
+func NewDrawer(name string, content string) (drawer Drawer, err error) {
+	name = strings.Trim(strings.TrimSpace(name), ":")
+
+	if !drawerBeginRegex.MatchString(":"+name+":") || strings.EqualFold(name, "END") {
+		err = fmt.Errorf("invalid drawer name '%s', use a single word like NOTES", name)
+		return
+	}
+
+	drawer.name = name
+	err = drawer.SetContent(content)
+
+	return
+}
+
+// IsDrawerLine reports whether the given line (with or without indentation) starts a drawer.
+func IsDrawerLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return drawerBeginRegex.MatchString(line) && !strings.EqualFold(line, ":END:")
+}
+
+func isDrawerEndLine(line string) bool {
+	return strings.EqualFold(strings.TrimSpace(line), ":END:")
+}
+
+// NewDrawerFromReader reads a drawer up to and including its `:END:` line.
+// When the end line is missing before the next headline or the end of the file
+// nothing is consumed and None is returned, the begin line is then just text.
+func NewDrawerFromReader(r *reader.PeekReader) option.Option[*Drawer] {
+	bytes, err := r.PeekBytes('\n')
+	if err != nil && len(bytes) == 0 {
+		return option.None[*Drawer]()
+	}
+
+	if !IsDrawerLine(string(bytes)) {
+		return option.None[*Drawer]()
+	}
+
+	first, _ := r.ReadBytes('\n')
+	consumed := first
+	indent := len(first) - len(strings.TrimLeft(string(first), " "))
+
+	drawer := Drawer{
+		name:   strings.Trim(strings.TrimSpace(string(first)), ":"),
+		indent: indent,
+	}
+
+	for {
+		line, err := r.ReadBytes('\n')
+		consumed = append(consumed, line...)
+
+		if IsHeaderLine(string(line)) || (err != nil && len(line) == 0) {
+			r.Unread(consumed)
+			return option.None[*Drawer]()
+		}
+
+		content := strings.TrimRight(string(line), "\r\n")
+
+		if isDrawerEndLine(content) {
+			drawer.lowercase = strings.TrimSpace(content) == ":end:"
+			return option.Some(&drawer)
+		}
+
+		// strip the indentation of the drawer itself, but never more than the line has
+		lineIndent := len(content) - len(strings.TrimLeft(content, " "))
+		drawer.lines = append(drawer.lines, content[min(indent, lineIndent):])
+
+		if err != nil {
+			r.Unread(consumed)
+			return option.None[*Drawer]()
+		}
+	}
+}
+
+func (d *Drawer) Name() string {
+	return d.name
+}
+
+// Content returns the lines of the drawer without the drawer indentation.
+func (d *Drawer) Content() string {
+	return strings.Join(d.lines, "\n")
+}
+
+// SetContent replaces the content of the drawer. Org mode has no escape for drawers,
+// so lines that would end the drawer or start a headline are rejected.
+func (d *Drawer) SetContent(content string) error {
+	lines := []string{}
+
+	if content != "" {
+		for line := range strings.SplitSeq(strings.TrimRight(content, "\n"), "\n") {
+			if isDrawerEndLine(line) || IsHeaderLine(line) {
+				return fmt.Errorf("drawer content cannot contain the line '%s'", line)
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	d.lines = lines
+
+	return nil
+}
+
+func (d *Drawer) CheckProgress() option.Option[Progress] {
+	return option.None[Progress]()
+}
+
+func (d *Drawer) Render(builder *strings.Builder, depth int) {
+	d.source.write(builder, d.render)
+
+	if depth != 0 {
+		d.source.writeTrailing(builder)
+	}
+}
+
+func (d *Drawer) sourceRef() *Source {
+	return &d.source
+}
+
+func (d *Drawer) seal() {
+	d.source.seal(d.render)
+}
+
+func (d *Drawer) render(builder *strings.Builder) {
+	indent := strings.Repeat(" ", d.indent)
+
+	builder.WriteString(indent)
+	builder.WriteString(":" + d.name + ":\n")
+
+	for _, line := range d.lines {
+		if line != "" {
+			builder.WriteString(indent)
+			builder.WriteString(line)
+		}
+
+		builder.WriteRune('\n')
+	}
+
+	builder.WriteString(indent)
+	if d.lowercase {
+		builder.WriteString(":end:\n")
+	} else {
+		builder.WriteString(":END:\n")
+	}
+}
+
+func (d *Drawer) IndentLevel() int {
+	return option.Map(d.parent, func(r Render) int {
+		return r.ChildIndentLevel()
+	}).UnwrapOr(0)
+}
+
+func (d *Drawer) ChildIndentLevel() int {
+	return d.IndentLevel()
+}
+
+func (d *Drawer) Level() int {
+	return option.Map(d.parent, func(r Render) int {
+		return r.Level() + 1
+	}).UnwrapOr(0)
+}
+
+func (d *Drawer) Location(table map[Uid]int) (loc int) {
+	if val, ok := table[d.Uid()]; ok {
+		return val
+	}
+
+	if parent, ok := d.parent.Split(); ok {
+		loc += parent.Location(table)
+
+		for i, child := range parent.Children() {
+			if child == d {
+				loc += i + 1
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func (d *Drawer) AddChildren(r ...Render) error {
+	return errors.New("Drawer cannot have children")
+}
+
+func (d *Drawer) SetParent(r Render) error {
+	d.parent = option.Some(r)
+	d.index = len(r.Children())
+	d.uid.reset()
+	d.indent = r.ChildIndentLevel()
+
+	return nil
+}
+
+func (d *Drawer) RemoveChildren(...Uid) error {
+	return errors.New("Drawer cannot have children")
+}
+
+func (d *Drawer) Children() []Render {
+	return []Render{}
+}
+
+func (d *Drawer) ChildrenRec(_ int) []Render {
+	return []Render{}
+}
+
+func (d *Drawer) Uid() Uid {
+	if d.parent.IsNone() {
+		return NewUid(-1)
+	}
+
+	return keyedUid(d.parent.Unwrap(), d)
+}
+
+// uidKey uses the name and the content, like bullets changing the content changes the UID.
+func (d *Drawer) uidKey() (string, string) {
+	return "drw", d.name + "\n" + strings.Join(d.lines, "\n")
+}
+
+func (d *Drawer) keyedUidCache() *uidCache {
+	return &d.uid
+}
+
+func (d *Drawer) ParentUid() Uid {
+	if d.parent.IsNone() {
+		return NewUid(0)
+	}
+
+	return d.parent.Unwrap().Uid()
+}
+
+func (d *Drawer) Status() RenderStatus {
+	return ""
+}
+
+func (d *Drawer) TagList() (list TagList) {
+	if parent, ok := d.parent.Split(); ok {
+		list = parent.TagList()
+	}
+
+	return
+}
+
+func (d *Drawer) Preview(length int) string {
+	preview := ":" + d.name + ":"
+	if content := d.Content(); content != "" {
+		preview += "\n" + content
+	}
+
+	if length < 0 || length >= len(preview) {
+		return preview
+	}
+
+	return preview[:length]
+}
+
+func (d *Drawer) Path() string {
+	if parent, ok := d.parent.Split(); ok {
+		return parent.Path() + "/" + d.Uid().String()
+	}
+
+	return d.Uid().String()
+}
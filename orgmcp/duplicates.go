@@ -0,0 +1,151 @@
+package orgmcp
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// DuplicateID is a header that has the same ID as an earlier header of the file,
+// which happens when a subtree is copied in Emacs. Only the first header can be targeted by the ID.
+type DuplicateID struct {
+	Header   *Header
+	Original *Header
+}
+
+// IDRepair is a header that got a new ID from RepairIDs.
+type IDRepair struct {
+	Header *Header
+	Old    Uid
+	New    Uid
+}
+
+var idLinkRegex = regexp.MustCompile(`\[\[id:([^\]]+)\]`)
+
+// DuplicateIDs returns the headers that reuse the ID of an earlier header, in file order.
+func (of *OrgFile) DuplicateIDs() []DuplicateID {
+	return of.duplicates
+}
+
+// Warnings returns the problems found while reading the file that tools should report.
+func (of *OrgFile) Warnings() (warnings []string) {
+	for _, dup := range of.duplicates {
+		warnings = append(warnings, fmt.Sprintf(
+			"Duplicate ID %s: '%s' has the same ID as '%s', the UID only targets the first one. Use repair_ids to give the later header a new ID.",
+			dup.Header.Uid(), dup.Header.Content, dup.Original.Content,
+		))
+	}
+
+	return
+}
+
+// RepairIDs gives every duplicate header a new ID and returns the repairs in file order.
+// A duplicate is usually part of a copied subtree, so `id:` links inside that subtree that point
+// at a repaired header are updated to the new ID, links elsewhere keep pointing at the original.
+func (of *OrgFile) RepairIDs() (repairs []IDRepair, links int) {
+	if len(of.duplicates) == 0 {
+		return
+	}
+
+	used := map[Uid]bool{}
+	for _, r := range of.ChildrenRec(-1) {
+		if header, ok := r.(*Header); ok {
+			used[header.Uid()] = true
+		}
+	}
+
+	duplicate := map[*Header]bool{}
+	for _, dup := range of.duplicates {
+		duplicate[dup.Header] = true
+	}
+
+	// the new IDs grouped by the outermost duplicate header, the root of the copied subtree
+	roots := []*Header{}
+	relinks := map[*Header]map[string]string{}
+
+	for _, dup := range of.duplicates {
+		id := newID()
+		for used[NewUid(id)] {
+			id = newID()
+		}
+
+		used[NewUid(id)] = true
+
+		old := dup.Header.Uid()
+		dup.Header.properties.Set("ID", ParsePropValue(id))
+		repairs = append(repairs, IDRepair{Header: dup.Header, Old: old, New: dup.Header.Uid()})
+
+		root := dup.Header
+		for parent, ok := root.Parent.Split(); ok; {
+			header, isHeader := parent.(*Header)
+			if !isHeader {
+				break
+			}
+
+			if duplicate[header] {
+				root = header
+			}
+
+			parent, ok = header.Parent.Split()
+		}
+
+		if _, ok := relinks[root]; !ok {
+			roots = append(roots, root)
+			relinks[root] = map[string]string{}
+		}
+
+		relinks[root][old.String()] = id
+	}
+
+	for _, root := range roots {
+		for _, r := range append([]Render{root}, root.ChildrenRec(-1)...) {
+			links += rewriteIDLinks(r, relinks[root])
+		}
+	}
+
+	of.Reindex()
+
+	return
+}
+
+// rewriteIDLinks points the `[[id:...]]` links in the text of an item to their new ID
+// and returns the number of links that changed. Blocks are left alone, their content is code or verbatim text.
+func rewriteIDLinks(r Render, ids map[string]string) (count int) {
+	rewriteText(r, func(text string) string {
+		return idLinkRegex.ReplaceAllStringFunc(text, func(link string) string {
+			id := idLinkRegex.FindStringSubmatch(link)[1]
+			if newID, ok := ids[id]; ok {
+				count += 1
+				return "[[id:" + newID + "]"
+			}
+
+			return link
+		})
+	})
+
+	return
+}
+
+// rewriteText replaces the own text of an item, without its children, with the result of rewrite.
+// Blocks and properties are not included.
+func rewriteText(r Render, rewrite func(string) string) {
+	switch item := r.(type) {
+	case *Header:
+		item.Content = rewrite(item.Content)
+	case *Bullet:
+		item.content = rewrite(item.content)
+	case *PlainText:
+		item.content = rewrite(item.content)
+	case *Keyword:
+		item.value = rewrite(item.value)
+	case *Drawer:
+		for i, line := range item.lines {
+			item.lines[i] = rewrite(line)
+		}
+	case *Table:
+		for _, row := range item.rows {
+			for i, cell := range row.Cells {
+				row.Cells[i] = rewrite(cell)
+			}
+		}
+	}
+}
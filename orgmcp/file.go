@@ -34,6 +34,13 @@ type OrgFile struct {
 
 	items       map[Uid]Render
 	locationMap map[Uid]int
+	duplicates  []DuplicateID
+	// leading holds the blank lines at the start of the file, see Config.Lossless
+	leading string
+	// base is the content the file was read from, a write checks it against the disk to find changes made by others
+	base option.Option[string]
+	// baseRender is how the file rendered right after it was read, before any tool changed it
+	baseRender string
 }
 
 // Enforce that OrgFile implements the Render interface at compile time
@@ -55,41 +62,57 @@ func OrgFileFromReader(ctx context.Context, r io.Reader) result.Result[OrgFile]
 	var currentParent map[int]Render = map[int]Render{
 		0: &org_file,
 	}
-	currentParentIdx := 1
+	currentParentIdx := 0
 
 	var currentContent map[int]Render = map[int]Render{}
 	currentContentIndex := 0
 	currentContentIndent := 0
 
+	// blank lines are kept with the item above them
+	var last sourced
+
 	for val, err := peek_reader.PeekBytes('\n'); true; val, err = peek_reader.PeekBytes('\n') {
-		if err == io.EOF {
+		// the last line of a file does not need to end with a newline
+		if err == io.EOF && len(val) == 0 {
 			break
 		}
 
-		if err != nil {
+		if err != nil && err != io.EOF {
 			return result.Err[OrgFile](err)
 		}
 
+		start := peek_reader.Offset()
+
 		if len(strings.TrimSpace(string(val))) == 0 {
 			peek_reader.Continue()
+
+			if last != nil {
+				last.sourceRef().trailing += peek_reader.Text(start, peek_reader.Offset())
+			} else {
+				org_file.leading += peek_reader.Text(start, peek_reader.Offset())
+			}
+
 			continue
 		}
 
-		switch val[0] {
-		case '*':
+		switch {
+		case IsHeaderLine(string(val)):
 			peek_reader.Continue()
-			NewHeaderFromString(string(val), peek_reader).Then(func(h Header) {
+			headline := peek_reader.Text(start, peek_reader.Offset())
+
+			NewHeaderWithKeywords(string(val), peek_reader, org_file.TodoKeywords()).Then(func(h Header) {
+				h.source.capture(headline)
 				h.Parent = option.Some(currentParent[h.Level()-1])
 				h.location = current_line
 				currentParent[h.Level()-1].AddChildren(&h)
-				org_file.items[h.Uid()] = &h
 				current_line += 1
 				currentParent[h.Level()] = &h
 				currentParentIdx = h.Level()
 				currentContentIndex = 0
 				currentContentIndent = 0
+				last = &h
 			})
-		case ' ':
+		default:
 			indent := len(val) - len(strings.TrimLeft(string(val), " "))
 			ParseIndentedLine(peek_reader, currentParent[currentParentIdx]).Then(func(r Render) {
 				if currentContentIndent == 0 {
@@ -111,13 +134,17 @@ func OrgFileFromReader(ctx context.Context, r io.Reader) result.Result[OrgFile]
 					currentParent[currentParentIdx].AddChildren(r)
 				}
 
-				org_file.items[r.Uid()] = r
+				if s, ok := r.(sourced); ok {
+					s.sourceRef().capture(peek_reader.Text(start, peek_reader.Offset()))
+					last = s
+				}
 			})
-		default:
-			panic("unreachable")
 		}
 	}
 
+	org_file.deriveIDs()
+	org_file.Reindex()
+	sealSources(&org_file)
 	org_file.BuildLocationTable()
 
 	peek_reader.Continue()
@@ -148,16 +175,43 @@ func OrgFileFromReader(ctx context.Context, r io.Reader) result.Result[OrgFile]
 	return result.Ok(org_file)
 }
 
+// IsHeaderLine reports whether the line is an org headline, meaning it starts with one or more
+// stars followed by a space or the end of the line. Lines like `*bold* text` are not headlines.
+func IsHeaderLine(line string) bool {
+	line = strings.TrimRight(line, "\r\n")
+	stars := len(line) - len(strings.TrimLeft(line, "*"))
+
+	return stars > 0 && (stars == len(line) || line[stars] == ' ')
+}
+
+// ParseIndentedLine parses any line that is not a headline, indented or not.
+// Lines before the first headline end up as children of the OrgFile itself.
 func ParseIndentedLine(r *reader.PeekReader, parent Render) option.Option[Render] {
 	// errors have already been handled at this point
 	bytes, _ := r.PeekBytes('\n')
 	trimmed := strings.TrimSpace(string(bytes))
 	// fmt.Fprintf(os.Stderr, "Indented: %s\n", string(bytes))
 
-	switch trimmed[0] {
-	case '-':
-		fallthrough
-	case '*':
+	switch {
+	case IsKeywordLine(trimmed):
+		return option.Cast[*Keyword, Render](NewKeywordFromReader(r))
+	case IsTableLine(trimmed):
+		return option.Cast[*Table, Render](NewTableFromReader(r))
+	case IsBlockLine(trimmed):
+		if block, ok := NewBlockFromReader(r).Split(); ok {
+			return option.Some[Render](block)
+		}
+
+		// an unterminated block is just text
+		return option.Cast[*PlainText, Render](NewPlainTextFromReader(r))
+	case IsDrawerLine(trimmed):
+		if drawer, ok := NewDrawerFromReader(r).Split(); ok {
+			return option.Some[Render](drawer)
+		}
+
+		// an unterminated drawer is just text
+		return option.Cast[*PlainText, Render](NewPlainTextFromReader(r))
+	case trimmed == "-" || strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
 		return option.Cast[*Bullet, Render](NewBulletFromReader(r))
 	default:
 		return option.Cast[*PlainText, Render](NewPlainTextFromReader(r))
@@ -172,6 +226,29 @@ func (of *OrgFile) SetName(name string) {
 	of.name = name
 }
 
+// Base returns the content the file was read from, None for a file that did not exist yet.
+func (of *OrgFile) Base() option.Option[string] {
+	return of.base
+}
+
+// BaseRender returns how the file rendered when its base was set. It differs from the base when
+// rendering normalizes the file, for example by aligning tags or adding missing IDs.
+func (of *OrgFile) BaseRender() string {
+	return of.baseRender
+}
+
+// SetBase records the content the file was read from, the file must not have been changed since.
+func (of *OrgFile) SetBase(content string) {
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	of.base = option.Some(content)
+	of.baseRender = builder.String()
+	if !strings.HasSuffix(of.baseRender, "\n") {
+		of.baseRender += "\n"
+	}
+}
+
 func (of *OrgFile) GenerateEmbeddings() error {
 	headers := []Render{}
 	contents := []string{}
@@ -271,6 +348,10 @@ func (of *OrgFile) Render(builder *strings.Builder, depth int) {
 		return
 	}
 
+	if CurrentConfig().Lossless {
+		builder.WriteString(of.leading)
+	}
+
 	var headers []Render
 	var content []Render
 
@@ -352,18 +433,115 @@ func (of *OrgFile) RemoveChildren(uids ...Uid) error {
 	return nil
 }
 
+// GetUid looks up an item by its UID. Headers can also be found with org link targets,
+// `id:` followed by their ID or `#` followed by their CUSTOM_ID, or just their CUSTOM_ID.
+// Items are looked up in the index of the file, after adding or changing items call Reindex to find them.
 func (of *OrgFile) GetUid(uid Uid) option.Option[Render] {
 	if uid == NewUid(0) || uid == NewUid("root") {
 		return option.Some[Render](of)
 	}
 
-	if child, found := of.items[uid]; found {
+	if child, found := of.items[NewUid(strings.TrimPrefix(uid.String(), "id:"))]; found {
 		return option.Some(child)
 	}
 
+	customID := strings.TrimPrefix(uid.String(), "#")
+
+	for _, r := range of.ChildrenRec(-1) {
+		if header, ok := r.(*Header); ok && header.GetProperty("CUSTOM_ID") == option.Some(customID) {
+			return option.Some(r)
+		}
+	}
+
 	return option.None[Render]()
 }
 
+// deriveIDs gives the headers that were read without an ID one derived from the UID of their parent
+// and their title, an ID read from the file is never used twice. The IDs stay hidden until the header changes.
+func (of *OrgFile) deriveIDs() {
+	items := of.ChildrenRec(-1)
+	used := map[string]bool{}
+
+	for _, r := range items {
+		if header, ok := r.(*Header); ok && !header.properties.hidden {
+			used[header.Uid().String()] = true
+		}
+	}
+
+	for _, r := range items {
+		header, ok := r.(*Header)
+		if !ok || !header.properties.hidden {
+			continue
+		}
+
+		key := header.ParentUid().String() + "\n" + header.Content
+		id := derivedID(key)
+
+		for i := 2; used[id]; i++ {
+			id = derivedID(fmt.Sprintf("%s\n%d", key, i))
+		}
+
+		header.properties.content["ID"] = ParsePropValue(id)
+		used[id] = true
+	}
+}
+
+// Reindex rebuilds the UID lookup of the file from its items. Generated IDs that are
+// already used by another header of the file are replaced first. When headers read from
+// the file share an ID the first one keeps it and the others are recorded as duplicates.
+func (of *OrgFile) Reindex() {
+	items := of.ChildrenRec(-1)
+	used := map[Uid]*Header{}
+	of.duplicates = nil
+
+	for _, r := range items {
+		if header, ok := r.(*Header); ok && !header.properties.generated {
+			if original, found := used[header.Uid()]; found {
+				of.duplicates = append(of.duplicates, DuplicateID{Header: header, Original: original})
+				continue
+			}
+
+			used[header.Uid()] = header
+		}
+	}
+
+	for _, r := range items {
+		if header, ok := r.(*Header); ok && header.properties.generated {
+			for used[header.Uid()] != nil {
+				header.properties.generateID()
+			}
+
+			used[header.Uid()] = header
+		}
+	}
+
+	// drop the cached UIDs of the items below headers, the first lookup below numbers all siblings at once
+	for _, r := range items {
+		if item, ok := r.(keyed); ok {
+			item.keyedUidCache().reset()
+		}
+	}
+
+	of.items = map[Uid]Render{NewUid(0): of}
+
+	for _, r := range items {
+		if _, found := of.items[r.Uid()]; !found {
+			of.items[r.Uid()] = r
+		}
+	}
+}
+
+// NewHeader creates a header with an ID that no other item of the file uses.
+func (of *OrgFile) NewHeader(status HeaderStatus, content string) Header {
+	header := NewHeader(status, content)
+
+	for of.GetUid(header.Uid()).IsSome() {
+		header.properties.generateID()
+	}
+
+	return header
+}
+
 func (of *OrgFile) ParentUid() Uid {
 	return NewUid(0)
 }
@@ -399,6 +577,22 @@ func (of *OrgFile) GetHeaderByStatus(status RenderStatus) []*Header {
 	return headers
 }
 
+// RunningClock returns the header with a running clock, like org mode only one clock runs at a time.
+func (of *OrgFile) RunningClock() option.Option[*Header] {
+	for _, child := range of.ChildrenRec(-1) {
+		header, ok := child.(*Header)
+		if !ok {
+			continue
+		}
+
+		if header.Logbook().AndThen(func(l *Logbook) bool { return l.Running().IsSome() }) {
+			return option.Some(header)
+		}
+	}
+
+	return option.None[*Header]()
+}
+
 type StatusReport struct {
 	Count int   `json:"count"`
 	Ids   []Uid `json:"ids"`
@@ -469,10 +663,11 @@ func GetHeaderRec(header *Header, predicate func(*Header) bool, headers []*Heade
 }
 
 func (of *OrgFile) BuildLocationTable() *map[Uid]int {
-	location_table := make(map[Uid]int)
+	location_table := map[Uid]int{of.Uid(): 0}
 
-	for _, r := range of.items {
-		location_table[r.Uid()] = r.Location(location_table)
+	// the location of an item is its position in the file, so one walk in file order gives all of them
+	for i, r := range of.ChildrenRec(-1) {
+		location_table[r.Uid()] = i + 1
 	}
 
 	of.locationMap = location_table
@@ -488,10 +683,41 @@ func (of *OrgFile) Status() RenderStatus {
 	return ""
 }
 
+// TagList returns the tags set with `#+FILETAGS:`, these are inherited by every header in the file.
 func (of *OrgFile) TagList() (list TagList) {
+	for _, value := range of.Keywords("FILETAGS") {
+		for tag := range strings.SplitSeq(value, ":") {
+			for t := range strings.FieldsSeq(tag) {
+				list = append(list, t)
+			}
+		}
+	}
+
 	return
 }
 
+// Keywords returns the values of all `#+KEY:` lines with the given key, in file order.
+// Only keywords that are direct children of the file are considered.
+func (of *OrgFile) Keywords(key string) (values []string) {
+	for _, child := range of.children {
+		if keyword, ok := child.(*Keyword); ok && strings.EqualFold(keyword.Key(), key) {
+			values = append(values, keyword.Value())
+		}
+	}
+
+	return
+}
+
+// Keyword returns the value of the last `#+KEY:` line with the given key.
+func (of *OrgFile) Keyword(key string) option.Option[string] {
+	values := of.Keywords(key)
+	if len(values) == 0 {
+		return option.None[string]()
+	}
+
+	return option.Some(values[len(values)-1])
+}
+
 func (of *OrgFile) Preview(_ int) string {
 	return ""
 }
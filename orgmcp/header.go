@@ -4,7 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"iter"
-	"os"
 	"slices"
 	"strings"
 	"time"
@@ -17,45 +16,23 @@ import (
 
 type HeaderStatus string
 
+// StatusFromString matches the string against the configured default TODO keywords.
 func StatusFromString(str string) HeaderStatus {
-	switch strings.ToLower(str) {
-	case "todo":
-		return Todo
-	case "next":
-		return Next
-	case "prog":
-		return Prog
-	case "revw":
-		return Revw
-	case "done":
-		return Done
-	case "delg":
-		return Delg
-	}
-
-	return None
+	return CurrentConfig().TodoKeywords.Parse(str)
 }
 
+// UnmarshalJSON accepts any single word as a status, whether it is a TODO keyword
+// depends on the file the status is applied to.
 func (h *HeaderStatus) UnmarshalJSON(input []byte) error {
-	switch strings.Trim(strings.ToLower(string(input)), "\"") {
-	case "todo":
-		fmt.Fprintf(os.Stderr, "Found todo")
-		*h = Todo
-	case "next":
-		*h = Next
-	case "prog":
-		*h = Prog
-	case "revw":
-		*h = Revw
-	case "done":
-		*h = Done
-	case "delg":
-		*h = Delg
-	case "none":
-	case "":
+	str := strings.Trim(string(input), "\"")
+
+	switch {
+	case str == "" || strings.EqualFold(str, string(None)):
 		*h = None
-	default:
+	case strings.ContainsAny(str, " \t|"):
 		return errors.New("invalid HeaderStatus value")
+	default:
+		*h = HeaderStatus(strings.ToUpper(str))
 	}
 
 	return nil
@@ -69,21 +46,9 @@ func (s HeaderStatus) String() string {
 	return string(s)
 }
 
+// GetNext cycles through the configured default TODO keywords.
 func (s HeaderStatus) GetNext() HeaderStatus {
-	switch s {
-	case None:
-		return Todo
-	case Todo:
-		return Next
-	case Next:
-		return Prog
-	case Prog:
-		return Done
-	case Done:
-		return None
-	default:
-		panic("unreachable")
-	}
+	return CurrentConfig().TodoKeywords.Next(s)
 }
 
 const (
@@ -96,8 +61,6 @@ const (
 	Delg HeaderStatus = "DELG"
 )
 
-var DoneStatuses = []HeaderStatus{Revw, Done, Delg}
-
 var SPECIAL_TOKENS = []string{"[", ":"}
 
 // Enforce that Header implements the Render interface at compile time
@@ -106,6 +69,7 @@ var _ Render = (*Header)(nil)
 type Header struct {
 	status   HeaderStatus
 	level    int
+	Priority option.Option[Priority]
 	Progress option.Option[Progress]
 	Tags     option.Option[TagList]
 	location int
@@ -114,7 +78,13 @@ type Header struct {
 	children   []Render
 	schedule   option.Option[Schedule]
 	properties Properties
+	logbook    option.Option[Logbook]
 	embedding  option.Option[embeddings.Embedding]
+	source     Source
+	// parsed holds the own lines and the parent UID of the header right after parsing,
+	// to tell whether it was changed or moved since
+	parsed       string
+	parsedParent Uid
 
 	Content string
 }
@@ -122,12 +92,14 @@ type Header struct {
 func NewHeader(status HeaderStatus, content string) Header {
 	header := Header{
 		status:   status,
+		Priority: option.None[Priority](),
 		Progress: option.None[Progress](),
 		Tags:     option.None[TagList](),
 
 		Parent:    option.None[Render](),
 		children:  []Render{},
 		schedule:  option.None[Schedule](),
+		logbook:   option.None[Logbook](),
 		embedding: option.None[embeddings.Embedding](),
 
 		Content: content,
@@ -140,6 +112,11 @@ func NewHeader(status HeaderStatus, content string) Header {
 
 // TODO: remove str from arguments and parse from reader only (prob make a new constructor)
 func NewHeaderFromString(str string, reader *reader.PeekReader) option.Option[Header] {
+	return NewHeaderWithKeywords(str, reader, CurrentConfig().TodoKeywords)
+}
+
+// NewHeaderWithKeywords parses a header, only the given TODO keywords are recognised as a status.
+func NewHeaderWithKeywords(str string, reader *reader.PeekReader, keywords TodoKeywords) option.Option[Header] {
 	// fmt.Fprintf(os.Stderr, "Parsing header %s\n", str)
 	if !strings.HasPrefix(str, "*") {
 		return option.None[Header]()
@@ -157,12 +134,18 @@ func NewHeaderFromString(str string, reader *reader.PeekReader) option.Option[He
 
 	part, end := next()
 
-	header.status = StatusFromString(part)
+	header.status = keywords.Parse(part)
 
 	if header.status != None {
 		part, end = next()
 	}
 
+	header.Priority = PriorityFromString(part)
+
+	if header.Priority.IsSome() {
+		part, end = next()
+	}
+
 	for end {
 		if slice.Any(SPECIAL_TOKENS, func(char string) bool { return strings.HasPrefix(part, char) }) {
 			switch part[0] {
@@ -194,6 +177,8 @@ func NewHeaderFromString(str string, reader *reader.PeekReader) option.Option[He
 	header.properties = NewPropertiesFromReader(reader)
 	header.properties.parent = &header
 
+	header.logbook = NewLogbookFromReader(reader)
+
 	return option.Some(header)
 }
 
@@ -212,6 +197,13 @@ func (h *Header) SetParent(render Render) error {
 	h.level = render.Level() + 1
 	h.location = len(render.Children())
 
+	// the drawers are indented by the level of the header, they can still point at the copy that was parsed
+	h.properties.parent = h
+	if schedule, ok := h.schedule.Split(); ok {
+		schedule.parent = h
+		h.schedule = option.Some(schedule)
+	}
+
 	return nil
 }
 
@@ -227,42 +219,101 @@ func (h *Header) Schedule() option.Option[*Schedule] {
 	return option.Ref(&h.schedule)
 }
 
-func (h *Header) SetContent(c string) {
-	h.Content = c
+// SetSchedule sets or with None clears the SCHEDULED, DEADLINE or CLOSED date of the header.
+// Like org mode a new date keeps the repeater and warning period of the date it replaces.
+func (h *Header) SetSchedule(status ScheduleStatus, ts option.Option[Timestamp]) {
+	schedule := h.schedule.UnwrapOr(NewSchedule(h))
+	schedule.parent = h
+
+	if updated, ok := ts.Split(); ok {
+		if old, ok := schedule.Values[status]; ok {
+			if updated.Repeater.IsNone() {
+				updated.Repeater = old.Repeater
+			}
+
+			if updated.Warning.IsNone() {
+				updated.Warning = old.Warning
+			}
+		}
+
+		updated.inactive = status == Closed
+		schedule.Values[status] = updated
+	} else {
+		delete(schedule.Values, status)
+	}
+
+	if len(schedule.Values) == 0 {
+		h.schedule = option.None[Schedule]()
+		return
+	}
+
+	h.schedule = option.Some(schedule)
 }
 
-func (h *Header) Render(builder *strings.Builder, depth int) {
-	builder.WriteString(strings.Repeat("*", h.Level()))
-	builder.WriteString(" ")
-	if h.status != None {
-		builder.WriteString(h.status.String())
-		builder.WriteString(" ")
+func (h *Header) Logbook() option.Option[*Logbook] {
+	return option.Ref(&h.logbook)
+}
+
+// ClockIn starts a clock in the logbook of the header, the logbook is created when needed.
+func (h *Header) ClockIn(t time.Time) error {
+	logbook := h.logbook.UnwrapOr(Logbook{})
+	if err := logbook.ClockIn(t); err != nil {
+		return err
 	}
-	builder.WriteString(h.Content)
 
-	h.Progress.Then(func(p Progress) {
-		builder.WriteRune(' ')
-		p.Render(builder)
-	})
+	h.logbook = option.Some(logbook)
 
-	h.Tags.Then(func(tl TagList) {
-		builder.WriteRune(' ')
-		tl.Render(builder)
+	return nil
+}
+
+// ClockOut stops the running clock in the logbook of the header.
+func (h *Header) ClockOut(t time.Time) (ClockEntry, error) {
+	logbook, ok := h.Logbook().Split()
+	if !ok {
+		return ClockEntry{}, errors.New("no clock is running")
+	}
+
+	return logbook.ClockOut(t)
+}
+
+// Clocked returns the total clocked time of the header and all its subheaders, like the org mode clocksum.
+func (h *Header) Clocked() (total time.Duration) {
+	h.logbook.ThenPtr(func(l *Logbook) {
+		total += l.Total()
 	})
 
+	for _, child := range h.children {
+		if header, ok := child.(*Header); ok {
+			total += header.Clocked()
+		}
+	}
+
+	return
+}
+
+func (h *Header) SetContent(c string) {
+	h.Content = c
+}
+
+func (h *Header) Render(builder *strings.Builder, depth int) {
+	h.source.write(builder, h.render)
+
 	if depth == 0 {
-		builder.WriteRune('\n')
 		return
 	}
 
-	builder.WriteRune('\n')
-
 	h.schedule.Then(func(s Schedule) {
 		s.Render(builder)
 	})
 
 	h.properties.Render(builder)
 
+	h.logbook.ThenPtr(func(l *Logbook) {
+		l.Render(builder, h.ChildIndentLevel())
+	})
+
+	h.source.writeTrailing(builder)
+
 	var body []Render
 	var subheaders []Render
 
@@ -283,9 +334,88 @@ func (h *Header) Render(builder *strings.Builder, depth int) {
 	}
 }
 
+func (h *Header) sourceRef() *Source {
+	return &h.source
+}
+
+// seal seals the headline and the schedule, properties and logbook below it.
+func (h *Header) seal() {
+	// update the progress cookie first, reading it later must not count as a change
+	h.CheckProgress()
+	h.source.seal(h.render)
+
+	if schedule, ok := h.schedule.Split(); ok {
+		schedule.source.seal(schedule.render)
+		h.schedule = option.Some(schedule)
+	}
+
+	h.properties.source.seal(h.properties.render)
+
+	if logbook, ok := h.logbook.Split(); ok {
+		logbook.source.seal(func(builder *strings.Builder) { logbook.render(builder, h.ChildIndentLevel()) })
+		h.logbook = option.Some(logbook)
+	}
+
+	h.parsed = h.ownLines()
+	h.parsedParent = h.ParentUid()
+}
+
+// changed reports whether the headline, schedule, properties or logbook of a parsed header differ
+// from how they were read or the header moved to another parent. A hidden ID does not count,
+// it is only written once the header changed, so a moved header keeps its UID.
+func (h *Header) changed() bool {
+	return h.ParentUid() != h.parsedParent || h.ownLines() != h.parsed
+}
+
+func (h *Header) ownLines() string {
+	builder := strings.Builder{}
+	h.render(&builder)
+
+	h.schedule.Then(func(s Schedule) {
+		s.render(&builder)
+	})
+
+	h.properties.render(&builder)
+
+	h.logbook.ThenPtr(func(l *Logbook) {
+		l.render(&builder, h.ChildIndentLevel())
+	})
+
+	return builder.String()
+}
+
+// render writes the headline.
+func (h *Header) render(builder *strings.Builder) {
+	builder.WriteString(strings.Repeat("*", h.Level()))
+	builder.WriteString(" ")
+	if h.status != None {
+		builder.WriteString(h.status.String())
+		builder.WriteString(" ")
+	}
+	h.Priority.Then(func(p Priority) {
+		p.Render(builder)
+		builder.WriteRune(' ')
+	})
+	builder.WriteString(h.Content)
+
+	h.Progress.Then(func(p Progress) {
+		builder.WriteRune(' ')
+		p.Render(builder)
+	})
+
+	h.Tags.Then(func(tl TagList) {
+		builder.WriteRune(' ')
+		tl.Render(builder)
+	})
+
+	builder.WriteRune('\n')
+}
+
 func (h *Header) CheckProgress() option.Option[Progress] {
+	keywords := h.TodoKeywords()
+
 	if h.Progress.IsNone() && h.status != None {
-		return option.Some(Progress{done: option.Some(h.status == Done)})
+		return option.Some(Progress{done: option.Some(keywords.IsDone(h.status))})
 	}
 
 	return option.Map(h.Progress, func(_ Progress) Progress {
@@ -308,8 +438,10 @@ func (h *Header) CheckProgress() option.Option[Progress] {
 		h.Progress = option.Some(progress)
 
 		if h.Progress.AndThen(func(p Progress) bool { return p.Done() }) && h.status != None {
-			h.status = Done
-		} else if h.Progress.AndThen(func(p Progress) bool { return p.Prog() }) && h.status != None && h.status != Done {
+			if !keywords.IsDone(h.status) {
+				h.status = keywords.DefaultDone()
+			}
+		} else if h.Progress.AndThen(func(p Progress) bool { return p.Prog() }) && h.status != None && !keywords.IsDone(h.status) && keywords.Contains(Prog) {
 			h.status = Prog
 		}
 
@@ -326,7 +458,7 @@ func (p *Header) Location(table map[Uid]int) (loc int) {
 		loc += parent.Location(table)
 
 		for i, child := range parent.ChildrenRec(-1) {
-			if child.Uid() == p.Uid() {
+			if child == p {
 				loc += i + 1
 				break
 			}
@@ -376,7 +508,7 @@ func (b *Header) ChildrenRec(depth int) (children []Render) {
 }
 
 func (b *Header) Uid() Uid {
-	return NewUid(b.properties.content["ID"].Int().Unwrap())
+	return NewUid(b.properties.content["ID"].String())
 }
 
 // GetParentUid returns the UID of the parent header, if it exists
@@ -448,9 +580,21 @@ func (h *Header) Status() RenderStatus {
 	return RenderStatus(h.status)
 }
 
+// SetStatus changes the status, completing a header sets its CLOSED date.
+// Like org mode completing a repeating task instead moves its dates to the next occurrence,
+// resets the status and records the time in the LAST_REPEAT property.
 func (h *Header) SetStatus(status HeaderStatus) {
-	if slices.Contains(DoneStatuses, status) && !slices.Contains(DoneStatuses, h.status) {
-		h.schedule = option.Some(h.schedule.UnwrapOr(NewSchedule(h)).AppendSchedule(Closed, time.Now(), true))
+	keywords := h.TodoKeywords()
+	now := Now()
+
+	if keywords.IsDone(status) && !keywords.IsDone(h.status) {
+		if h.Schedule().AndThen(func(s *Schedule) bool { return s.Repeat(now) }) {
+			h.status = keywords.DefaultActive()
+			h.SetProperty("LAST_REPEAT", NewTimestamp(now.Truncate(time.Minute), true).AsInactive().String())
+			return
+		}
+
+		h.schedule = option.Some(h.schedule.UnwrapOr(NewSchedule(h)).AppendSchedule(Closed, now, true))
 	}
 
 	h.status = status
@@ -484,10 +628,33 @@ func (h *Header) Path() string {
 	return h.Uid().String()
 }
 
+// SetProperty sets a property of the header, see ParsePropValue for how the value is typed.
 func (h *Header) SetProperty(key string, value string) {
-	h.properties.content[key] = &stringProperty{
-		str: value,
+	h.properties.Set(key, ParsePropValue(value))
+}
+
+// RemoveProperty removes a property from the header and reports whether it was set.
+func (h *Header) RemoveProperty(key string) bool {
+	return h.properties.Remove(key)
+}
+
+// PropertyKeys returns the property names of the header in the order of the drawer.
+func (h *Header) PropertyKeys() []string {
+	return h.properties.Keys()
+}
+
+// PropValue returns the typed value of a property.
+func (h *Header) PropValue(key string) option.Option[PropValue] {
+	if prop, ok := h.properties.content[key]; ok {
+		return option.Some(prop)
 	}
+
+	return option.None[PropValue]()
+}
+
+// PropertiesJSON returns the properties drawer of the header as a JSON object.
+func (h *Header) PropertiesJSON() string {
+	return h.properties.JSON()
 }
 
 func (h *Header) GetProperty(key string) option.Option[string] {
@@ -0,0 +1,88 @@
+package orgmcp
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// IDGenerator creates the ID property of headers that do not have one yet.
+type IDGenerator interface {
+	NewID() string
+}
+
+type IDFormat string
+
+const (
+	// IDNumeric generates 8 digit numbers, the format org-mcp has always used.
+	IDNumeric IDFormat = "numeric"
+	// IDUUID generates random UUIDs like `org-id-new` does with the default `org-id-method`.
+	IDUUID IDFormat = "uuid"
+)
+
+type randomIDs struct {
+	format IDFormat
+	rand   *rand.Rand
+}
+
+// NewIDGenerator returns a generator for the given format. With a seed the generated IDs
+// are the same on every run, which is meant for tests.
+func NewIDGenerator(format IDFormat, seed option.Option[uint64]) (IDGenerator, error) {
+	if format != IDNumeric && format != IDUUID {
+		return nil, fmt.Errorf("unknown id format %s, use %s or %s", format, IDNumeric, IDUUID)
+	}
+
+	source := rand.NewPCG(rand.Uint64(), rand.Uint64())
+	if s, ok := seed.Split(); ok {
+		source = rand.NewPCG(s, s)
+	}
+
+	return &randomIDs{format: format, rand: rand.New(source)}, nil
+}
+
+func (r *randomIDs) NewID() string {
+	if r.format == IDNumeric {
+		return strconv.Itoa(r.rand.IntN(100000000))
+	}
+
+	bytes := make([]byte, 16)
+	for i := range bytes {
+		bytes[i] = byte(r.rand.UintN(256))
+	}
+
+	// version 4, variant 10
+	bytes[6] = bytes[6]&0x0f | 0x40
+	bytes[8] = bytes[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", bytes[0:4], bytes[4:6], bytes[6:8], bytes[8:10], bytes[10:16])
+}
+
+var defaultIDs, _ = NewIDGenerator(IDNumeric, option.None[uint64]())
+
+// newID generates an ID with the configured generator.
+func newID() string {
+	if config.IDs != nil {
+		return config.IDs.NewID()
+	}
+
+	return defaultIDs.NewID()
+}
+
+// derivedID returns an ID in the configured format that only depends on the key,
+// so a header without an ID gets the same one every time the file is parsed.
+func derivedID(key string) string {
+	format := IDNumeric
+	if ids, ok := config.IDs.(*randomIDs); ok {
+		format = ids.format
+	}
+
+	hash := fnv.New64a()
+	hash.Write([]byte(key))
+
+	ids, _ := NewIDGenerator(format, option.Some(hash.Sum64()))
+
+	return ids.NewID()
+}
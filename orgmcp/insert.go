@@ -1,27 +1,37 @@
 package orgmcp
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
 
 func (of *OrgFile) Insert(index int, render Render) (err error) {
-	children := of.children[:index]
-	children = append(children, render)
-	of.children = append(children, of.children[index:]...)
+	if index < 0 || index > len(of.children) {
+		return fmt.Errorf("index %d out of range, there are %d children", index, len(of.children))
+	}
+
+	of.children = slices.Insert(of.children, index, render)
 
 	return
 }
 
 func (h *Header) Insert(index int, render Render) (err error) {
-	children := h.children[:index]
-	children = append(children, render)
-	h.children = append(children, h.children[index:]...)
+	if index < 0 || index > len(h.children) {
+		return fmt.Errorf("index %d out of range, there are %d children", index, len(h.children))
+	}
+
+	h.children = slices.Insert(h.children, index, render)
 
 	return
 }
 
 func (b *Bullet) Insert(index int, render Render) (err error) {
-	children := b.children[:index]
-	children = append(children, render)
-	b.children = append(children, b.children[index:]...)
+	if index < 0 || index > len(b.children) {
+		return fmt.Errorf("index %d out of range, there are %d children", index, len(b.children))
+	}
+
+	b.children = slices.Insert(b.children, index, render)
 
 	return
 }
@@ -29,3 +39,19 @@ func (b *Bullet) Insert(index int, render Render) (err error) {
 func (p *PlainText) Insert(index int, render Render) (err error) {
 	return errors.New("PlainText cannot have children")
 }
+
+func (k *Keyword) Insert(index int, render Render) (err error) {
+	return errors.New("Keyword cannot have children")
+}
+
+func (b *Block) Insert(index int, render Render) (err error) {
+	return errors.New("Block cannot have children")
+}
+
+func (d *Drawer) Insert(index int, render Render) (err error) {
+	return errors.New("Drawer cannot have children")
+}
+
+func (t *Table) Insert(index int, render Render) (err error) {
+	return errors.New("Table cannot have children")
+}
@@ -0,0 +1,219 @@
+package orgmcp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+	"github.com/p3rtang/org-mcp/utils/reader"
+)
+
+var keywordRegex = regexp.MustCompile(`^#\+([^\s:]+):(?:\s+(.*))?$`)
+
+// Keyword is an in-buffer setting line like `#+TITLE: My file` or `#+FILETAGS: :work:`.
+type Keyword struct {
+	key    string
+	value  string
+	indent int
+	index  int
+
+	parent option.Option[Render]
+	source Source
+	uid    uidCache
+}
+
+// Enforce that Keyword implements the Render interface at compile time
+var _ Render = (*Keyword)(nil)
+
+func NewKeyword(key string, value string) Keyword {
+	return Keyword{key: strings.ToUpper(key), value: strings.TrimSpace(value)}
+}
+
+// IsKeywordLine reports whether the given line (with or without indentation) is a `#+KEY: value` line.
+func IsKeywordLine(line string) bool {
+	return keywordRegex.MatchString(strings.TrimSpace(line))
+}
+
+func NewKeywordFromReader(reader *reader.PeekReader) option.Option[*Keyword] {
+	bytes, err := reader.PeekBytes('\n')
+	if err != nil && len(bytes) == 0 {
+		return option.None[*Keyword]()
+	}
+
+	matches := keywordRegex.FindStringSubmatch(strings.TrimSpace(string(bytes)))
+	if matches == nil {
+		return option.None[*Keyword]()
+	}
+
+	line, _ := reader.ReadBytes('\n')
+	indent := len(line) - len(strings.TrimLeft(string(line), " "))
+
+	keyword := NewKeyword(matches[1], matches[2])
+	// keep the key as it was written, `#+title:` is as valid as `#+TITLE:`
+	keyword.key = matches[1]
+	keyword.indent = indent
+
+	return option.Some(&keyword)
+}
+
+// Key returns the upper case key, keys are compared case insensitively like org mode does.
+func (k *Keyword) Key() string {
+	return strings.ToUpper(k.key)
+}
+
+func (k *Keyword) Value() string {
+	return k.value
+}
+
+func (k *Keyword) SetValue(value string) {
+	k.value = strings.TrimSpace(value)
+}
+
+func (k *Keyword) CheckProgress() option.Option[Progress] {
+	return option.None[Progress]()
+}
+
+func (k *Keyword) Render(builder *strings.Builder, depth int) {
+	k.source.write(builder, k.render)
+
+	if depth != 0 {
+		k.source.writeTrailing(builder)
+	}
+}
+
+func (k *Keyword) sourceRef() *Source {
+	return &k.source
+}
+
+func (k *Keyword) seal() {
+	k.source.seal(k.render)
+}
+
+func (k *Keyword) render(builder *strings.Builder) {
+	builder.WriteString(strings.Repeat(" ", k.indent))
+	fmt.Fprintf(builder, "#+%s:", k.key)
+
+	if k.value != "" {
+		builder.WriteRune(' ')
+		builder.WriteString(k.value)
+	}
+
+	builder.WriteRune('\n')
+}
+
+func (k *Keyword) IndentLevel() int {
+	return option.Map(k.parent, func(r Render) int {
+		return r.ChildIndentLevel()
+	}).UnwrapOr(0)
+}
+
+func (k *Keyword) ChildIndentLevel() int {
+	return k.IndentLevel()
+}
+
+func (k *Keyword) Level() int {
+	return option.Map(k.parent, func(r Render) int {
+		return r.Level() + 1
+	}).UnwrapOr(0)
+}
+
+func (k *Keyword) Location(table map[Uid]int) (loc int) {
+	if val, ok := table[k.Uid()]; ok {
+		return val
+	}
+
+	if parent, ok := k.parent.Split(); ok {
+		loc += parent.Location(table)
+
+		for i, child := range parent.Children() {
+			if child == k {
+				loc += i + 1
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func (k *Keyword) AddChildren(r ...Render) error {
+	return errors.New("Keyword cannot have children")
+}
+
+func (k *Keyword) SetParent(r Render) error {
+	k.parent = option.Some(r)
+	k.index = len(r.Children())
+	k.uid.reset()
+	k.indent = r.ChildIndentLevel()
+
+	return nil
+}
+
+func (k *Keyword) RemoveChildren(...Uid) error {
+	return errors.New("Keyword cannot have children")
+}
+
+func (k *Keyword) Children() []Render {
+	return []Render{}
+}
+
+func (k *Keyword) ChildrenRec(_ int) []Render {
+	return []Render{}
+}
+
+func (k *Keyword) Uid() Uid {
+	if k.parent.IsNone() {
+		return NewUid(-1)
+	}
+
+	return keyedUid(k.parent.Unwrap(), k)
+}
+
+// uidKey only uses the key, so changing the value keeps the UID.
+func (k *Keyword) uidKey() (string, string) {
+	return "k", k.Key()
+}
+
+func (k *Keyword) keyedUidCache() *uidCache {
+	return &k.uid
+}
+
+func (k *Keyword) ParentUid() Uid {
+	if k.parent.IsNone() {
+		return NewUid(0)
+	}
+
+	return k.parent.Unwrap().Uid()
+}
+
+func (k *Keyword) Status() RenderStatus {
+	return ""
+}
+
+func (k *Keyword) TagList() (list TagList) {
+	if parent, ok := k.parent.Split(); ok {
+		list = parent.TagList()
+	}
+
+	return
+}
+
+func (k *Keyword) Preview(length int) string {
+	preview := fmt.Sprintf("#+%s: %s", k.key, k.value)
+
+	if length < 0 || length >= len(preview) {
+		return preview
+	}
+
+	return preview[:length]
+}
+
+func (k *Keyword) Path() string {
+	if parent, ok := k.parent.Split(); ok {
+		return parent.Path() + "/" + k.Uid().String()
+	}
+
+	return k.Uid().String()
+}
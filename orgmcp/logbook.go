@@ -0,0 +1,250 @@
+package orgmcp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+	"github.com/p3rtang/org-mcp/utils/reader"
+)
+
+var clockRegex = regexp.MustCompile(`^CLOCK:\s*\[([^\]]+)\](?:--\[([^\]]+)\])?`)
+var clockTimeRegex = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})(?:\s+[^\s\d]+)?\s+(\d{1,2}:\d{2})$`)
+
+// ClockEntry is a single `CLOCK:` line, an entry without an end is a running clock.
+type ClockEntry struct {
+	Start time.Time
+	End   option.Option[time.Time]
+}
+
+func (c ClockEntry) Running() bool {
+	return c.End.IsNone()
+}
+
+// Duration returns the clocked time, a running clock has no duration yet.
+func (c ClockEntry) Duration() time.Duration {
+	return option.Map(c.End, func(end time.Time) time.Duration { return end.Sub(c.Start) }).UnwrapOr(0)
+}
+
+func (c ClockEntry) Render(builder *strings.Builder) {
+	builder.WriteString("CLOCK: [")
+	builder.WriteString(formatClockTime(c.Start))
+	builder.WriteRune(']')
+
+	c.End.Then(func(end time.Time) {
+		builder.WriteString("--[")
+		builder.WriteString(formatClockTime(end))
+		builder.WriteString("] => ")
+		builder.WriteString(FormatDuration(c.Duration()))
+	})
+}
+
+func formatClockTime(t time.Time) string {
+	return t.Format("2006-01-02") + " " + t.Weekday().String()[:3] + " " + t.Format("15:04")
+}
+
+func parseClockTime(str string) (time.Time, error) {
+	matches := clockTimeRegex.FindStringSubmatch(strings.TrimSpace(str))
+	if matches == nil {
+		return time.Time{}, fmt.Errorf("invalid clock time %s", str)
+	}
+
+	return time.ParseInLocation("2006-01-02 15:04", matches[1]+" "+matches[2], time.Local)
+}
+
+// FormatDuration formats a duration like org mode does, e.g. ` 1:23` or `12:05`.
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%2d:%02d", minutes/60, minutes%60)
+}
+
+func parseClockEntry(line string) option.Option[ClockEntry] {
+	matches := clockRegex.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return option.None[ClockEntry]()
+	}
+
+	start, err := parseClockTime(matches[1])
+	if err != nil {
+		return option.None[ClockEntry]()
+	}
+
+	entry := ClockEntry{Start: start, End: option.None[time.Time]()}
+
+	if matches[2] != "" {
+		end, err := parseClockTime(matches[2])
+		if err != nil {
+			return option.None[ClockEntry]()
+		}
+
+		entry.End = option.Some(end)
+	}
+
+	return option.Some(entry)
+}
+
+// logbookLine is either a clock entry or any other line like a state change note, kept as is.
+type logbookLine struct {
+	clock option.Option[ClockEntry]
+	raw   string
+}
+
+// Logbook is the `:LOGBOOK:` drawer of a header.
+type Logbook struct {
+	lines  []logbookLine
+	source Source
+}
+
+func NewLogbookFromReader(reader *reader.PeekReader) option.Option[Logbook] {
+	bytes, err := reader.PeekBytes('\n')
+	if err != nil && len(bytes) == 0 {
+		return option.None[Logbook]()
+	}
+
+	if strings.TrimSpace(string(bytes)) != ":LOGBOOK:" {
+		return option.None[Logbook]()
+	}
+
+	start := reader.Offset()
+	first, _ := reader.ReadBytes('\n')
+	consumed := first
+	indent := len(first) - len(strings.TrimLeft(string(first), " "))
+	logbook := Logbook{}
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		consumed = append(consumed, line...)
+
+		// a logbook without an end is not a logbook, the lines are read again as text
+		if IsHeaderLine(string(line)) || (err != nil && len(line) == 0) {
+			reader.Unread(consumed)
+			return option.None[Logbook]()
+		}
+
+		content := strings.TrimRight(string(line), "\r\n")
+
+		if strings.TrimSpace(content) == ":END:" {
+			break
+		}
+
+		if clock, ok := parseClockEntry(content).Split(); ok {
+			logbook.lines = append(logbook.lines, logbookLine{clock: option.Some(clock)})
+		} else {
+			// keep the continuation indent of notes, only the indentation of the drawer is stripped
+			lineIndent := len(content) - len(strings.TrimLeft(content, " "))
+			logbook.lines = append(logbook.lines, logbookLine{clock: option.None[ClockEntry](), raw: content[min(indent, lineIndent):]})
+		}
+
+		if err != nil {
+			reader.Unread(consumed)
+			return option.None[Logbook]()
+		}
+	}
+
+	logbook.source.capture(reader.Text(start, reader.Offset()))
+
+	return option.Some(logbook)
+}
+
+// Clocks returns all clock entries, the most recent entry comes first.
+func (l *Logbook) Clocks() (clocks []ClockEntry) {
+	for _, line := range l.lines {
+		line.clock.Then(func(c ClockEntry) {
+			clocks = append(clocks, c)
+		})
+	}
+
+	return
+}
+
+// Running returns the running clock if there is one.
+func (l *Logbook) Running() option.Option[ClockEntry] {
+	for _, clock := range l.Clocks() {
+		if clock.Running() {
+			return option.Some(clock)
+		}
+	}
+
+	return option.None[ClockEntry]()
+}
+
+// Total returns the sum of all finished clock entries.
+func (l *Logbook) Total() (total time.Duration) {
+	for _, clock := range l.Clocks() {
+		total += clock.Duration()
+	}
+
+	return
+}
+
+// ClockIn starts a new clock, like Emacs new entries are added at the top of the drawer.
+func (l *Logbook) ClockIn(t time.Time) error {
+	if l.Running().IsSome() {
+		return errors.New("a clock is already running")
+	}
+
+	entry := ClockEntry{Start: t.Truncate(time.Minute), End: option.None[time.Time]()}
+	l.lines = append([]logbookLine{{clock: option.Some(entry)}}, l.lines...)
+
+	return nil
+}
+
+// ClockOut stops the running clock and returns the finished entry.
+func (l *Logbook) ClockOut(t time.Time) (entry ClockEntry, err error) {
+	for i, line := range l.lines {
+		clock, ok := line.clock.Split()
+		if !ok || !clock.Running() {
+			continue
+		}
+
+		end := t.Truncate(time.Minute)
+		if end.Before(clock.Start) {
+			return entry, errors.New("a clock cannot end before it started")
+		}
+
+		clock.End = option.Some(end)
+		l.lines[i].clock = option.Some(clock)
+
+		return clock, nil
+	}
+
+	return entry, errors.New("no clock is running")
+}
+
+// Render writes the drawer with the given indentation, an empty logbook is not written at all.
+func (l *Logbook) Render(builder *strings.Builder, indentLevel int) {
+	if l == nil {
+		return
+	}
+
+	l.source.write(builder, func(builder *strings.Builder) { l.render(builder, indentLevel) })
+}
+
+func (l *Logbook) render(builder *strings.Builder, indentLevel int) {
+	if len(l.lines) == 0 {
+		return
+	}
+
+	indent := strings.Repeat(" ", indentLevel)
+
+	builder.WriteString(indent)
+	builder.WriteString(":LOGBOOK:\n")
+
+	for _, line := range l.lines {
+		builder.WriteString(indent)
+
+		if clock, ok := line.clock.Split(); ok {
+			clock.Render(builder)
+		} else {
+			builder.WriteString(line.raw)
+		}
+
+		builder.WriteRune('\n')
+	}
+
+	builder.WriteString(indent)
+	builder.WriteString(":END:\n")
+}
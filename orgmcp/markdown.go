@@ -40,7 +40,12 @@ func (h *Header) RenderMarkdown(builder *strings.Builder, depth int) {
 	builder.WriteString(strings.Repeat("#", h.level))
 
 	builder.WriteRune(' ')
-	h.status.RenderMarkdown(builder)
+	h.status.RenderMarkdown(builder, h.TodoKeywords())
+
+	h.Priority.Then(func(p Priority) {
+		p.Render(builder)
+		builder.WriteRune(' ')
+	})
 
 	builder.WriteString(orgToMarkdownStyle(h.Content))
 	builder.WriteRune(' ')
@@ -107,7 +112,66 @@ func (t *PlainText) RenderMarkdown(builder *strings.Builder, depth int) {
 	builder.WriteString(orgToMarkdownStyle(t.content))
 }
 
-func (s HeaderStatus) RenderMarkdown(builder *strings.Builder) {
+func (k *Keyword) RenderMarkdown(builder *strings.Builder, depth int) {
+	fmt.Fprintf(builder, "<!-- #+%s: %s -->\n", k.key, k.value)
+}
+
+func (b *Block) RenderMarkdown(builder *strings.Builder, depth int) {
+	indent := strings.Repeat(" ", b.indent)
+	content := strings.Split(b.Content(), "\n")
+
+	switch b.kind {
+	case "SRC", "EXAMPLE", "EXPORT":
+		fmt.Fprintf(builder, "%s```%s\n", indent, b.Language().UnwrapOr(""))
+		for _, line := range content {
+			builder.WriteString(indent)
+			builder.WriteString(line)
+			builder.WriteRune('\n')
+		}
+		fmt.Fprintf(builder, "%s```\n", indent)
+	case "QUOTE":
+		for _, line := range content {
+			builder.WriteString(indent)
+			builder.WriteString("> ")
+			builder.WriteString(orgToMarkdownStyle(line))
+			builder.WriteRune('\n')
+		}
+	default:
+		for _, line := range content {
+			builder.WriteString(indent)
+			builder.WriteString(orgToMarkdownStyle(line))
+			builder.WriteRune('\n')
+		}
+	}
+}
+
+// RenderMarkdown writes nothing, like org mode drawers are not exported.
+func (d *Drawer) RenderMarkdown(builder *strings.Builder, depth int) {}
+
+func (t *Table) RenderMarkdown(builder *strings.Builder, depth int) {
+	rows := t.Rows()
+	columns := t.columnCount()
+
+	for i, row := range rows {
+		builder.WriteRune('|')
+		for col := range columns {
+			cell := ""
+			if col < len(row) {
+				cell = row[col]
+			}
+
+			fmt.Fprintf(builder, " %s |", orgToMarkdownStyle(cell))
+		}
+		builder.WriteRune('\n')
+
+		// markdown tables always need a header row
+		if i == 0 {
+			builder.WriteString("|" + strings.Repeat(" --- |", columns) + "\n")
+		}
+	}
+}
+
+func (s HeaderStatus) RenderMarkdown(builder *strings.Builder, keywords TodoKeywords) {
 	if s == None {
 		return
 	}
@@ -119,13 +183,13 @@ func (s HeaderStatus) RenderMarkdown(builder *strings.Builder) {
 		return
 	}
 
-	color := "green"
-	switch s {
-	case Todo:
-		color = "red"
-	case Next:
+	color := "red"
+	switch {
+	case keywords.IsDone(s):
+		color = "green"
+	case s == Next:
 		color = "orange"
-	case Prog:
+	case s == Prog:
 		color = "blue"
 	}
 
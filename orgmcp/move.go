@@ -1,6 +1,12 @@
 package orgmcp
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
 
 type MoveOperationKind interface {
 	SwapOperation | IndexOperation | IndexRelativeOperation
@@ -61,6 +67,11 @@ func (op MoveOperation) MoveSlice(slice []Render) (res []Render, err error) {
 			return
 		}
 
+		if op.Index.to < 0 || op.Index.to >= len(slice) {
+			err = errors.New("New index out of bounds")
+			return
+		}
+
 		item := slice[index]
 		slice = append(slice[:index], slice[index+1:]...)
 		res = append(slice[:op.Index.to], append([]Render{item}, slice[op.Index.to:]...)...)
@@ -98,16 +109,28 @@ type SwapOperation struct {
 	uidRight Uid
 }
 
+func NewSwapOperation(left Uid, right Uid) SwapOperation {
+	return SwapOperation{uidLeft: left, uidRight: right}
+}
+
 type IndexOperation struct {
 	uid Uid
 	to  int
 }
 
+func NewIndexOperation(uid Uid, to int) IndexOperation {
+	return IndexOperation{uid: uid, to: to}
+}
+
 type IndexRelativeOperation struct {
 	uid    Uid
 	offset int
 }
 
+func NewIndexRelativeOperation(uid Uid, offset int) IndexRelativeOperation {
+	return IndexRelativeOperation{uid: uid, offset: offset}
+}
+
 func (of *OrgFile) Move(op MoveOperation) (err error) {
 	c, err := op.MoveSlice(of.children)
 	if err != nil {
@@ -141,3 +164,357 @@ func (b *Bullet) Move(op MoveOperation) (err error) {
 func (p *PlainText) Move(op MoveOperation) (err error) {
 	return errors.New("PlainText cannot have children")
 }
+
+func (k *Keyword) Move(op MoveOperation) (err error) {
+	return errors.New("Keyword cannot have children")
+}
+
+func (b *Block) Move(op MoveOperation) (err error) {
+	return errors.New("Block cannot have children")
+}
+
+func (d *Drawer) Move(op MoveOperation) (err error) {
+	return errors.New("Drawer cannot have children")
+}
+
+func (t *Table) Move(op MoveOperation) (err error) {
+	return errors.New("Table cannot have children")
+}
+
+// parentOf returns the item that has the given item as a direct child.
+func (of *OrgFile) parentOf(item Render) (Render, bool) {
+	for _, r := range append([]Render{of}, of.ChildrenRec(-1)...) {
+		if slices.Contains(r.Children(), item) {
+			return r, true
+		}
+	}
+
+	return nil, false
+}
+
+// sameKind reports whether both items are headers or both are part of the body of a header.
+func sameKind(a Render, b Render) bool {
+	_, aHeader := a.(*Header)
+	_, bHeader := b.(*Header)
+
+	return aHeader == bHeader
+}
+
+// childIndex converts an index among the siblings of the same kind as item to an index into siblings,
+// which must not contain item. Body items always come before sub headers. An index of -1 is after the last one.
+func childIndex(siblings []Render, item Render, index int) (int, error) {
+	positions := []int{}
+	firstHeader := len(siblings)
+
+	for i, sibling := range siblings {
+		if sameKind(sibling, item) {
+			positions = append(positions, i)
+		}
+
+		if _, ok := sibling.(*Header); ok && firstHeader == len(siblings) {
+			firstHeader = i
+		}
+	}
+
+	if index == -1 {
+		index = len(positions)
+	}
+
+	if index < 0 || index > len(positions) {
+		return 0, fmt.Errorf("index %d out of range, there are %d siblings", index, len(positions))
+	}
+
+	if index < len(positions) {
+		return positions[index], nil
+	}
+
+	if len(positions) > 0 {
+		return positions[len(positions)-1] + 1, nil
+	}
+
+	if _, ok := item.(*Header); ok {
+		return len(siblings), nil
+	}
+
+	return firstHeader, nil
+}
+
+// siblingIndex returns the index of an item among the siblings of the same kind.
+func siblingIndex(siblings []Render, item Render) int {
+	index := 0
+
+	for _, sibling := range siblings {
+		if sibling == item {
+			return index
+		}
+
+		if sameKind(sibling, item) {
+			index += 1
+		}
+	}
+
+	return -1
+}
+
+// reparent sets the parent of an item and everything below it again after a move,
+// so header levels and the indentation of body items follow the new position.
+func reparent(item Render, parent Render) {
+	item.SetParent(parent)
+
+	for _, child := range item.Children() {
+		reparent(child, item)
+	}
+}
+
+// MoveTo moves an item with its subtree under a new parent. The index counts the siblings of the same kind,
+// sub headers for a header and body items like bullets and text for everything else, -1 appends the item.
+func (of *OrgFile) MoveTo(uid Uid, parentUid Uid, index int) error {
+	item, ok := of.GetUid(uid).Split()
+	if !ok || item == Render(of) {
+		return fmt.Errorf("Uid %s not found", uid)
+	}
+
+	parent, ok := of.GetUid(parentUid).Split()
+	if !ok {
+		return fmt.Errorf("Parent uid %s not found", parentUid)
+	}
+
+	return of.moveTo(item, parent, index)
+}
+
+// canMoveUnder checks that item can become a child of parent.
+func canMoveUnder(item Render, parent Render) error {
+	switch parent.(type) {
+	case *OrgFile, *Header:
+	case *Bullet:
+		if _, ok := item.(*Header); ok {
+			return errors.New("a header can only be moved under another header or the root of the file")
+		}
+	default:
+		return fmt.Errorf("%s cannot have children", parent.Uid())
+	}
+
+	if parent == item || slices.Contains(item.ChildrenRec(-1), parent) {
+		return fmt.Errorf("cannot move %s into its own subtree", item.Uid())
+	}
+
+	return nil
+}
+
+func (of *OrgFile) moveTo(item Render, parent Render, index int) error {
+	if err := canMoveUnder(item, parent); err != nil {
+		return err
+	}
+
+	oldParent, ok := of.parentOf(item)
+	if !ok {
+		return fmt.Errorf("Parent of %s not found", item.Uid())
+	}
+
+	siblings := slices.DeleteFunc(slices.Clone(parent.Children()), func(r Render) bool { return r == item })
+
+	at, err := childIndex(siblings, item, index)
+	if err != nil {
+		return err
+	}
+
+	if err := oldParent.RemoveChildren(item.Uid()); err != nil {
+		return err
+	}
+
+	if err := parent.Insert(at, item); err != nil {
+		return err
+	}
+
+	reparent(item, parent)
+	of.Reindex()
+
+	return nil
+}
+
+// MoveToFile moves an item with its subtree under a parent in another file, the index works like in MoveTo.
+// Headers keep their IDs, properties and schedules, so an ID of the subtree that is already used
+// in the target file is an error instead of a duplicate. Both files are reindexed, writing them is up to the caller.
+func (of *OrgFile) MoveToFile(uid Uid, target *OrgFile, parentUid Uid, index int) error {
+	if target == of {
+		return of.MoveTo(uid, parentUid, index)
+	}
+
+	item, ok := of.GetUid(uid).Split()
+	if !ok || item == Render(of) {
+		return fmt.Errorf("Uid %s not found", uid)
+	}
+
+	parent, ok := target.GetUid(parentUid).Split()
+	if !ok {
+		return fmt.Errorf("Parent uid %s not found in %s", parentUid, target.Name())
+	}
+
+	if err := canMoveUnder(item, parent); err != nil {
+		return err
+	}
+
+	for _, r := range append([]Render{item}, item.ChildrenRec(-1)...) {
+		header, ok := r.(*Header)
+		if !ok || header.properties.generated {
+			continue
+		}
+
+		if _, found := target.items[header.Uid()]; found {
+			return fmt.Errorf("ID %s of '%s' is already used in %s", header.Uid(), header.Content, target.Name())
+		}
+	}
+
+	oldParent, ok := of.parentOf(item)
+	if !ok {
+		return fmt.Errorf("Parent of %s not found", item.Uid())
+	}
+
+	at, err := childIndex(parent.Children(), item, index)
+	if err != nil {
+		return err
+	}
+
+	if err := oldParent.RemoveChildren(item.Uid()); err != nil {
+		return err
+	}
+
+	if err := parent.Insert(at, item); err != nil {
+		return err
+	}
+
+	reparent(item, parent)
+	of.Reindex()
+	target.Reindex()
+
+	return nil
+}
+
+// Reorder moves an item to the given index among its siblings of the same kind, -1 moves it to the end.
+func (of *OrgFile) Reorder(uid Uid, index int) error {
+	item, ok := of.GetUid(uid).Split()
+	if !ok || item == Render(of) {
+		return fmt.Errorf("Uid %s not found", uid)
+	}
+
+	parent, ok := of.parentOf(item)
+	if !ok {
+		return fmt.Errorf("Parent of %s not found", uid)
+	}
+
+	siblings := slices.DeleteFunc(slices.Clone(parent.Children()), func(r Render) bool { return r == item })
+
+	at, err := childIndex(siblings, item, index)
+	if err != nil {
+		return err
+	}
+
+	if err := parent.Move(NewMoveOperation(NewIndexOperation(item.Uid(), at))); err != nil {
+		return err
+	}
+
+	of.Reindex()
+
+	return nil
+}
+
+// ReorderBy moves an item by an offset among its siblings of the same kind, a negative offset moves it up.
+func (of *OrgFile) ReorderBy(uid Uid, offset int) error {
+	item, ok := of.GetUid(uid).Split()
+	if !ok || item == Render(of) {
+		return fmt.Errorf("Uid %s not found", uid)
+	}
+
+	parent, ok := of.parentOf(item)
+	if !ok {
+		return fmt.Errorf("Parent of %s not found", uid)
+	}
+
+	index := siblingIndex(parent.Children(), item) + offset
+	if index < 0 {
+		return fmt.Errorf("cannot move %s up by %d, it is at index %d", uid, -offset, index-offset)
+	}
+
+	return of.Reorder(item.Uid(), index)
+}
+
+// Swap swaps two siblings of the same kind.
+func (of *OrgFile) Swap(left Uid, right Uid) error {
+	leftItem, ok := of.GetUid(left).Split()
+	if !ok {
+		return fmt.Errorf("Uid %s not found", left)
+	}
+
+	rightItem, ok := of.GetUid(right).Split()
+	if !ok {
+		return fmt.Errorf("Uid %s not found", right)
+	}
+
+	parent, _ := of.parentOf(leftItem)
+	if other, _ := of.parentOf(rightItem); parent == nil || parent != other {
+		return fmt.Errorf("%s and %s are not siblings", left, right)
+	}
+
+	if !sameKind(leftItem, rightItem) {
+		return errors.New("a header can only be swapped with another header")
+	}
+
+	if err := parent.Move(NewMoveOperation(NewSwapOperation(leftItem.Uid(), rightItem.Uid()))); err != nil {
+		return err
+	}
+
+	of.Reindex()
+
+	return nil
+}
+
+// Promote moves a header one level up, it becomes the next sibling of its parent.
+func (of *OrgFile) Promote(uid Uid) error {
+	header, ok := option.Cast[Render, *Header](of.GetUid(uid)).Split()
+	if !ok {
+		return fmt.Errorf("Uid %s is not a header", uid)
+	}
+
+	parent, ok := of.parentOf(header)
+	parentHeader, isHeader := parent.(*Header)
+	if !ok || !isHeader {
+		return fmt.Errorf("%s is already a top level header", uid)
+	}
+
+	grandparent, ok := of.parentOf(parentHeader)
+	if !ok {
+		return fmt.Errorf("Parent of %s not found", parentHeader.Uid())
+	}
+
+	return of.moveTo(header, grandparent, siblingIndex(grandparent.Children(), parentHeader)+1)
+}
+
+// Demote moves a header one level down, it becomes the last child of the header before it, like org-demote-subtree.
+func (of *OrgFile) Demote(uid Uid) error {
+	header, ok := option.Cast[Render, *Header](of.GetUid(uid)).Split()
+	if !ok {
+		return fmt.Errorf("Uid %s is not a header", uid)
+	}
+
+	parent, ok := of.parentOf(header)
+	if !ok {
+		return fmt.Errorf("Parent of %s not found", uid)
+	}
+
+	var previous *Header
+	for _, sibling := range parent.Children() {
+		if sibling == Render(header) {
+			break
+		}
+
+		if h, ok := sibling.(*Header); ok {
+			previous = h
+		}
+	}
+
+	if previous == nil {
+		return fmt.Errorf("%s has no header before it to become a child of", uid)
+	}
+
+	return of.moveTo(header, previous, -1)
+}
@@ -2,7 +2,6 @@ package orgmcp
 
 import (
 	"errors"
-	"fmt"
 	"strings"
 
 	"github.com/p3rtang/org-mcp/utils/option"
@@ -15,6 +14,8 @@ type PlainText struct {
 	index   int
 
 	parent option.Option[Render]
+	source Source
+	uid    uidCache
 }
 
 // Enforce that PlainText implements the Render interface at compile time
@@ -30,7 +31,8 @@ func NewPlainText(content string) PlainText {
 func NewPlainTextFromReader(reader *reader.PeekReader) option.Option[*PlainText] {
 	line, err := reader.ReadBytes('\n')
 
-	if err != nil {
+	// the last line of a file does not need to end with a newline
+	if err != nil && len(line) == 0 {
 		return option.None[*PlainText]()
 	}
 
@@ -51,6 +53,22 @@ func (p *PlainText) CheckProgress() option.Option[Progress] {
 }
 
 func (p *PlainText) Render(builder *strings.Builder, depth int) {
+	p.source.write(builder, p.render)
+
+	if depth != 0 {
+		p.source.writeTrailing(builder)
+	}
+}
+
+func (p *PlainText) sourceRef() *Source {
+	return &p.source
+}
+
+func (p *PlainText) seal() {
+	p.source.seal(p.render)
+}
+
+func (p *PlainText) render(builder *strings.Builder) {
 	builder.WriteString(strings.Repeat(" ", p.indent))
 	builder.WriteString(p.content)
 	builder.WriteByte('\n')
@@ -81,7 +99,7 @@ func (p *PlainText) Location(table map[Uid]int) (loc int) {
 		loc += parent.Location(table)
 
 		for i, child := range parent.Children() {
-			if child.Uid() == p.Uid() {
+			if child == p {
 				loc += i + 1
 				break
 			}
@@ -98,6 +116,7 @@ func (p *PlainText) AddChildren(r ...Render) error {
 func (p *PlainText) SetParent(r Render) error {
 	p.parent = option.Some(r)
 	p.index = len(r.Children())
+	p.uid.reset()
 	p.indent = r.ChildIndentLevel()
 
 	return nil
@@ -120,8 +139,16 @@ func (p *PlainText) Uid() Uid {
 		return NewUid(-1)
 	}
 
-	return NewUid(fmt.Sprintf("%s.t%d", p.parent.Unwrap().Uid(), p.index))
+	return keyedUid(p.parent.Unwrap(), p)
+}
+
+func (p *PlainText) uidKey() (string, string) {
+	return "t", p.content
+}
+func (p *PlainText) keyedUidCache() *uidCache {
+	return &p.uid
 }
+
 func (p *PlainText) ParentUid() Uid {
 	if p.parent.IsNone() {
 		return NewUid(0)
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/p3rtang/org-mcp/utils/option"
 	"github.com/p3rtang/org-mcp/utils/slice"
 )
 
@@ -16,6 +17,7 @@ const (
 	ColPreview       Column = "PREVIEW"
 	ColContent       Column = "CONTENT"
 	ColStatus        Column = "STATUS"
+	ColPriority      Column = "PRIORITY"
 	ColProgress      Column = "PROGRESS"
 	ColParent        Column = "PARENT"
 	ColChildrenCount Column = "CHILDREN_COUNT"
@@ -25,6 +27,8 @@ const (
 	ColScheduled     Column = "SCHEDULED"
 	ColDeadline      Column = "DEADLINE"
 	ColClosed        Column = "CLOSED"
+	ColClocked       Column = "CLOCKED"
+	ColProperties    Column = "PROPERTIES"
 )
 
 var (
@@ -33,6 +37,7 @@ var (
 	ColPreviewValue       = ColPreview
 	ColContentValue       = ColContent
 	ColStatusValue        = ColStatus
+	ColPriorityValue      = ColPriority
 	ColProgressValue      = ColProgress
 	ColParentValue        = ColParent
 	ColChildrenCountValue = ColChildrenCount
@@ -42,6 +47,8 @@ var (
 	ColScheduledValue     = ColScheduled
 	ColDeadlineValue      = ColDeadline
 	ColClosedValue        = ColClosed
+	ColClockedValue       = ColClocked
+	ColPropertiesValue    = ColProperties
 )
 
 var AllColumns = []Column{
@@ -50,6 +57,7 @@ var AllColumns = []Column{
 	ColPreview,
 	ColContent,
 	ColStatus,
+	ColPriority,
 	ColProgress,
 	ColParent,
 	ColChildrenCount,
@@ -59,6 +67,8 @@ var AllColumns = []Column{
 	ColScheduled,
 	ColDeadline,
 	ColClosed,
+	ColClocked,
+	ColProperties,
 }
 
 var AllColumnsStr = strings.Join(slice.Map(AllColumns, func(c Column) string { return c.String() }), ", ")
@@ -82,6 +92,10 @@ func (c Column) Value(r Render, quoteChars string) (val string) {
 		}
 	case ColStatus:
 		val = r.Status().String()
+	case ColPriority:
+		if header, ok := r.(*Header); ok {
+			val = option.Map(header.Priority, Priority.String).UnwrapOr("")
+		}
 	case ColProgress:
 		if p, ok := r.CheckProgress().Split(); ok && p.Total > 0 {
 			val = fmt.Sprintf("%d/%d", p.Complete, p.Total)
@@ -100,36 +114,22 @@ func (c Column) Value(r Render, quoteChars string) (val string) {
 	case ColPath:
 		val = r.Path()
 	case ColScheduled:
-		if header, ok := r.(*Header); ok && header.Schedule().IsSome() {
-			date := header.Schedule().Unwrap().Values[Scheduled]
-			if !date.T.IsZero() {
-				if date.withTime {
-					val = date.T.Format("2006-01-02 15:04")
-				} else {
-					val = date.T.Format("2006-01-02")
-				}
-			}
-		}
+		val = scheduleValue(r, Scheduled)
 	case ColDeadline:
-		if header, ok := r.(*Header); ok && header.Schedule().IsSome() {
-			date := header.Schedule().Unwrap().Values[Deadline]
-			if !date.T.IsZero() {
-				if date.withTime {
-					val = date.T.Format("2006-01-02 15:04")
-				} else {
-					val = date.T.Format("2006-01-02")
-				}
+		val = scheduleValue(r, Deadline)
+	case ColClosed:
+		val = scheduleValue(r, Closed)
+	case ColClocked:
+		if header, ok := r.(*Header); ok {
+			if clocked := header.Clocked(); clocked > 0 {
+				val = strings.TrimSpace(FormatDuration(clocked))
 			}
 		}
-	case ColClosed:
-		if header, ok := r.(*Header); ok && header.Schedule().IsSome() {
-			date := header.Schedule().Unwrap().Values[Closed]
-			if !date.T.IsZero() {
-				if date.withTime {
-					val = date.T.Format("2006-01-02 15:04")
-				} else {
-					val = date.T.Format("2006-01-02")
-				}
+	case ColProperties:
+		if header, ok := r.(*Header); ok {
+			val = header.PropertiesJSON()
+			if strings.ContainsAny(val, quoteChars) {
+				val = fmt.Sprintf("\"%s\"", strings.ReplaceAll(val, "\"", "\"\""))
 			}
 		}
 	}
@@ -137,6 +137,22 @@ func (c Column) Value(r Render, quoteChars string) (val string) {
 	return
 }
 
+// scheduleValue returns the date of a SCHEDULED, DEADLINE or CLOSED entry including its times and ranges.
+func scheduleValue(r Render, status ScheduleStatus) string {
+	header, ok := r.(*Header)
+	if !ok {
+		return ""
+	}
+
+	return option.Map(header.Schedule(), func(s *Schedule) string {
+		if date, ok := s.Values[status]; ok && !date.T.IsZero() {
+			return date.Format()
+		}
+
+		return ""
+	}).UnwrapOr("")
+}
+
 func (c *Column) String() string {
 	return string(*c)
 }
@@ -155,6 +171,8 @@ func (c *Column) UnmarshalJSON(input []byte) error {
 		*c = ColContent
 	case "STATUS":
 		*c = ColStatus
+	case "PRIORITY":
+		*c = ColPriority
 	case "PROGRESS":
 		*c = ColProgress
 	case "PARENT":
@@ -173,6 +191,10 @@ func (c *Column) UnmarshalJSON(input []byte) error {
 		*c = ColDeadline
 	case "CLOSED":
 		*c = ColClosed
+	case "CLOCKED":
+		*c = ColClocked
+	case "PROPERTIES":
+		*c = ColProperties
 	default:
 		return fmt.Errorf("Unknown column type %s\n, potential values are: %s\n", col, AllColumnsStr)
 	}
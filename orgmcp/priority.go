@@ -0,0 +1,45 @@
+package orgmcp
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+var priorityRegex = regexp.MustCompile(`^\[#([A-Za-z]|[0-9]{1,2})\]$`)
+
+// Priority is the value of a priority cookie like `[#A]`.
+// Org mode uses the letters A to C by default, numeric priorities are supported as well.
+type Priority string
+
+// PriorityFromString parses a priority cookie, e.g. `[#A]`.
+func PriorityFromString(str string) option.Option[Priority] {
+	matches := priorityRegex.FindStringSubmatch(str)
+	if matches == nil {
+		return option.None[Priority]()
+	}
+
+	return option.Some(Priority(strings.ToUpper(matches[1])))
+}
+
+// NewPriority parses a priority value as passed in by a client, with or without the cookie syntax, e.g. `A` or `[#A]`.
+func NewPriority(str string) option.Option[Priority] {
+	str = strings.TrimSpace(str)
+
+	if !strings.HasPrefix(str, "[") {
+		str = "[#" + str + "]"
+	}
+
+	return PriorityFromString(str)
+}
+
+func (p Priority) String() string {
+	return string(p)
+}
+
+func (p Priority) Render(builder *strings.Builder) {
+	builder.WriteString("[#")
+	builder.WriteString(string(p))
+	builder.WriteRune(']')
+}
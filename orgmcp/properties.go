@@ -1,10 +1,13 @@
 package orgmcp
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/p3rtang/org-mcp/utils/option"
 	"github.com/p3rtang/org-mcp/utils/reader"
-	"math/rand"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -16,20 +19,61 @@ type PropValue interface {
 	Int() option.Option[int]
 }
 
+// ParsePropValue parses a property value into a typed PropValue.
+// Active or inactive timestamps become dates, whole numbers become ints and decimals like 1.5 floats,
+// values are only typed when they render back to exactly the same string.
+func ParsePropValue(str string) PropValue {
+	str = strings.TrimSpace(str)
+
+	if strings.HasPrefix(str, "[") || strings.HasPrefix(str, "<") {
+		if ts, err := ParseTimestamp(str); err == nil && ts.String() == str {
+			return &dateProperty{timestamp: ts}
+		}
+	}
+
+	if num, err := strconv.Atoi(str); err == nil && strconv.Itoa(num) == str {
+		return &intProperty{num: num}
+	}
+
+	if num, err := strconv.ParseFloat(str, 64); err == nil && !math.IsInf(num, 0) && !math.IsNaN(num) &&
+		strconv.FormatFloat(num, 'f', -1, 64) == str {
+		return &floatProperty{num: num}
+	}
+
+	return &stringProperty{str: str}
+}
+
+// ValidateProperty checks that a key and value can be written to a properties drawer.
+func ValidateProperty(key string, value string) error {
+	if key == "" || strings.ContainsAny(key, ": \t\n") {
+		return fmt.Errorf("invalid property name '%s', names cannot be empty or contain colons or whitespace", key)
+	}
+
+	if strings.EqualFold(key, "END") || strings.EqualFold(key, "PROPERTIES") {
+		return fmt.Errorf("invalid property name '%s'", key)
+	}
+
+	if strings.Contains(value, "\n") {
+		return errors.New("property values cannot span multiple lines")
+	}
+
+	return nil
+}
+
 type dateProperty struct {
-	time time.Time
+	timestamp Timestamp
 }
 
 func (d *dateProperty) Date() option.Option[time.Time] {
-	return option.Some(d.time)
+	return option.Some(d.timestamp.T)
 }
 
 func (d *dateProperty) String() string {
-	return d.time.String()
+	return d.timestamp.String()
 }
 
 func (d *dateProperty) Int() option.Option[int] {
-	return option.Some(int(d.time.UnixMilli()))
+	return option.Some(int(d.timestamp.T.UnixMilli()))
 }
 
 type stringProperty struct {
@@ -68,19 +112,42 @@ func (i *intProperty) Int() option.Option[int] {
 	return option.Some(i.num)
 }
 
+type floatProperty struct {
+	num float64
+}
+
+func (f *floatProperty) Date() option.Option[time.Time] {
+	return option.None[time.Time]()
+}
+
+func (f *floatProperty) String() string {
+	return strconv.FormatFloat(f.num, 'f', -1, 64)
+}
+
+func (f *floatProperty) Int() option.Option[int] {
+	return option.None[int]()
+}
+
+// Properties is the properties drawer of a header, keys keeps the order of the drawer
+// so rendering a parsed drawer gives back the same lines.
 type Properties struct {
 	parent  Render
 	content map[string]PropValue
+	keys    []string
+	source  Source
+	// generated is set when the ID was not read from the file
+	generated bool
+	// hidden is set when the header was read without an ID, the ID is only written once the header changes
+	hidden bool
 }
 
 // generateUID returns an 8-digit pseudo-random identifier as a string.
-func NewPropertiesWithUID(parent *Header) Properties {
-	return Properties{
-		content: map[string]PropValue{
-			"ID": &intProperty{num: rand.Intn(100000000)},
-		},
-		parent: parent,
-	}
+func NewPropertiesWithUID(parent *Header) (p Properties) {
+	p.content = make(map[string]PropValue)
+	p.parent = parent
+	p.generateID()
+
+	return
 }
 
 func NewPropertiesFromReader(reader *reader.PeekReader) (p Properties) {
@@ -88,9 +155,9 @@ func NewPropertiesFromReader(reader *reader.PeekReader) (p Properties) {
 
 	bytes, err := reader.PeekBytes('\n')
 
-	// newline not found return a default generation
+	// newline not found, the header has no properties
 	if err != nil {
-		p.content["ID"] = &intProperty{num: rand.Intn(100000000)}
+		p.missingID()
 		return
 	}
 	//
@@ -102,31 +169,115 @@ func NewPropertiesFromReader(reader *reader.PeekReader) (p Properties) {
 
 	// properties not found return None
 	if !strings.Contains(string(bytes), ":PROPERTIES:") {
-		p.content["ID"] = &intProperty{num: rand.Intn(100000000)}
+		p.missingID()
 		return
 	}
 
 	// p.indent = strings.Index(string(bytes), ":PROPERTIES:")
 
 	// advance the reader
+	start := reader.Offset()
 	reader.Continue()
 
-	// TODO: should also parse dates between [...]
 	for bytes, err := reader.ReadBytes('\n'); err == nil && !strings.Contains(string(bytes), ":END:"); bytes, err = reader.ReadBytes('\n') {
 		mapping := strings.SplitN(string(bytes), ":", 3)
 		if len(mapping) >= 3 {
-			p.content[strings.TrimSpace(mapping[1])] = &stringProperty{str: strings.TrimSpace(mapping[2])}
+			p.Set(strings.TrimSpace(mapping[1]), ParsePropValue(mapping[2]))
 		}
 	}
 
-	// Assign a UID if missing
+	p.source.capture(reader.Text(start, reader.Offset()))
+
 	if _, hasUID := p.content["ID"]; !hasUID {
-		p.content["ID"] = &intProperty{num: rand.Intn(100000000)}
+		p.missingID()
 	}
 
 	return
 }
 
+// generateID assigns a new ID, a generated ID always goes at the top of the drawer.
+// Unlike the ID of a header read without one it is always written.
+func (p *Properties) generateID() {
+	p.setGeneratedID(newID())
+	p.hidden = false
+}
+
+// missingID marks the ID as missing in the file. Once the file is parsed the header gets an ID derived
+// from its place in the file, which stays hidden so parsing and writing a file does not add IDs to it.
+func (p *Properties) missingID() {
+	p.setGeneratedID("")
+	p.hidden = true
+}
+
+func (p *Properties) setGeneratedID(id string) {
+	p.content["ID"] = ParsePropValue(id)
+	p.generated = true
+	p.keys = append([]string{"ID"}, slices.DeleteFunc(p.keys, func(k string) bool { return k == "ID" })...)
+}
+
+// Set sets a property, new keys are appended to the end of the drawer.
+func (p *Properties) Set(key string, value PropValue) {
+	if _, ok := p.content[key]; !ok {
+		p.keys = append(p.keys, key)
+	}
+
+	if key == "ID" {
+		p.hidden = false
+	}
+
+	p.content[key] = value
+}
+
+// Remove removes a property and reports whether it was set.
+func (p *Properties) Remove(key string) bool {
+	if _, ok := p.content[key]; !ok {
+		return false
+	}
+
+	delete(p.content, key)
+	p.keys = slices.DeleteFunc(p.keys, func(k string) bool { return k == key })
+
+	return true
+}
+
+// Keys returns the property names in drawer order.
+func (p *Properties) Keys() []string {
+	return slices.Clone(p.keys)
+}
+
+// JSON returns the properties as a JSON object in drawer order, numbers are written as numbers
+// and all other values as strings.
+func (p *Properties) JSON() string {
+	builder := strings.Builder{}
+	builder.WriteRune('{')
+
+	for i, key := range p.keys {
+		if i > 0 {
+			builder.WriteRune(',')
+		}
+
+		var value any
+		switch v := p.content[key].(type) {
+		case *intProperty:
+			value = v.num
+		case *floatProperty:
+			value = v.num
+		default:
+			value = v.String()
+		}
+
+		// strings and finite numbers cannot fail to marshal
+		name, _ := json.Marshal(key)
+		bytes, _ := json.Marshal(value)
+		builder.Write(name)
+		builder.WriteRune(':')
+		builder.Write(bytes)
+	}
+
+	builder.WriteRune('}')
+	return builder.String()
+}
+
 func (p *Properties) IndentLevel() int {
 	return p.parent.ChildIndentLevel()
 }
@@ -142,16 +293,47 @@ func (p *Properties) ChildIndentLevel() int {
 // :KEY2: value2
 // :END:
 func (p *Properties) Render(sb *strings.Builder) {
-	if p == nil || len(p.content) == 0 {
+	if p == nil {
+		return
+	}
+
+	// a hidden ID is written together with the first change to its header
+	if p.hidden {
+		if header, ok := p.parent.(*Header); ok && header.changed() {
+			p.source.write(sb, func(sb *strings.Builder) { p.renderKeys(sb, true) })
+			return
+		}
+	}
+
+	p.source.write(sb, p.render)
+}
+
+// render writes the drawer, leaving out a hidden ID.
+func (p *Properties) render(sb *strings.Builder) {
+	p.renderKeys(sb, !p.hidden)
+}
+
+func (p *Properties) renderKeys(sb *strings.Builder, withID bool) {
+	keys := p.keys
+	if !withID {
+		keys = slices.DeleteFunc(slices.Clone(keys), func(k string) bool { return k == "ID" })
+	}
+
+	if len(keys) == 0 {
 		return
 	}
 
 	sb.WriteString(strings.Repeat(" ", p.IndentLevel()))
 	sb.WriteString(":PROPERTIES:\n")
 
-	for k, v := range p.content {
+	for _, k := range keys {
 		sb.WriteString(strings.Repeat(" ", p.IndentLevel()))
-		fmt.Fprintf(sb, ":%s: %s\n", k, v.String())
+
+		if value := p.content[k].String(); value != "" {
+			fmt.Fprintf(sb, ":%s: %s\n", k, value)
+		} else {
+			fmt.Fprintf(sb, ":%s:\n", k)
+		}
 	}
 
 	sb.WriteString(strings.Repeat(" ", p.IndentLevel()))
@@ -159,15 +341,12 @@ func (p *Properties) Render(sb *strings.Builder) {
 }
 
 func (p *Properties) RenderMarkdown(builder *strings.Builder) {
-	index := 0
-
 	builder.WriteString("<!-- ")
-	for k, v := range p.content {
-		fmt.Fprintf(builder, "%s: %s", k, v.String())
+	for index, k := range p.keys {
+		fmt.Fprintf(builder, "%s: %s", k, p.content[k].String())
 
-		if index < len(p.content)-1 {
+		if index < len(p.keys)-1 {
 			builder.WriteString("; ")
-			index += 1
 		}
 	}
 
@@ -1,8 +1,6 @@
 package orgmcp
 
 import (
-	"fmt"
-	"os"
 	"regexp"
 	"strings"
 	"time"
@@ -53,31 +51,27 @@ var ScheduleKeywords = []string{"DEADLINE", "SCHEDULED", "CLOSED"}
 var OrderedSchedules = []ScheduleStatus{Scheduled, Deadline, Closed}
 
 type Schedule struct {
-	Values map[ScheduleStatus]struct {
-		T        time.Time
-		withTime bool
-	}
+	Values map[ScheduleStatus]Timestamp
 
 	parent *Header
+	source Source
 }
 
 func NewSchedule(parent *Header) Schedule {
 	return Schedule{
-		Values: make(map[ScheduleStatus]struct {
-			T        time.Time
-			withTime bool
-		}),
+		Values: make(map[ScheduleStatus]Timestamp),
 		parent: parent,
 	}
 }
 
+var scheduleRegexes = map[ScheduleStatus]*regexp.Regexp{
+	Scheduled: regexp.MustCompile(`SCHEDULED:\s*(<[^>]*>(?:--<[^>]*>)?)`),
+	Deadline:  regexp.MustCompile(`DEADLINE:\s*(<[^>]*>(?:--<[^>]*>)?)`),
+	Closed:    regexp.MustCompile(`CLOSED:\s*(\[[^\]]*\])`),
+}
+
 func NewScheduleFromReader(reader *reader.PeekReader) option.Option[Schedule] {
-	var err error
-	schedule := Schedule{}
-	schedule.Values = make(map[ScheduleStatus]struct {
-		T        time.Time
-		withTime bool
-	})
+	schedule := NewSchedule(nil)
 
 	bytes, err := reader.PeekBytes('\n')
 
@@ -87,51 +81,14 @@ func NewScheduleFromReader(reader *reader.PeekReader) option.Option[Schedule] {
 
 	content := string(bytes)
 
-	r := regexp.MustCompile(".*SCHEDULED: <(.*?)( .*?)?>.*")
-	matches := r.FindStringSubmatch(content)
-	// fmt.Fprintf(os.Stderr, "matches: %v", matches)
-
-	if len(matches) != 0 {
-		if t, err := time.Parse("2006-01-02", matches[1]); err == nil {
-			schedule.Values[Scheduled] = struct {
-				T        time.Time
-				withTime bool
-			}{T: t, withTime: false}
-		}
-	}
-
-	r = regexp.MustCompile(".*DEADLINE: <(.*?)( .*?)?>.*")
-	matches = r.FindStringSubmatch(content)
-	// fmt.Fprintf(os.Stderr, "matches: %v", matches)
-
-	if len(matches) != 0 {
-		if t, err := time.Parse("2006-01-02", matches[1]); err == nil {
-			schedule.Values[Deadline] = struct {
-				T        time.Time
-				withTime bool
-			}{T: t, withTime: false}
-		}
-	}
-
-	r = regexp.MustCompile(`.*CLOSED: \[(.*?)( .*?)?( .*?)?\].*`)
-	matches = r.FindStringSubmatch(content)
-
-	if len(matches) != 0 {
-		if t, err := time.Parse("2006-01-02", matches[1]); err == nil {
-			schedule.Values[Closed] = struct {
-				T        time.Time
-				withTime bool
-			}{T: t, withTime: false}
+	for _, status := range OrderedSchedules {
+		matches := scheduleRegexes[status].FindStringSubmatch(content)
+		if matches == nil {
+			continue
 		}
-	}
 
-	if len(matches) > 3 {
-		if t, err := time.Parse("2006-01-02 15:04", matches[1]+matches[3]); err == nil {
-			fmt.Fprintf(os.Stderr, "Parsed closed time with time: %s\n", t)
-			schedule.Values[Closed] = struct {
-				T        time.Time
-				withTime bool
-			}{T: t, withTime: true}
+		if ts, err := ParseTimestamp(matches[1]); err == nil {
+			schedule.Values[status] = ts
 		}
 	}
 
@@ -139,11 +96,18 @@ func NewScheduleFromReader(reader *reader.PeekReader) option.Option[Schedule] {
 		return option.None[Schedule]()
 	}
 
+	start := reader.Offset()
 	reader.Continue()
+	schedule.source.capture(reader.Text(start, reader.Offset()))
+
 	return option.Some(schedule)
 }
 
 func (s *Schedule) Render(builder *strings.Builder) {
+	s.source.write(builder, s.render)
+}
+
+func (s *Schedule) render(builder *strings.Builder) {
 	// Indent according to parent's child indent level
 	// subtract 1 to account for the space before the schedule keywords bound to a minimum of 0
 	builder.WriteString(strings.Repeat(" ", max(s.parent.ChildIndentLevel()-1, 0)))
@@ -158,39 +122,37 @@ func (s *Schedule) Render(builder *strings.Builder) {
 		builder.WriteString(status.String())
 		builder.WriteString(": ")
 
-		if status == Closed {
-			builder.WriteRune('[')
-		} else {
-			builder.WriteRune('<')
-		}
-
-		builder.WriteString(t.T.Format("2006-01-02"))
-		builder.WriteRune(' ')
-		builder.WriteString(t.T.Weekday().String()[:3])
-
-		if t.withTime {
-			builder.WriteRune(' ')
-			builder.WriteString(t.T.Format("15:04"))
-		}
-
-		if status == Closed {
-			builder.WriteRune(']')
-		} else {
-			builder.WriteRune('>')
-		}
+		t.Render(builder)
 	}
 
 	builder.WriteString("\n")
 }
 
 func (s Schedule) AppendSchedule(status ScheduleStatus, t time.Time, withTime bool) Schedule {
-	s.Values[status] = struct {
-		T        time.Time
-		withTime bool
-	}{
-		T:        t,
-		withTime: withTime,
+	ts := NewTimestamp(t, withTime)
+	if status == Closed {
+		ts = ts.AsInactive()
 	}
 
+	s.Values[status] = ts
+
 	return s
 }
+
+// Repeat moves the repeating SCHEDULED and DEADLINE dates to their next occurrence.
+// It reports whether any of them repeats.
+func (s *Schedule) Repeat(now time.Time) (repeated bool) {
+	for _, status := range []ScheduleStatus{Scheduled, Deadline} {
+		ts, ok := s.Values[status]
+		if !ok {
+			continue
+		}
+
+		if ts.Repeat(now) {
+			s.Values[status] = ts
+			repeated = true
+		}
+	}
+
+	return
+}
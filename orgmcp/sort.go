@@ -0,0 +1,214 @@
+package orgmcp
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// SortKey is what headers are sorted by, like the keys of org-sort.
+type SortKey string
+
+const (
+	// SortAlpha sorts by headline, ignoring case.
+	SortAlpha SortKey = "alpha"
+	// SortStatus sorts by the order of the TODO keywords of the file, headers without a status go last.
+	SortStatus SortKey = "status"
+	// SortPriority sorts by priority, headers without a priority count as [#B] like org mode.
+	SortPriority SortKey = "priority"
+	// SortScheduled sorts by SCHEDULED date, headers without one go last.
+	SortScheduled SortKey = "scheduled"
+	// SortDeadline sorts by DEADLINE date, headers without one go last.
+	SortDeadline SortKey = "deadline"
+	// SortClosed sorts by CLOSED date, headers without one go last.
+	SortClosed SortKey = "closed"
+	// SortProperty sorts by the value of a property, numbers numerically, headers without it go last.
+	SortProperty SortKey = "property"
+)
+
+var SortKeys = []SortKey{SortAlpha, SortStatus, SortPriority, SortScheduled, SortDeadline, SortClosed, SortProperty}
+
+// ParseSortKey returns the sort key with the given name.
+func ParseSortKey(str string) (SortKey, error) {
+	key := SortKey(strings.ToLower(strings.TrimSpace(str)))
+	if !slices.Contains(SortKeys, key) {
+		return key, fmt.Errorf("invalid sort key %s, valid keys are: %v", str, SortKeys)
+	}
+
+	return key, nil
+}
+
+// SortOptions controls how Sort orders headers.
+type SortOptions struct {
+	Key SortKey
+	// Property is the property to sort by for SortProperty.
+	Property string
+	// Descending reverses the order, headers without a value still go last.
+	Descending bool
+	// Stable keeps the current order of headers with the same value, otherwise they are ordered by headline.
+	Stable bool
+	// Recursive sorts the sub headers of every sorted header as well.
+	Recursive bool
+}
+
+// Sort orders the sub headers of a header, or the top level headers for the root, like org-sort-entries.
+// The body of the header stays where it is, above the sub headers, and every header keeps its UID.
+func (of *OrgFile) Sort(uid Uid, options SortOptions) error {
+	if _, err := ParseSortKey(string(options.Key)); err != nil {
+		return err
+	}
+
+	if options.Key == SortProperty && options.Property == "" {
+		return fmt.Errorf("sorting by property needs the name of a property")
+	}
+
+	parent, ok := of.GetUid(uid).Split()
+	if !ok {
+		return fmt.Errorf("Uid %s not found", uid)
+	}
+
+	switch parent.(type) {
+	case *OrgFile, *Header:
+	default:
+		return fmt.Errorf("%s is not a header, only the sub headers of a header can be sorted", uid)
+	}
+
+	return sortHeaders(parent, options)
+}
+
+func sortHeaders(parent Render, options SortOptions) error {
+	headers := []*Header{}
+	for _, child := range parent.Children() {
+		if header, ok := child.(*Header); ok {
+			headers = append(headers, header)
+		}
+	}
+
+	compare := func(a, b *Header) int {
+		c := compareHeaders(a, b, options)
+		if c == 0 && !options.Stable {
+			c = strings.Compare(strings.ToLower(a.Content), strings.ToLower(b.Content))
+		}
+
+		return c
+	}
+
+	sorted := slices.Clone(headers)
+	slices.SortStableFunc(sorted, compare)
+
+	// move the headers one by one into the positions of the sub headers, the body items in between stay in order
+	for i, header := range sorted {
+		positions := []int{}
+		for j, child := range parent.Children() {
+			if _, ok := child.(*Header); ok {
+				positions = append(positions, j)
+			}
+		}
+
+		if parent.Children()[positions[i]] == Render(header) {
+			continue
+		}
+
+		if err := parent.Move(NewMoveOperation(NewIndexOperation(header.Uid(), positions[i]))); err != nil {
+			return err
+		}
+	}
+
+	if options.Recursive {
+		for _, header := range sorted {
+			if err := sortHeaders(header, options); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// compareHeaders compares two headers by the sort key, a header without a value for the key always goes last.
+func compareHeaders(a, b *Header, options SortOptions) int {
+	order := 1
+	if options.Descending {
+		order = -1
+	}
+
+	switch options.Key {
+	case SortAlpha:
+		return order * strings.Compare(strings.ToLower(a.Content), strings.ToLower(b.Content))
+	case SortStatus:
+		rank := func(h *Header) option.Option[int] {
+			index := slices.Index(h.TodoKeywords().All(), h.status)
+			if index == -1 {
+				return option.None[int]()
+			}
+
+			return option.Some(index)
+		}
+
+		return compareMissingLast(rank(a), rank(b), order, cmp.Compare[int])
+	case SortPriority:
+		return order * cmp.Compare(priorityRank(a), priorityRank(b))
+	case SortScheduled, SortDeadline, SortClosed:
+		status := map[SortKey]ScheduleStatus{SortScheduled: Scheduled, SortDeadline: Deadline, SortClosed: Closed}[options.Key]
+		date := func(h *Header) option.Option[time.Time] {
+			if schedule, ok := h.schedule.Split(); ok {
+				if ts, ok := schedule.Values[status]; ok {
+					return option.Some(ts.T)
+				}
+			}
+
+			return option.None[time.Time]()
+		}
+
+		return compareMissingLast(date(a), date(b), order, func(x, y time.Time) int { return x.Compare(y) })
+	case SortProperty:
+		return compareMissingLast(a.GetProperty(options.Property), b.GetProperty(options.Property), order, comparePropertyValues)
+	}
+
+	return 0
+}
+
+func compareMissingLast[T any](a, b option.Option[T], order int, compare func(T, T) int) int {
+	aValue, aOk := a.Split()
+	bValue, bOk := b.Split()
+
+	switch {
+	case aOk && bOk:
+		return order * compare(aValue, bValue)
+	case aOk:
+		return -1
+	case bOk:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// comparePropertyValues compares numbers numerically and everything else as text, ignoring case.
+func comparePropertyValues(a, b string) int {
+	aNum, aErr := strconv.ParseFloat(a, 64)
+	bNum, bErr := strconv.ParseFloat(b, 64)
+
+	if aErr == nil && bErr == nil {
+		return cmp.Compare(aNum, bNum)
+	}
+
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// priorityRank orders priorities from high to low, letters before numbers. A header without a priority
+// has the default priority B like org mode.
+func priorityRank(h *Header) int {
+	priority := h.Priority.UnwrapOr("B")
+
+	if num, err := strconv.Atoi(string(priority)); err == nil {
+		return int('Z') + 1 + num
+	}
+
+	return int(strings.ToUpper(string(priority))[0])
+}
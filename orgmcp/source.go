@@ -0,0 +1,91 @@
+package orgmcp
+
+import (
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// Source keeps the original text of a parsed item. In lossless mode an item whose own lines
+// still render the way they did right after parsing writes its original text instead,
+// so only the items an operation changed differ on disk.
+type Source struct {
+	raw option.Option[string]
+	// rendered is the normalized rendering of the item right after parsing
+	rendered string
+	// trailing holds the blank lines that followed the item in the file
+	trailing string
+}
+
+// sourced is implemented by items that keep their original text.
+type sourced interface {
+	sourceRef() *Source
+	// seal records the normalized rendering of a freshly parsed item
+	seal()
+}
+
+func (s *Source) capture(raw string) {
+	if raw != "" {
+		s.raw = option.Some(raw)
+	}
+}
+
+func (s *Source) seal(render func(*strings.Builder)) {
+	builder := strings.Builder{}
+	render(&builder)
+	s.rendered = builder.String()
+}
+
+// write renders the own lines of an item, without its children.
+func (s *Source) write(builder *strings.Builder, render func(*strings.Builder)) {
+	own := strings.Builder{}
+	render(&own)
+
+	raw, ok := s.raw.Split()
+	if !ok || !CurrentConfig().Lossless {
+		builder.WriteString(own.String())
+		return
+	}
+
+	if own.String() == s.rendered {
+		builder.WriteString(raw)
+		return
+	}
+
+	// the item changed in place, keep its original indentation so the items around it still line up
+	before, after, original := leadingSpace(s.rendered), leadingSpace(own.String()), leadingSpace(raw)
+	if before != after || after == original {
+		builder.WriteString(own.String())
+		return
+	}
+
+	for line := range strings.Lines(own.String()) {
+		if rest, ok := strings.CutPrefix(line, after); ok {
+			line = original + rest
+		}
+
+		builder.WriteString(line)
+	}
+}
+
+// writeTrailing writes the blank lines that followed the item, they are only kept in lossless mode.
+func (s *Source) writeTrailing(builder *strings.Builder) {
+	if CurrentConfig().Lossless {
+		builder.WriteString(s.trailing)
+	}
+}
+
+func leadingSpace(str string) string {
+	return str[:len(str)-len(strings.TrimLeft(str, " \t"))]
+}
+
+// sealSources seals the item and all of its descendants.
+func sealSources(r Render) {
+	if s, ok := r.(sourced); ok {
+		s.seal()
+	}
+
+	for _, child := range r.Children() {
+		sealSources(child)
+	}
+}
@@ -1 +1,471 @@
-package orgmcp
+package orgmcp
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+	"github.com/p3rtang/org-mcp/utils/reader"
+)
+
+// TableRow is a single row of a table, either a row of cells or a horizontal line.
+type TableRow struct {
+	Cells []string
+	Hline bool
+}
+
+// Table is an org table, the columns are aligned when it is rendered.
+// Row indices used by the methods below only count rows with cells, horizontal lines are skipped.
+type Table struct {
+	rows     []TableRow
+	formulas []string
+	indent   int
+	index    int
+
+	parent option.Option[Render]
+	source Source
+	uid    uidCache
+}
+
+// Enforce that Table implements the Render interface at compile time
+var _ Render = (*Table)(nil)
+
+// NewTable creates a table from rows of cells, with header set a horizontal line is added after the first row.
+func NewTable(rows [][]string, header bool) Table {
+	table := Table{}
+
+	for i, cells := range rows {
+		table.rows = append(table.rows, TableRow{Cells: escapeCells(cells)})
+
+		if header && i == 0 {
+			table.rows = append(table.rows, TableRow{Hline: true})
+		}
+	}
+
+	return table
+}
+
+// NewTableFromCsv creates a table from CSV data, see NewTable.
+func NewTableFromCsv(data string, header bool) (table Table, err error) {
+	reader := csv.NewReader(strings.NewReader(data))
+	reader.FieldsPerRecord = -1
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return
+	}
+
+	if len(rows) == 0 {
+		err = errors.New("a table needs at least one row")
+		return
+	}
+
+	return NewTable(rows, header), nil
+}
+
+// IsTableLine reports whether the given line (with or without indentation) is a table row.
+func IsTableLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+func NewTableFromReader(r *reader.PeekReader) option.Option[*Table] {
+	table := Table{}
+
+	for {
+		bytes, err := r.PeekBytes('\n')
+		if len(bytes) == 0 {
+			break
+		}
+
+		line := strings.TrimSpace(string(bytes))
+
+		if !IsTableLine(line) {
+			// formulas belong to the table directly above them
+			if len(table.rows) > 0 && strings.HasPrefix(strings.ToUpper(line), "#+TBLFM:") {
+				r.ReadBytes('\n')
+				table.formulas = append(table.formulas, strings.TrimSpace(line[len("#+TBLFM:"):]))
+				continue
+			}
+
+			break
+		}
+
+		read, _ := r.ReadBytes('\n')
+		if len(table.rows) == 0 && len(table.formulas) == 0 {
+			table.indent = len(read) - len(strings.TrimLeft(string(read), " "))
+		}
+
+		table.rows = append(table.rows, parseTableRow(line))
+
+		if err != nil {
+			break
+		}
+	}
+
+	if len(table.rows) == 0 {
+		return option.None[*Table]()
+	}
+
+	return option.Some(&table)
+}
+
+func parseTableRow(line string) TableRow {
+	if strings.HasPrefix(line, "|-") {
+		return TableRow{Hline: true}
+	}
+
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	row := TableRow{}
+	for cell := range strings.SplitSeq(line, "|") {
+		row.Cells = append(row.Cells, strings.TrimSpace(cell))
+	}
+
+	return row
+}
+
+func escapeCells(cells []string) []string {
+	escaped := make([]string, len(cells))
+
+	for i, cell := range cells {
+		escaped[i] = escapeCell(cell)
+	}
+
+	return escaped
+}
+
+// escapeCell makes sure a value cannot break the table structure.
+func escapeCell(cell string) string {
+	cell = strings.ReplaceAll(cell, "\n", " ")
+	return strings.TrimSpace(strings.ReplaceAll(cell, "|", "\\vert"))
+}
+
+// Rows returns the cells of every row, horizontal lines are skipped.
+func (t *Table) Rows() (rows [][]string) {
+	for _, row := range t.rows {
+		if !row.Hline {
+			rows = append(rows, row.Cells)
+		}
+	}
+
+	return
+}
+
+// Formulas returns the `#+TBLFM:` lines of the table.
+func (t *Table) Formulas() []string {
+	return t.formulas
+}
+
+func (t *Table) rowIndex(row int) (int, error) {
+	count := 0
+
+	for i, r := range t.rows {
+		if r.Hline {
+			continue
+		}
+
+		if count == row {
+			return i, nil
+		}
+
+		count++
+	}
+
+	return 0, fmt.Errorf("row %d out of range, the table has %d rows", row, count)
+}
+
+func (t *Table) Cell(row int, col int) option.Option[string] {
+	idx, err := t.rowIndex(row)
+	if err != nil || col < 0 || col >= len(t.rows[idx].Cells) {
+		return option.None[string]()
+	}
+
+	return option.Some(t.rows[idx].Cells[col])
+}
+
+// SetCell sets the value of a cell, the row is extended with empty cells when needed.
+func (t *Table) SetCell(row int, col int, value string) error {
+	if col < 0 {
+		return fmt.Errorf("column %d out of range", col)
+	}
+
+	idx, err := t.rowIndex(row)
+	if err != nil {
+		return err
+	}
+
+	for len(t.rows[idx].Cells) <= col {
+		t.rows[idx].Cells = append(t.rows[idx].Cells, "")
+	}
+
+	t.rows[idx].Cells[col] = escapeCell(value)
+
+	return nil
+}
+
+func (t *Table) AppendRow(cells []string) {
+	t.rows = append(t.rows, TableRow{Cells: escapeCells(cells)})
+}
+
+func (t *Table) DeleteRow(row int) error {
+	idx, err := t.rowIndex(row)
+	if err != nil {
+		return err
+	}
+
+	t.rows = append(t.rows[:idx], t.rows[idx+1:]...)
+
+	return nil
+}
+
+// Csv returns the rows of the table as CSV, horizontal lines are skipped.
+func (t *Table) Csv() string {
+	builder := strings.Builder{}
+	writer := csv.NewWriter(&builder)
+
+	writer.WriteAll(t.Rows())
+
+	return builder.String()
+}
+
+func (t *Table) columnCount() (count int) {
+	for _, row := range t.rows {
+		count = max(count, len(row.Cells))
+	}
+
+	return
+}
+
+// columnWidths returns the width of every column and whether it is a numeric column.
+// Like org mode numeric columns are aligned to the right.
+func (t *Table) columnWidths() (widths []int, numeric []bool) {
+	widths = make([]int, t.columnCount())
+	numeric = make([]bool, len(widths))
+
+	for col := range widths {
+		numbers, filled := 0, 0
+
+		for _, row := range t.rows {
+			if col >= len(row.Cells) {
+				continue
+			}
+
+			widths[col] = max(widths[col], utf8.RuneCountInString(row.Cells[col]))
+
+			if row.Cells[col] == "" {
+				continue
+			}
+
+			filled++
+			if _, err := strconv.ParseFloat(row.Cells[col], 64); err == nil {
+				numbers++
+			}
+		}
+
+		widths[col] = max(widths[col], 1)
+		numeric[col] = filled > 0 && numbers*2 >= filled
+	}
+
+	return
+}
+
+func (t *Table) CheckProgress() option.Option[Progress] {
+	return option.None[Progress]()
+}
+
+func (t *Table) Render(builder *strings.Builder, depth int) {
+	t.source.write(builder, t.render)
+
+	if depth != 0 {
+		t.source.writeTrailing(builder)
+	}
+}
+
+func (t *Table) sourceRef() *Source {
+	return &t.source
+}
+
+func (t *Table) seal() {
+	t.source.seal(t.render)
+}
+
+func (t *Table) render(builder *strings.Builder) {
+	indent := strings.Repeat(" ", t.indent)
+	widths, numeric := t.columnWidths()
+
+	for _, row := range t.rows {
+		builder.WriteString(indent)
+
+		if row.Hline {
+			builder.WriteRune('|')
+			for i, width := range widths {
+				if i > 0 {
+					builder.WriteRune('+')
+				}
+				builder.WriteString(strings.Repeat("-", width+2))
+			}
+			builder.WriteString("|\n")
+			continue
+		}
+
+		for i, width := range widths {
+			cell := ""
+			if i < len(row.Cells) {
+				cell = row.Cells[i]
+			}
+
+			padding := strings.Repeat(" ", width-utf8.RuneCountInString(cell))
+
+			builder.WriteString("| ")
+			if numeric[i] {
+				builder.WriteString(padding)
+				builder.WriteString(cell)
+			} else {
+				builder.WriteString(cell)
+				builder.WriteString(padding)
+			}
+			builder.WriteRune(' ')
+		}
+
+		builder.WriteString("|\n")
+	}
+
+	for _, formula := range t.formulas {
+		builder.WriteString(indent)
+		builder.WriteString("#+TBLFM: ")
+		builder.WriteString(formula)
+		builder.WriteRune('\n')
+	}
+}
+
+func (t *Table) IndentLevel() int {
+	return option.Map(t.parent, func(r Render) int {
+		return r.ChildIndentLevel()
+	}).UnwrapOr(0)
+}
+
+func (t *Table) ChildIndentLevel() int {
+	return t.IndentLevel()
+}
+
+func (t *Table) Level() int {
+	return option.Map(t.parent, func(r Render) int {
+		return r.Level() + 1
+	}).UnwrapOr(0)
+}
+
+func (t *Table) Location(table map[Uid]int) (loc int) {
+	if val, ok := table[t.Uid()]; ok {
+		return val
+	}
+
+	if parent, ok := t.parent.Split(); ok {
+		loc += parent.Location(table)
+
+		for i, child := range parent.Children() {
+			if child == t {
+				loc += i + 1
+				break
+			}
+		}
+	}
+
+	return
+}
+
+func (t *Table) AddChildren(r ...Render) error {
+	return errors.New("Table cannot have children")
+}
+
+func (t *Table) SetParent(r Render) error {
+	t.parent = option.Some(r)
+	t.index = len(r.Children())
+	t.uid.reset()
+	t.indent = r.ChildIndentLevel()
+
+	return nil
+}
+
+func (t *Table) RemoveChildren(...Uid) error {
+	return errors.New("Table cannot have children")
+}
+
+func (t *Table) Children() []Render {
+	return []Render{}
+}
+
+func (t *Table) ChildrenRec(_ int) []Render {
+	return []Render{}
+}
+
+func (t *Table) Uid() Uid {
+	if t.parent.IsNone() {
+		return NewUid(-1)
+	}
+
+	return keyedUid(t.parent.Unwrap(), t)
+}
+
+// uidKey uses all cells of the table, like bullets changing the content changes the UID.
+func (t *Table) uidKey() (string, string) {
+	rows := []string{}
+
+	for _, row := range t.rows {
+		if !row.Hline {
+			rows = append(rows, strings.Join(row.Cells, "|"))
+		}
+	}
+
+	return "tbl", strings.Join(rows, "\n")
+}
+
+func (t *Table) keyedUidCache() *uidCache {
+	return &t.uid
+}
+
+func (t *Table) ParentUid() Uid {
+	if t.parent.IsNone() {
+		return NewUid(0)
+	}
+
+	return t.parent.Unwrap().Uid()
+}
+
+func (t *Table) Status() RenderStatus {
+	return ""
+}
+
+func (t *Table) TagList() (list TagList) {
+	if parent, ok := t.parent.Split(); ok {
+		list = parent.TagList()
+	}
+
+	return
+}
+
+// Preview returns the first row of the table.
+func (t *Table) Preview(length int) string {
+	preview := ""
+	if rows := t.Rows(); len(rows) > 0 {
+		preview = "| " + strings.Join(rows[0], " | ") + " |"
+	}
+
+	if length < 0 || length >= len(preview) {
+		return preview
+	}
+
+	return preview[:length]
+}
+
+func (t *Table) Path() string {
+	if parent, ok := t.parent.Split(); ok {
+		return parent.Path() + "/" + t.Uid().String()
+	}
+
+	return t.Uid().String()
+}
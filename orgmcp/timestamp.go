@@ -0,0 +1,430 @@
+package orgmcp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+var timestampDateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+var timestampTimeRegex = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+var timestampTimeRangeRegex = regexp.MustCompile(`^\d{1,2}:\d{2}-\d{1,2}:\d{2}$`)
+var timestampBracketRegex = regexp.MustCompile(`^([<\[])([^>\]]*)[>\]]$`)
+var timestampRangeRegex = regexp.MustCompile(`^([<\[])([^>\]]*)[>\]]--[<\[]([^>\]]*)[>\]]$`)
+var repeaterRegex = regexp.MustCompile(`^(\.\+|\+\+|\+)(\d+)([hdwmy])(?:/(\d+)([hdwmy]))?$`)
+var warningRegex = regexp.MustCompile(`^(--?)(\d+)([hdwmy])$`)
+var offsetRegex = regexp.MustCompile(`^([+-]?)(\d+)([hdwmy])$`)
+
+// Interval is an amount of time as written in a timestamp, e.g. `3d` or `1w`.
+type Interval struct {
+	Value int
+	Unit  rune
+}
+
+func (i Interval) String() string {
+	return fmt.Sprintf("%d%c", i.Value, i.Unit)
+}
+
+// AddTo adds the interval n times to t, months and years keep the day of the month like org mode.
+func (i Interval) AddTo(t time.Time, n int) time.Time {
+	switch i.Unit {
+	case 'h':
+		return t.Add(time.Duration(i.Value*n) * time.Hour)
+	case 'd':
+		return t.AddDate(0, 0, i.Value*n)
+	case 'w':
+		return t.AddDate(0, 0, 7*i.Value*n)
+	case 'm':
+		return t.AddDate(0, i.Value*n, 0)
+	case 'y':
+		return t.AddDate(i.Value*n, 0, 0)
+	default:
+		return t
+	}
+}
+
+func parseInterval(value string, unit string) Interval {
+	num, _ := strconv.Atoi(value)
+	return Interval{Value: num, Unit: rune(unit[0])}
+}
+
+type RepeaterKind string
+
+const (
+	// RepeatCumulate shifts the date by the interval once, `+1w`.
+	RepeatCumulate RepeaterKind = "+"
+	// RepeatCatchUp shifts the date by the interval until it is in the future, `++1w`.
+	RepeatCatchUp RepeaterKind = "++"
+	// RepeatRestart shifts the date by the interval starting from today, `.+1w`.
+	RepeatRestart RepeaterKind = ".+"
+)
+
+// Repeater is the repeat cookie of a timestamp, habits can have an upper bound like `.+2d/3d`.
+type Repeater struct {
+	Kind     RepeaterKind
+	Interval Interval
+	Habit    option.Option[Interval]
+}
+
+func (r Repeater) String() string {
+	str := string(r.Kind) + r.Interval.String()
+
+	r.Habit.Then(func(i Interval) {
+		str += "/" + i.String()
+	})
+
+	return str
+}
+
+// Next returns the date the repeating timestamp t moves to when the task is done at now.
+func (r Repeater) Next(t time.Time, now time.Time, withTime bool) time.Time {
+	if r.Interval.Value <= 0 {
+		return t
+	}
+
+	switch r.Kind {
+	case RepeatCatchUp:
+		next := r.Interval.AddTo(t, 1)
+		for !isAfter(next, now, withTime || r.Interval.Unit == 'h') {
+			next = r.Interval.AddTo(next, 1)
+		}
+
+		return next
+	case RepeatRestart:
+		start := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, t.Location())
+		if r.Interval.Unit == 'h' {
+			start = now.Truncate(time.Minute)
+		}
+
+		return r.Interval.AddTo(start, 1)
+	default:
+		return r.Interval.AddTo(t, 1)
+	}
+}
+
+// isAfter compares the dates only, unless the time of day matters.
+func isAfter(t time.Time, now time.Time, withTime bool) bool {
+	if withTime {
+		return t.After(now)
+	}
+
+	ty, tm, td := t.Date()
+	ny, nm, nd := now.Date()
+
+	return time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC).After(time.Date(ny, nm, nd, 0, 0, 0, 0, time.UTC))
+}
+
+// Warning is the warning period of a deadline or the delay of a scheduled date, e.g. `-3d`.
+// With FirstOnly (`--3d`) it only applies to the first occurrence of a repeating timestamp.
+type Warning struct {
+	Interval  Interval
+	FirstOnly bool
+}
+
+func (w Warning) String() string {
+	if w.FirstOnly {
+		return "--" + w.Interval.String()
+	}
+
+	return "-" + w.Interval.String()
+}
+
+// Timestamp is the date of a SCHEDULED, DEADLINE or CLOSED entry including its cookies.
+// A timestamp can span a time range on one day, `<2026-10-20 Tue 14:00-15:30>`,
+// or a range of dates, `<2026-10-20 Tue>--<2026-10-22 Thu>`.
+type Timestamp struct {
+	T        time.Time
+	withTime bool
+
+	End         option.Option[time.Time]
+	endWithTime bool
+	dateRange   bool
+	inactive    bool
+
+	Repeater option.Option[Repeater]
+	Warning  option.Option[Warning]
+}
+
+func NewTimestamp(t time.Time, withTime bool) Timestamp {
+	return Timestamp{
+		T:        t,
+		withTime: withTime,
+		End:      option.None[time.Time](),
+		Repeater: option.None[Repeater](),
+		Warning:  option.None[Warning](),
+	}
+}
+
+// ParseTimestamp parses a timestamp with or without its brackets, e.g. `<2026-10-20 Tue 10:00 +1w -3d>`,
+// `[2026-10-20 Tue]` or `<2026-10-20 Tue>--<2026-10-22 Thu>`. Without brackets the timestamp is active.
+func ParseTimestamp(str string) (ts Timestamp, err error) {
+	str = strings.TrimSpace(str)
+
+	if matches := timestampRangeRegex.FindStringSubmatch(str); matches != nil {
+		if ts, err = parseTimestampContent(matches[2]); err != nil {
+			return
+		}
+
+		var end Timestamp
+		if end, err = parseTimestampContent(matches[3]); err != nil {
+			return
+		}
+
+		ts.End = option.Some(end.T)
+		ts.endWithTime = end.withTime
+		ts.dateRange = true
+		ts.inactive = matches[1] == "["
+
+		return
+	}
+
+	if matches := timestampBracketRegex.FindStringSubmatch(str); matches != nil {
+		ts, err = parseTimestampContent(matches[2])
+		ts.inactive = matches[1] == "["
+
+		return
+	}
+
+	return parseTimestampContent(str)
+}
+
+// parseTimestampContent parses the content between the brackets of a timestamp.
+func parseTimestampContent(str string) (ts Timestamp, err error) {
+	fields := strings.Fields(str)
+	if len(fields) == 0 || !timestampDateRegex.MatchString(fields[0]) {
+		err = fmt.Errorf("invalid timestamp %s", str)
+		return
+	}
+
+	ts = NewTimestamp(time.Time{}, false)
+	layout, value := "2006-01-02", fields[0]
+	endTime := ""
+
+	for _, field := range fields[1:] {
+		switch {
+		case timestampTimeRegex.MatchString(field):
+			layout, value = layout+" 15:04", value+" "+field
+			ts.withTime = true
+		case timestampTimeRangeRegex.MatchString(field):
+			times := strings.SplitN(field, "-", 2)
+			layout, value = layout+" 15:04", value+" "+times[0]
+			ts.withTime = true
+			endTime = times[1]
+		case repeaterRegex.MatchString(field):
+			matches := repeaterRegex.FindStringSubmatch(field)
+			repeater := Repeater{
+				Kind:     RepeaterKind(matches[1]),
+				Interval: parseInterval(matches[2], matches[3]),
+				Habit:    option.None[Interval](),
+			}
+
+			if matches[4] != "" {
+				repeater.Habit = option.Some(parseInterval(matches[4], matches[5]))
+			}
+
+			ts.Repeater = option.Some(repeater)
+		case warningRegex.MatchString(field):
+			matches := warningRegex.FindStringSubmatch(field)
+			ts.Warning = option.Some(Warning{
+				Interval:  parseInterval(matches[2], matches[3]),
+				FirstOnly: matches[1] == "--",
+			})
+		}
+		// anything else is the day name, which is derived from the date when rendering
+	}
+
+	if ts.T, err = time.ParseInLocation(layout, value, time.Local); err != nil {
+		return
+	}
+
+	if endTime != "" {
+		var end time.Time
+		if end, err = time.ParseInLocation("2006-01-02 15:04", fields[0]+" "+endTime, time.Local); err != nil {
+			return
+		}
+
+		ts.End = option.Some(end)
+		ts.endWithTime = true
+	}
+
+	return
+}
+
+func (ts Timestamp) WithTime() bool {
+	return ts.withTime
+}
+
+func (ts Timestamp) Active() bool {
+	return !ts.inactive
+}
+
+// AsInactive returns the timestamp with square brackets, inactive timestamps do not show up in the agenda.
+func (ts Timestamp) AsInactive() Timestamp {
+	ts.inactive = true
+	return ts
+}
+
+// IsDateRange reports whether the timestamp is a `<..>--<..>` range, rather than a single day.
+func (ts Timestamp) IsDateRange() bool {
+	return ts.dateRange && ts.End.IsSome()
+}
+
+// SetTimeRange sets the times of day, an end time before the start time is rejected.
+func (ts *Timestamp) SetTimeRange(start time.Time, end option.Option[time.Time]) error {
+	if end.AndThen(func(e time.Time) bool { return e.Before(start) }) {
+		return errors.New("the end of a time range cannot be before its start")
+	}
+
+	ts.T = start
+	ts.withTime = true
+	ts.End = end
+	ts.endWithTime = end.IsSome()
+	ts.dateRange = false
+
+	return nil
+}
+
+// SetDateRange turns the timestamp into a `<..>--<..>` range ending at end.
+func (ts *Timestamp) SetDateRange(end time.Time, withTime bool) error {
+	if end.Before(ts.T) {
+		return errors.New("the end of a date range cannot be before its start")
+	}
+
+	ts.End = option.Some(end)
+	ts.endWithTime = withTime
+	ts.dateRange = true
+
+	return nil
+}
+
+func (ts Timestamp) brackets() (rune, rune) {
+	if ts.inactive {
+		return '[', ']'
+	}
+
+	return '<', '>'
+}
+
+func writeDate(builder *strings.Builder, t time.Time, withTime bool) {
+	builder.WriteString(t.Format("2006-01-02"))
+	builder.WriteRune(' ')
+	builder.WriteString(t.Weekday().String()[:3])
+
+	if withTime {
+		builder.WriteRune(' ')
+		builder.WriteString(t.Format("15:04"))
+	}
+}
+
+// Render writes the timestamp including its brackets.
+func (ts Timestamp) Render(builder *strings.Builder) {
+	open, close := ts.brackets()
+
+	builder.WriteRune(open)
+	writeDate(builder, ts.T, ts.withTime)
+
+	if end, ok := ts.End.Split(); ok && !ts.dateRange {
+		builder.WriteRune('-')
+		builder.WriteString(end.Format("15:04"))
+	}
+
+	ts.Repeater.Then(func(r Repeater) {
+		builder.WriteRune(' ')
+		builder.WriteString(r.String())
+	})
+
+	ts.Warning.Then(func(w Warning) {
+		builder.WriteRune(' ')
+		builder.WriteString(w.String())
+	})
+
+	builder.WriteRune(close)
+
+	if end, ok := ts.End.Split(); ok && ts.dateRange {
+		builder.WriteString("--")
+		builder.WriteRune(open)
+		writeDate(builder, end, ts.endWithTime)
+		builder.WriteRune(close)
+	}
+}
+
+func (ts Timestamp) String() string {
+	builder := strings.Builder{}
+	ts.Render(&builder)
+
+	return builder.String()
+}
+
+// Format returns the timestamp without day names and cookies as used in the CSV columns,
+// e.g. `2026-10-20`, `2026-10-20 14:00-15:30` or `2026-10-20--2026-10-22`.
+func (ts Timestamp) Format() string {
+	layout := func(withTime bool) string {
+		if withTime {
+			return "2006-01-02 15:04"
+		}
+
+		return "2006-01-02"
+	}
+
+	str := ts.T.Format(layout(ts.withTime))
+
+	ts.End.Then(func(end time.Time) {
+		if ts.dateRange {
+			str += "--" + end.Format(layout(ts.endWithTime))
+		} else {
+			str += "-" + end.Format("15:04")
+		}
+	})
+
+	return str
+}
+
+// EndOrStart returns the end of a range, or the start for a single point in time.
+func (ts Timestamp) EndOrStart() time.Time {
+	return ts.End.UnwrapOr(ts.T)
+}
+
+// Repeat moves a repeating timestamp to its next occurrence, it reports whether the timestamp repeats at all.
+// The end of a range moves along with the start.
+func (ts *Timestamp) Repeat(now time.Time) bool {
+	repeater, ok := ts.Repeater.Split()
+	if !ok {
+		return false
+	}
+
+	next := repeater.Next(ts.T, now, ts.withTime)
+	ts.End = option.Map(ts.End, func(end time.Time) time.Time {
+		return end.Add(next.Sub(ts.T))
+	})
+	ts.T = next
+
+	return true
+}
+
+// Shift moves the timestamp by an interval, the end of a range moves along with the start.
+func (ts *Timestamp) Shift(i Interval) {
+	ts.End = option.Map(ts.End, func(end time.Time) time.Time {
+		return i.AddTo(end, 1)
+	})
+	ts.T = i.AddTo(ts.T, 1)
+}
+
+// ParseOffset parses a date offset like `+1w`, `-3d` or `2m`, as used by org-clone-subtree-with-time-shift.
+func ParseOffset(str string) (Interval, error) {
+	matches := offsetRegex.FindStringSubmatch(strings.TrimSpace(str))
+	if matches == nil {
+		return Interval{}, fmt.Errorf("invalid offset %s, expected a number with one of the units h, d, w, m or y like +1w or -3d", str)
+	}
+
+	interval := parseInterval(matches[2], matches[3])
+	if matches[1] == "-" {
+		interval.Value = -interval.Value
+	}
+
+	return interval, nil
+}
@@ -0,0 +1,186 @@
+package orgmcp
+
+import (
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/utils/slice"
+)
+
+// TodoKeywordKeys are the in-buffer settings that define a TODO sequence, e.g. `#+TODO: TODO WAIT | DONE`.
+var TodoKeywordKeys = []string{"TODO", "SEQ_TODO", "TYP_TODO"}
+
+// TodoKeywords holds the TODO states known in a file, split into active and done states.
+// The order of the states is the order in which they cycle.
+type TodoKeywords struct {
+	Active []HeaderStatus
+	Done   []HeaderStatus
+}
+
+var DefaultTodoKeywords = TodoKeywords{
+	Active: []HeaderStatus{Todo, Next, Prog},
+	Done:   []HeaderStatus{Revw, Done, Delg},
+}
+
+// ParseTodoKeywords parses the value of a `#+TODO:` line.
+// Active and done states are separated by a `|`, when it is missing the last state is the done state.
+// Fast access keys and logging settings like `WAIT(w@/!)` are ignored.
+func ParseTodoKeywords(str string) (keywords TodoKeywords, err error) {
+	seenSeparator := false
+	states := []HeaderStatus{}
+
+	for field := range strings.FieldsSeq(str) {
+		if field == "|" {
+			keywords.Active = states
+			states = []HeaderStatus{}
+			seenSeparator = true
+			continue
+		}
+
+		if idx := strings.Index(field, "("); idx > 0 {
+			field = field[:idx]
+		}
+
+		states = append(states, HeaderStatus(field))
+	}
+
+	if seenSeparator {
+		keywords.Done = states
+	} else if len(states) > 0 {
+		keywords.Active = states[:len(states)-1]
+		keywords.Done = states[len(states)-1:]
+	}
+
+	if len(keywords.Active)+len(keywords.Done) == 0 {
+		err = errors.New("a TODO sequence needs at least one state")
+	}
+
+	return
+}
+
+// Merge appends the states of another sequence, like multiple `#+TODO:` lines in one file.
+func (kw TodoKeywords) Merge(other TodoKeywords) TodoKeywords {
+	return TodoKeywords{
+		Active: append(slices.Clone(kw.Active), other.Active...),
+		Done:   append(slices.Clone(kw.Done), other.Done...),
+	}
+}
+
+// All returns every state, active states first.
+func (kw TodoKeywords) All() []HeaderStatus {
+	return append(slices.Clone(kw.Active), kw.Done...)
+}
+
+func (kw TodoKeywords) Contains(s HeaderStatus) bool {
+	return slices.Contains(kw.All(), s)
+}
+
+func (kw TodoKeywords) IsDone(s HeaderStatus) bool {
+	return slices.Contains(kw.Done, s)
+}
+
+// Lookup matches a string case-insensitively against the known states.
+// An empty string and NONE resolve to the None status.
+func (kw TodoKeywords) Lookup(str string) (HeaderStatus, bool) {
+	if str == "" || strings.EqualFold(str, string(None)) {
+		return None, true
+	}
+
+	for _, state := range kw.All() {
+		if strings.EqualFold(string(state), str) {
+			return state, true
+		}
+	}
+
+	return None, false
+}
+
+// Parse returns the matching state or None when the string is not a TODO keyword.
+func (kw TodoKeywords) Parse(str string) HeaderStatus {
+	status, _ := kw.Lookup(str)
+	return status
+}
+
+// Next returns the state after s, cycling from None through all states back to None.
+func (kw TodoKeywords) Next(s HeaderStatus) HeaderStatus {
+	all := kw.All()
+	if len(all) == 0 {
+		return None
+	}
+
+	if s == None {
+		return all[0]
+	}
+
+	idx := slices.Index(all, s)
+	if idx < 0 || idx == len(all)-1 {
+		return None
+	}
+
+	return all[idx+1]
+}
+
+// DefaultDone is the state used when a header gets completed automatically.
+func (kw TodoKeywords) DefaultDone() HeaderStatus {
+	if slices.Contains(kw.Done, Done) || len(kw.Done) == 0 {
+		return Done
+	}
+
+	return kw.Done[0]
+}
+
+// DefaultActive is the state a repeating task returns to after it is done, like org mode the first active state.
+func (kw TodoKeywords) DefaultActive() HeaderStatus {
+	if len(kw.Active) == 0 {
+		return Todo
+	}
+
+	return kw.Active[0]
+}
+
+// String renders the sequence the way it is written in a `#+TODO:` line.
+func (kw TodoKeywords) String() string {
+	toString := func(s HeaderStatus) string { return string(s) }
+
+	return strings.Join(slice.Map(kw.Active, toString), " ") + " | " + strings.Join(slice.Map(kw.Done, toString), " ")
+}
+
+// TodoKeywords returns the TODO states of the file, defined by `#+TODO:`, `#+SEQ_TODO:` and `#+TYP_TODO:` lines.
+// Without any of these lines the configured default is used.
+func (of *OrgFile) TodoKeywords() TodoKeywords {
+	keywords := TodoKeywords{}
+	found := false
+
+	for _, child := range of.children {
+		keyword, ok := child.(*Keyword)
+		if !ok || !slices.Contains(TodoKeywordKeys, keyword.Key()) {
+			continue
+		}
+
+		if parsed, err := ParseTodoKeywords(keyword.Value()); err == nil {
+			keywords = keywords.Merge(parsed)
+			found = true
+		}
+	}
+
+	if !found {
+		return CurrentConfig().TodoKeywords
+	}
+
+	return keywords
+}
+
+// TodoKeywords returns the TODO states of the file this header belongs to.
+func (h *Header) TodoKeywords() TodoKeywords {
+	if parent, ok := h.Parent.Split(); ok {
+		switch p := parent.(type) {
+		case *OrgFile:
+			return p.TodoKeywords()
+		case *Header:
+			return p.TodoKeywords()
+		}
+	}
+
+	return CurrentConfig().TodoKeywords
+}
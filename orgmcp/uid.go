@@ -2,6 +2,9 @@ package orgmcp
 
 import (
 	"fmt"
+	"hash/fnv"
+
+	"github.com/p3rtang/org-mcp/utils/option"
 )
 
 type UidValue interface {
@@ -23,3 +26,96 @@ func (u Uid) String() string {
 func (u *Uid) MarshalText() ([]byte, error) {
 	return []byte(u.uid), nil
 }
+
+// keyed is implemented by the items below headers, their UID is derived from a key
+// instead of their position so it does not change when siblings are added or removed.
+type keyed interface {
+	Render
+	// uidKey returns the marker of the item type and the key its UID is derived from
+	uidKey() (marker string, key string)
+	// keyedUidCache returns the UID the item got the last time its siblings were numbered
+	keyedUidCache() *uidCache
+}
+
+// uidCache holds the UID of a keyed item together with what it was derived from.
+// It is used as long as the parent UID, the key and the number of siblings are unchanged.
+type uidCache struct {
+	parentUid string
+	key       string
+	siblings  int
+	uid       option.Option[Uid]
+}
+
+func (c *uidCache) get(parentUid string, key string, siblings int) option.Option[Uid] {
+	if c.parentUid != parentUid || c.key != key || c.siblings != siblings {
+		return option.None[Uid]()
+	}
+
+	return c.uid
+}
+
+// reset drops the cached UID, e.g. when the item is moved to another parent.
+func (c *uidCache) reset() {
+	*c = uidCache{}
+}
+
+// keyedUid returns the parent UID followed by the marker and a hash of the key, e.g. 1234.b3f9a0c.
+// Siblings that end up with the same UID, because they have the same key or their keys share a hash,
+// are numbered in order, 1234.b3f9a0c-2 is the second one.
+func keyedUid(parent Render, item keyed) Uid {
+	parentUid := parent.Uid().String()
+	_, key := item.uidKey()
+	siblings := len(parent.Children())
+
+	if uid, ok := item.keyedUidCache().get(parentUid, key, siblings).Split(); ok {
+		return uid
+	}
+
+	numberKeyedUids(parent, parentUid)
+
+	if uid, ok := item.keyedUidCache().get(parentUid, key, siblings).Split(); ok {
+		return uid
+	}
+
+	// the item is not (yet) one of the children of its parent
+	return NewUid(hashedUid(parentUid, item))
+}
+
+// numberKeyedUids computes the UIDs of all keyed children of a parent in one pass and caches them,
+// so looking up the UIDs of every child does not hash all earlier siblings again.
+func numberKeyedUids(parent Render, parentUid string) {
+	children := parent.Children()
+	counts := make(map[string]int, len(children))
+
+	for _, child := range children {
+		item, ok := child.(keyed)
+		if !ok {
+			continue
+		}
+
+		_, key := item.uidKey()
+		uid := hashedUid(parentUid, item)
+		counts[uid] += 1
+
+		if counts[uid] > 1 {
+			uid += fmt.Sprintf("-%d", counts[uid])
+		}
+
+		*item.keyedUidCache() = uidCache{
+			parentUid: parentUid,
+			key:       key,
+			siblings:  len(children),
+			uid:       option.Some(NewUid(uid)),
+		}
+	}
+}
+
+// hashedUid returns the UID of a keyed item before it is numbered among its siblings.
+func hashedUid(parentUid string, item keyed) string {
+	marker, key := item.uidKey()
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+
+	return fmt.Sprintf("%s.%s%06x", parentUid, marker, hash.Sum32()&0xffffff)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+)
+
+func parseNamedFile(t *testing.T, file string, name string) *OrgFile {
+	content, err := os.ReadFile("./files/" + file)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", file, err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	of.SetName(name)
+
+	return &of
+}
+
+func useNow(t *testing.T, now time.Time) {
+	config := DefaultConfig()
+	config.Now = func() time.Time { return now }
+	Configure(config)
+
+	t.Cleanup(func() { Configure(DefaultConfig()) })
+}
+
+// TestMoveToFile tests that a subtree moved to another file keeps its ID, schedule and properties
+func TestMoveToFile(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	inbox := parseNamedFile(t, "inbox.org", "/org/inbox.org")
+	projects := parseNamedFile(t, "projects.org", "/org/projects.org")
+
+	if err := inbox.MoveToFile(NewUid(10), projects, NewUid(20), -1); err != nil {
+		t.Fatalf("failed to move to another file: %v", err)
+	}
+
+	expected := "* Home\n  :PROPERTIES:\n  :ID: 20\n  :END:\n" +
+		"** TODO Call the plumber\n   SCHEDULED: <2026-10-20 Tue>\n   :PROPERTIES:\n   :ID: 10\n   :EFFORT: 0:30\n   :END:\n" +
+		"   - ask about the boiler\n" +
+		"* Reused\n  :PROPERTIES:\n  :ID: 13\n  :END:\n"
+	if got := renderFile(projects); got != expected {
+		t.Errorf("unexpected target file\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	if strings.Contains(renderFile(inbox), "plumber") || inbox.GetUid(NewUid(10)).IsSome() {
+		t.Errorf("expected the subtree to be removed from the source file")
+	}
+
+	if projects.GetUid(NewUid(10)).IsNone() {
+		t.Errorf("expected the moved header to be found by its ID in the target file")
+	}
+
+	before := renderFile(inbox)
+	if err := inbox.MoveToFile(NewUid(11), projects, NewUid(0), -1); err == nil {
+		t.Errorf("expected an error for a subtree with an ID that is used in the target file")
+	}
+
+	if renderFile(inbox) != before {
+		t.Errorf("expected the source file to be unchanged after a failed move")
+	}
+}
+
+// TestArchive tests archiving to another file and to an archive heading in the same file
+func TestArchive(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useNow(t, time.Date(2026, 10, 17, 10, 0, 0, 0, time.Local))
+
+	inbox := parseNamedFile(t, "inbox.org", "/org/inbox.org")
+
+	done := []Uid{}
+	for _, header := range inbox.DoneSubtrees() {
+		done = append(done, header.Uid())
+	}
+
+	if !slices.Equal(done, []Uid{NewUid(12), NewUid(13)}) {
+		t.Errorf("expected done subtrees 12 and 13, got %v", done)
+	}
+
+	file, heading := inbox.ArchiveTarget(inbox.ArchiveLocation(inbox.GetUid(NewUid(12)).Unwrap().(*Header)))
+	if file != filepath.Clean("/org/inbox.org_archive") || heading != "" {
+		t.Errorf("expected the default location to be the top level of /org/inbox.org_archive, got %q %q", file, heading)
+	}
+
+	if file, heading := inbox.ArchiveTarget("::* Archive"); file != "" || heading != "* Archive" {
+		t.Errorf("expected ::* Archive to be a heading in the same file, got %q %q", file, heading)
+	}
+
+	archive, err := OrgFileFromReader(context.TODO(), strings.NewReader("")).Split()
+	if err != nil {
+		t.Fatalf("failed to create archive file: %v", err)
+	}
+
+	if err := inbox.Archive(NewUid(12), &archive, ""); err != nil {
+		t.Fatalf("failed to archive: %v", err)
+	}
+
+	expected := "* DONE Buy milk\n  CLOSED: [2026-10-16 Fri 09:00]\n  :PROPERTIES:\n  :ID: 12\n" +
+		"  :ARCHIVE_TIME: 2026-10-17 Sat 10:00\n  :ARCHIVE_FILE: /org/inbox.org\n  :ARCHIVE_OLPATH: Errands\n" +
+		"  :ARCHIVE_CATEGORY: inbox\n  :ARCHIVE_TODO: DONE\n  :END:\n"
+	if got := renderFile(&archive); got != expected {
+		t.Errorf("unexpected archive file\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+
+	if err := inbox.Archive(NewUid(13), inbox, "* Archive"); err != nil {
+		t.Fatalf("failed to archive to a heading: %v", err)
+	}
+
+	got := renderFile(inbox)
+	if !strings.Contains(got, "** DONE Post office\n   :PROPERTIES:\n   :ID: 13\n   :ARCHIVE_TIME: 2026-10-17 Sat 10:00\n") ||
+		!strings.Contains(got, "*** DONE Stamps\n") {
+		t.Errorf("expected the subtree under a new Archive heading, got:\n%s", got)
+	}
+
+	if done := inbox.DoneSubtrees(); len(done) != 0 {
+		t.Errorf("expected archived subtrees not to be archived again, got %s", done[0].Uid())
+	}
+
+	parent := inbox.GetUid(NewUid(13)).Unwrap().ParentUid()
+	if header, ok := inbox.GetUid(parent).Unwrap().(*Header); !ok || header.Content != "Archive" || header.Level() != 1 {
+		t.Errorf("expected the archived header to be under a top level Archive heading")
+	}
+
+	if err := inbox.Archive(NewUid(11), inbox, "** Missing"); err == nil {
+		t.Errorf("expected an error for a missing archive heading below the top level")
+	}
+}
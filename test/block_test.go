@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// TestBlockFileRender tests that blocks are rendered back unchanged
+func TestBlockFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content, err := os.ReadFile("./files/blocks.org")
+	if err != nil {
+		t.Fatalf("failed to read blocks.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != string(content) {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", string(content), builder.String())
+	}
+}
+
+// TestBlockParsing tests that the content of a block is kept verbatim and not parsed into other items
+func TestBlockParsing(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/blocks.org")
+	if err != nil {
+		t.Fatalf("failed to open blocks.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	header := of.GetUid(NewUid(1)).Unwrap()
+	if len(header.Children()) != 3 {
+		t.Fatalf("expected 3 children under header 1, got %d", len(header.Children()))
+	}
+
+	block, ok := option.Cast[Render, *Block](of.GetUid(NewUid("1.blkae0bde"))).Split()
+	if !ok {
+		t.Fatalf("expected a block with UID 1.blkae0bde")
+	}
+
+	if block.Kind() != "SRC" {
+		t.Errorf("expected kind SRC, got %s", block.Kind())
+	}
+
+	if block.Language() != option.Some("go") {
+		t.Errorf("expected language go, got %v", block.Language())
+	}
+
+	if !slices.Equal(block.Switches(), []string{"-n"}) {
+		t.Errorf("expected switches [-n], got %v", block.Switches())
+	}
+
+	expected := "func main() {\n    // - not a bullet\n    fmt.Println(\"hello\")\n\n* not a header\n}"
+	if block.Content() != expected {
+		t.Errorf("expected content %q, got %q", expected, block.Content())
+	}
+
+	if _, ok := of.GetUid(NewUid("1.b83cd34")).Split(); !ok {
+		t.Errorf("expected the bullet after the block to be parsed")
+	}
+
+	quote, ok := option.Cast[Render, *Block](of.GetUid(NewUid("2.blk267805"))).Split()
+	if !ok || quote.Kind() != "QUOTE" || quote.Language().IsSome() {
+		t.Errorf("expected a quote block with UID 2.blk267805")
+	}
+
+	note, ok := option.Cast[Render, *Block](of.GetUid(NewUid("2.blkf28058"))).Split()
+	if !ok || note.Kind() != "NOTE" || note.Content() != "A custom block" {
+		t.Errorf("expected a custom NOTE block with UID 2.blkf28058")
+	}
+}
+
+// TestBlockUnterminated tests that a block without an end line is parsed as plain text
+func TestBlockUnterminated(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/blocks.org")
+	if err != nil {
+		t.Fatalf("failed to open blocks.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	if _, ok := option.Cast[Render, *PlainText](of.GetUid(NewUid("3.tabd899"))).Split(); !ok {
+		t.Errorf("expected the unterminated begin line to be plain text")
+	}
+
+	if _, ok := of.GetUid(NewUid(4)).Split(); !ok {
+		t.Errorf("expected the header after the unterminated block to be parsed")
+	}
+}
+
+// TestBlockSetContent tests that lines which would break the block are escaped
+func TestBlockSetContent(t *testing.T) {
+	block := NewBlock("src", "org", "* A header\n#+TITLE: inside\nplain")
+
+	builder := strings.Builder{}
+	block.Render(&builder, -1)
+
+	expected := "#+BEGIN_SRC org\n,* A header\n,#+TITLE: inside\nplain\n#+END_SRC\n"
+	if builder.String() != expected {
+		t.Errorf("expected %q, got %q", expected, builder.String())
+	}
+
+	if block.Content() != "* A header\n#+TITLE: inside\nplain" {
+		t.Errorf("expected the escapes to be removed from the content, got %q", block.Content())
+	}
+}
+
+// TestBlockCommaRoundTrip tests that content which already starts with a comma escape is kept as is
+func TestBlockCommaRoundTrip(t *testing.T) {
+	content := ",* not a header\n  ,#+TITLE: not a setting\n,,*x\n, plain"
+	block := NewBlock("src", "org", content)
+
+	for range 2 {
+		if block.Content() != content {
+			t.Errorf("expected %q, got %q", content, block.Content())
+		}
+
+		block.SetContent(block.Content())
+	}
+
+	builder := strings.Builder{}
+	block.Render(&builder, -1)
+
+	expected := "#+BEGIN_SRC org\n,,* not a header\n  ,,#+TITLE: not a setting\n,,,*x\n, plain\n#+END_SRC\n"
+	if builder.String() != expected {
+		t.Errorf("expected %q, got %q", expected, builder.String())
+	}
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/p3rtang/org-mcp/mcp"
 	. "github.com/p3rtang/org-mcp/orgmcp"
@@ -134,10 +136,10 @@ func TestBulletIndexing(t *testing.T) {
 		uid     string
 		content string
 	}{
-		{uid: "31786692.b0", content: "   - [x] Bullet 1"},
-		{uid: "31786692.b1", content: "   - [ ] Bullet 2"},
-		{uid: "31786693.b0", content: "   - [ ] Main bullet"},
-		{uid: "31786693.b0.b0", content: "     * Sub bullet 1"},
+		{uid: "31786692.bed08be", content: "   - [x] Bullet 1"},
+		{uid: "31786692.bed072b", content: "   - [ ] Bullet 2"},
+		{uid: "31786693.b547b08", content: "   - [ ] Main bullet"},
+		{uid: "31786693.b547b08.b7cb4dc", content: "     * Sub bullet 1"},
 	}
 
 	// os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
@@ -323,7 +325,7 @@ func TestBulletComplete(t *testing.T) {
 	testMap := []Test{
 		{
 			name:     "TestCompleteDefaultBullet",
-			uid:      NewUid("31786692.b1"),
+			uid:      NewUid("31786692.bed072b"),
 			expected: "   - [x] Bullet 2",
 			operation: func(b *Bullet) {
 				b.CompleteCheckbox()
@@ -331,7 +333,7 @@ func TestBulletComplete(t *testing.T) {
 		},
 		{
 			name:     "TestCompleteNestedMainBullet",
-			uid:      NewUid("31786694.b1"),
+			uid:      NewUid("31786694.ba74afe"),
 			expected: "   - [x] Main bullet 2",
 			operation: func(b *Bullet) {
 				b.CompleteCheckbox()
@@ -364,3 +366,75 @@ func TestBulletComplete(t *testing.T) {
 		})
 	}
 }
+
+// TestBulletHashCollision tests that siblings whose contents share a hash still get their own UID
+func TestBulletHashCollision(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	// the UIDs of both bullets hash to the same value
+	content := "* Tasks\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  - task 589\n  - task 31426\n"
+
+	orgFile, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	header := orgFile.GetUid(NewUid(1)).Unwrap()
+	first, second := header.Children()[0], header.Children()[1]
+
+	if first.Uid() == second.Uid() || second.Uid().String() != first.Uid().String()+"-2" {
+		t.Fatalf("expected the second bullet to be numbered, got %s and %s", first.Uid(), second.Uid())
+	}
+
+	for _, bullet := range []Render{first, second} {
+		if found, ok := orgFile.GetUid(bullet.Uid()).Split(); !ok || found != bullet {
+			t.Errorf("expected %s to be found by its UID", bullet.Uid())
+		}
+	}
+}
+
+// TestBulletManySiblings tests that a header with thousands of bullets is indexed quickly,
+// looking up the UID of one sibling must not hash all the others again.
+func TestBulletManySiblings(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	builder := strings.Builder{}
+	builder.WriteString("* Tasks\n  :PROPERTIES:\n  :ID: 1\n  :END:\n")
+
+	for i := range 3000 {
+		builder.WriteString(fmt.Sprintf("  - task %d\n", i%1000))
+	}
+
+	start := time.Now()
+
+	orgFile, err := OrgFileFromReader(context.TODO(), strings.NewReader(builder.String())).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	orgFile.Reindex()
+	table := *orgFile.BuildLocationTable()
+
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("expected 3000 bullets to be indexed within 10s, took %s", elapsed)
+	}
+
+	children := orgFile.GetUid(NewUid(1)).Unwrap().Children()
+	if len(children) != 3000 {
+		t.Fatalf("expected 3000 bullets, got %d", len(children))
+	}
+
+	for i, bullet := range children {
+		if found, ok := orgFile.GetUid(bullet.Uid()).Split(); !ok || found != bullet {
+			t.Fatalf("expected %s to be found by its UID", bullet.Uid())
+		}
+
+		if table[bullet.Uid()] != i+2 {
+			t.Fatalf("expected bullet %d at location %d, got %d", i, i+2, table[bullet.Uid()])
+		}
+	}
+
+	if children[2000].Uid().String() != children[0].Uid().String()+"-3" {
+		t.Errorf("expected the third bullet with the same content to be numbered, got %s", children[2000].Uid())
+	}
+}
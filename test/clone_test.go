@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// TestClone tests that a clone gets new IDs and that checkboxes, statuses and dates are reset or shifted
+func TestClone(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useIDs(t, &sequenceIDs{ids: []string{"10", "11"}})
+
+	of := parseNamedFile(t, "templates.org", "/org/templates.org")
+
+	shift, err := ParseOffset("+1w")
+	if err != nil {
+		t.Fatalf("failed to parse offset: %v", err)
+	}
+
+	clone, err := of.Clone(NewUid(4), CloneOptions{ResetCheckboxes: true, ResetStatus: true, Shift: option.Some(shift)})
+	if err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+
+	if clone.Uid() != NewUid(10) {
+		t.Errorf("expected the clone to get a new ID, got %s", clone.Uid())
+	}
+
+	expected := "* Onboarding\n  :PROPERTIES:\n  :ID: 4\n  :END:\n" +
+		"** DONE Laptop\n   SCHEDULED: <2026-10-01 Thu> CLOSED: [2026-10-01 Thu 10:00]\n   :PROPERTIES:\n   :ID: 5\n   :END:\n" +
+		"   - [x] order\n   - [ ] set up\n" +
+		"* Onboarding\n  :PROPERTIES:\n  :ID: 10\n  :END:\n" +
+		"** TODO Laptop\n   SCHEDULED: <2026-10-08 Thu>\n   :PROPERTIES:\n   :ID: 11\n   :END:\n" +
+		"   - [ ] order\n   - [ ] set up\n"
+	if got := renderFile(of); !strings.HasSuffix(got, expected) {
+		t.Errorf("expected the clone right after the original\nExpected suffix:\n%s\nGot:\n%s", expected, got)
+	}
+
+	if _, err := ParseOffset("1 week"); err == nil {
+		t.Errorf("expected an invalid offset to be rejected")
+	}
+}
+
+// TestInstantiateTemplate tests that a template is copied with its variables substituted and without the template tag
+func TestInstantiateTemplate(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	// the failed instance uses up the first three IDs
+	useIDs(t, &sequenceIDs{ids: []string{"90", "91", "92", "20", "21", "22"}})
+
+	templates := parseNamedFile(t, "templates.org", "/org/templates.org")
+
+	if len(templates.Templates()) != 1 || templates.Template("Release {{version}}").IsNone() {
+		t.Fatalf("expected one template named 'Release {{version}}'")
+	}
+
+	if _, err := templates.Instantiate("1", templates, NewUid(0), -1, CloneOptions{Variables: map[string]string{"version": "1.2"}}); err == nil ||
+		!strings.Contains(err.Error(), "owner") {
+		t.Errorf("expected an error for the missing owner variable, got %v", err)
+	}
+
+	target, err := OrgFileFromReader(context.TODO(), strings.NewReader("* Releases\n  :PROPERTIES:\n  :ID: 30\n  :END:\n")).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	variables := map[string]string{"version": "1.2", "owner": "sam"}
+	if _, err := templates.Instantiate("1", &target, NewUid(30), -1, CloneOptions{Variables: variables}); err != nil {
+		t.Fatalf("failed to instantiate: %v", err)
+	}
+
+	expected := "* Releases\n  :PROPERTIES:\n  :ID: 30\n  :END:\n" +
+		"** Release 1.2\n   :PROPERTIES:\n   :ID: 20\n   :OWNER: sam\n   :END:\n" +
+		"*** TODO Tag v1.2\n    DEADLINE: <2026-10-20 Tue>\n    :PROPERTIES:\n    :ID: 21\n    :END:\n    - [ ] push the tag\n" +
+		"*** WAIT Announce\n    :PROPERTIES:\n    :ID: 22\n    :END:\n    See [[id:21][the tag]].\n"
+	if got := renderFile(&target); got != expected {
+		t.Errorf("unexpected instance\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
+
+// TestCloneNested tests that headers below the top level and nested templates can be copied
+func TestCloneNested(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useIDs(t, &sequenceIDs{ids: []string{"10", "20", "21"}})
+
+	of := parseNamedFile(t, "templates.org", "/org/templates.org")
+
+	clone, err := of.Clone(NewUid(5), CloneOptions{ResetCheckboxes: true})
+	if err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+
+	if clone.Uid() != NewUid(10) || clone.Level() != 2 {
+		t.Errorf("expected a level 2 clone with a new ID, got %s at level %d", clone.Uid(), clone.Level())
+	}
+
+	expected := "** DONE Laptop\n   SCHEDULED: <2026-10-01 Thu> CLOSED: [2026-10-01 Thu 10:00]\n   :PROPERTIES:\n   :ID: 5\n   :END:\n" +
+		"   - [x] order\n   - [ ] set up\n" +
+		"** DONE Laptop\n   SCHEDULED: <2026-10-01 Thu> CLOSED: [2026-10-01 Thu 10:00]\n   :PROPERTIES:\n   :ID: 10\n   :END:\n" +
+		"   - [ ] order\n   - [ ] set up\n"
+	if got := renderFile(of); !strings.HasSuffix(got, expected) {
+		t.Errorf("expected the clone right after the original\nExpected suffix:\n%s\nGot:\n%s", expected, got)
+	}
+
+	content := "* Templates\n  :PROPERTIES:\n  :ID: 1\n  :END:\n" +
+		"** Review {{topic}} :template:\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+		"*** TODO Read {{topic}}\n    :PROPERTIES:\n    :ID: 3\n    :END:\n"
+	templates, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	if _, err := templates.Instantiate("2", &templates, NewUid(0), -1, CloneOptions{Variables: map[string]string{"topic": "docs"}}); err != nil {
+		t.Fatalf("failed to instantiate: %v", err)
+	}
+
+	expected = content + "* Review docs\n  :PROPERTIES:\n  :ID: 20\n  :END:\n" +
+		"** TODO Read docs\n   :PROPERTIES:\n   :ID: 21\n   :END:\n"
+	if got := renderFile(&templates); got != expected {
+		t.Errorf("unexpected instance\nExpected:\n%s\nGot:\n%s", expected, got)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+)
+
+// TestParseDate tests relative dates against a fixed now, 2026-10-17 is a saturday
+func TestParseDate(t *testing.T) {
+	now := time.Date(2026, 10, 17, 9, 30, 0, 0, time.Local)
+
+	cases := []struct {
+		expr     string
+		expected string
+	}{
+		{"+3d", "<2026-10-20 Tue>"},
+		{"-1w", "<2026-10-10 Sat>"},
+		{"+2h", "<2026-10-17 Sat 11:30>"},
+		{"today", "<2026-10-17 Sat>"},
+		{"tomorrow 14:00", "<2026-10-18 Sun 14:00>"},
+		{"Tomorrow 14:00-15:30", "<2026-10-18 Sun 14:00-15:30>"},
+		{"saturday", "<2026-10-17 Sat>"},
+		{"next saturday", "<2026-10-24 Sat>"},
+		{"next monday", "<2026-10-19 Mon>"},
+		{"fri", "<2026-10-23 Fri>"},
+		{"next week", "<2026-10-24 Sat>"},
+		{"end of week", "<2026-10-18 Sun>"},
+		{"end of month", "<2026-10-31 Sat>"},
+		{"end of year", "<2026-12-31 Thu>"},
+		{"2026-11-02", "<2026-11-02 Mon>"},
+		{"2026-11-02 08:15", "<2026-11-02 Mon 08:15>"},
+		{"<2026-11-02 Mon +1w>", "<2026-11-02 Mon +1w>"},
+	}
+
+	for _, c := range cases {
+		ts, err := ParseDate(c.expr, now)
+		if err != nil {
+			t.Errorf("ParseDate(%q) returned an error: %v", c.expr, err)
+			continue
+		}
+
+		if ts.String() != c.expected {
+			t.Errorf("ParseDate(%q) = %s, expected %s", c.expr, ts.String(), c.expected)
+		}
+	}
+
+	for _, expr := range []string{"", "someday", "next", "+3x", "tomorrow 25:00"} {
+		if _, err := ParseDate(expr, now); err == nil {
+			t.Errorf("ParseDate(%q) should have returned an error", expr)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+func parseDrawersFile(t *testing.T) (OrgFile, string) {
+	content, err := os.ReadFile("./files/drawers.org")
+	if err != nil {
+		t.Fatalf("failed to read drawers.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	return of, string(content)
+}
+
+// TestDrawerFileRender tests that drawers render back unchanged
+func TestDrawerFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, content := parseDrawersFile(t)
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != content {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, builder.String())
+	}
+}
+
+// TestDrawerParsing tests that the content of a drawer is kept verbatim and not parsed into other items
+func TestDrawerParsing(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, _ := parseDrawersFile(t)
+
+	header := of.GetUid(NewUid(1)).Unwrap()
+	if len(header.Children()) != 4 {
+		t.Fatalf("expected 4 children under header 1, got %d", len(header.Children()))
+	}
+
+	drawer, ok := option.Cast[Render, *Drawer](of.GetUid(NewUid("1.drw127c20"))).Split()
+	if !ok {
+		t.Fatalf("expected a drawer with UID 1.drw127c20")
+	}
+
+	if drawer.Name() != "NOTES" {
+		t.Errorf("expected name NOTES, got %s", drawer.Name())
+	}
+
+	expected := "- not a bullet\n  indented line\n\nafter an empty line"
+	if drawer.Content() != expected {
+		t.Errorf("expected content %q, got %q", expected, drawer.Content())
+	}
+
+	if _, ok := option.Cast[Render, *Drawer](of.GetUid(NewUid("1.drwfa3383"))).Split(); !ok {
+		t.Errorf("expected the results drawer with a lower case end line at UID 1.drwfa3383")
+	}
+
+	unterminated := of.GetUid(NewUid(2)).Unwrap()
+	for _, child := range unterminated.Children() {
+		if _, ok := child.(*Drawer); ok {
+			t.Errorf("expected an unterminated drawer to be parsed as text")
+		}
+	}
+}
+
+// TestDrawerSetContent tests that content which would break the drawer is rejected
+func TestDrawerSetContent(t *testing.T) {
+	drawer, err := NewDrawer("notes", "first\nsecond")
+	if err != nil {
+		t.Fatalf("failed to create drawer: %v", err)
+	}
+
+	builder := strings.Builder{}
+	drawer.Render(&builder, -1)
+
+	if builder.String() != ":notes:\nfirst\nsecond\n:END:\n" {
+		t.Errorf("unexpected render %q", builder.String())
+	}
+
+	if err := drawer.SetContent("text\n:END:\nmore"); err == nil {
+		t.Errorf("expected an :END: line in the content to be rejected")
+	}
+
+	if err := drawer.SetContent("* headline"); err == nil {
+		t.Errorf("expected a headline in the content to be rejected")
+	}
+
+	if drawer.Content() != "first\nsecond" {
+		t.Errorf("expected the content to be unchanged after a rejected update, got %q", drawer.Content())
+	}
+
+	if _, err := NewDrawer("two words", ""); err == nil {
+		t.Errorf("expected an invalid drawer name to be rejected")
+	}
+}
+
+// TestDrawerSiblingUid tests that removing a drawer does not give a sibling with the same name its UID
+func TestDrawerSiblingUid(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "* Header\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  :NOTES:\n  first\n  :END:\n  :NOTES:\n  second\n  :END:\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	header := of.GetUid(NewUid(1)).Unwrap()
+	first, second := header.Children()[0], header.Children()[1]
+	firstUid, secondUid := first.Uid(), second.Uid()
+
+	if err := header.RemoveChildren(firstUid); err != nil {
+		t.Fatalf("failed to remove drawer: %v", err)
+	}
+
+	of.Reindex()
+
+	if second.Uid() != secondUid {
+		t.Errorf("expected the second drawer to keep UID %s, got %s", secondUid, second.Uid())
+	}
+
+	if of.GetUid(firstUid).IsSome() {
+		t.Errorf("expected UID %s to no longer be found after removing its drawer", firstUid)
+	}
+}
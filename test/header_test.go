@@ -167,3 +167,60 @@ func TestHeaderProgressCheckProgress(t *testing.T) {
 		})
 	}
 }
+
+func TestHeaderPriority(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	tests := []struct {
+		name     string
+		input    string
+		priority string
+		content  string
+	}{
+		{
+			name:     "Priority after status",
+			input:    "* TODO [#A] Example [0/1] :tag1:",
+			priority: "A",
+			content:  "Example",
+		},
+		{
+			name:     "Priority without status",
+			input:    "** [#C] Example",
+			priority: "C",
+			content:  "Example",
+		},
+		{
+			name:     "Numeric priority",
+			input:    "* NEXT [#10] Example",
+			priority: "10",
+			content:  "Example",
+		},
+		{
+			name:     "No priority",
+			input:    "* TODO Example",
+			priority: "",
+			content:  "Example",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := NewHeaderFromString(tt.input, nil).Unwrap()
+
+			if got := header.Priority.UnwrapOr(""); string(got) != tt.priority {
+				t.Errorf("expected priority '%s', got '%s'", tt.priority, got)
+			}
+
+			if header.Content != tt.content {
+				t.Errorf("expected content '%s', got '%s'", tt.content, header.Content)
+			}
+
+			builder := strings.Builder{}
+			header.Render(&builder, 0)
+
+			if strings.TrimSpace(builder.String()) != tt.input {
+				t.Errorf("expected rendered output '%s', got '%s'", tt.input, builder.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// sequenceIDs hands out a fixed list of IDs, to force collisions
+type sequenceIDs struct {
+	ids []string
+}
+
+func (s *sequenceIDs) NewID() string {
+	id := s.ids[0]
+	s.ids = s.ids[1:]
+
+	return id
+}
+
+func useIDs(t *testing.T, ids IDGenerator) {
+	config := DefaultConfig()
+	config.IDs = ids
+	Configure(config)
+
+	t.Cleanup(func() { Configure(DefaultConfig()) })
+}
+
+// TestIDGenerator tests the numeric and uuid formats and that a seed makes them deterministic
+func TestIDGenerator(t *testing.T) {
+	uuidRegex := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	numericRegex := regexp.MustCompile(`^\d{1,8}$`)
+
+	for format, regex := range map[IDFormat]*regexp.Regexp{IDUUID: uuidRegex, IDNumeric: numericRegex} {
+		first, err := NewIDGenerator(format, option.Some[uint64](42))
+		if err != nil {
+			t.Fatalf("NewIDGenerator(%s) failed: %v", format, err)
+		}
+
+		second, _ := NewIDGenerator(format, option.Some[uint64](42))
+
+		for range 10 {
+			a, b := first.NewID(), second.NewID()
+
+			if a != b {
+				t.Errorf("expected seeded %s generators to return the same IDs, got %s and %s", format, a, b)
+			}
+
+			if !regex.MatchString(a) {
+				t.Errorf("generated %s ID %s has the wrong format", format, a)
+			}
+		}
+	}
+
+	if _, err := NewIDGenerator("ulid", option.None[uint64]()); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
+
+// TestOrgIdInterop tests that IDs written by Emacs are used as UIDs and CUSTOM_ID and id: links resolve
+func TestOrgIdInterop(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content, err := os.ReadFile("./files/org_id.org")
+	if err != nil {
+		t.Fatalf("failed to read org_id.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	cases := map[string]string{
+		"7f3c2a9e-4b1d-4e8a-9c61-2d5f0b7e1a34":    "Written by Emacs",
+		"id:7f3c2a9e-4b1d-4e8a-9c61-2d5f0b7e1a34": "Written by Emacs",
+		"#intro": "Introduction",
+		"intro":  "Introduction",
+		"1":      "Numeric",
+	}
+
+	for uid, expected := range cases {
+		header, ok := option.Cast[Render, *Header](of.GetUid(NewUid(uid))).Split()
+		if !ok {
+			t.Errorf("GetUid(%s) did not find a header", uid)
+			continue
+		}
+
+		if header.Content != expected {
+			t.Errorf("GetUid(%s) = %s, expected %s", uid, header.Content, expected)
+		}
+	}
+
+	if of.GetUid(NewUid("#missing")).IsSome() {
+		t.Errorf("expected #missing not to resolve")
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if !strings.Contains(builder.String(), ":ID: 0b9d4c1e-8f2a-4a67-b3e5-9c1d7a2f6e80\n") {
+		t.Errorf("expected the org-id to be kept, got:\n%s", builder.String())
+	}
+}
+
+// TestGeneratedIdCollisions tests that generated IDs skip IDs that are already used in the file
+func TestGeneratedIdCollisions(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useIDs(t, &sequenceIDs{ids: []string{"1", "2", "3"}})
+
+	content := "* Without id\n* First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n* Second\n  :PROPERTIES:\n  :ID: 2\n  :END:\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	for uid, expected := range map[int]string{1: "First", 2: "Second"} {
+		if header := of.GetUid(NewUid(uid)).Unwrap().(*Header); header.Content != expected {
+			t.Errorf("expected %d to still be %s, got %s", uid, expected, header.Content)
+		}
+	}
+
+	// 1 and 2 are taken, so the next free ID is 3
+	header := of.NewHeader(Todo, "New")
+	if header.Uid() != NewUid(3) {
+		t.Errorf("expected the new header to get ID 3, got %s", header.Uid())
+	}
+}
+
+// TestMissingIdDerived tests that a header without an ID gets the same UID every time the file is parsed
+func TestMissingIdDerived(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "* Tasks\n** Without id\n* Other\n** Without id\n"
+
+	parse := func() OrgFile {
+		of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+		if err != nil {
+			t.Fatalf("failed to parse org file: %v", err)
+		}
+
+		return of
+	}
+
+	first, second := parse(), parse()
+	firstHeaders, secondHeaders := first.ChildrenRec(-1), second.ChildrenRec(-1)
+
+	for i := range firstHeaders {
+		if firstHeaders[i].Uid() != secondHeaders[i].Uid() {
+			t.Errorf("expected %s to keep its UID, got %s", firstHeaders[i].Uid(), secondHeaders[i].Uid())
+		}
+	}
+
+	if firstHeaders[1].Uid() == firstHeaders[3].Uid() {
+		t.Errorf("expected headers with the same title under different parents to get their own UID")
+	}
+
+	builder := strings.Builder{}
+	first.Render(&builder, -1)
+
+	if builder.String() != content {
+		t.Errorf("expected no IDs to be written, got:\n%s", builder.String())
+	}
+}
+
+// TestDuplicateIDs tests that a copied subtree is reported and that the first header keeps its ID
+func TestDuplicateIDs(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/duplicate_ids.org")
+	if err != nil {
+		t.Fatalf("failed to open duplicate_ids.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	duplicates := of.DuplicateIDs()
+	if len(duplicates) != 2 {
+		t.Fatalf("expected 2 duplicate IDs, got %d", len(duplicates))
+	}
+
+	if duplicates[0].Header.Content != "Project copy" || duplicates[0].Original.Content != "Project" {
+		t.Errorf("expected the copy to be the duplicate of Project, got %s of %s", duplicates[0].Header.Content, duplicates[0].Original.Content)
+	}
+
+	if header := of.GetUid(NewUid(100)).Unwrap().(*Header); header.Content != "Project" {
+		t.Errorf("expected UID 100 to target the first header, got %s", header.Content)
+	}
+
+	if warnings := of.Warnings(); len(warnings) != 2 || !strings.Contains(warnings[0], "repair_ids") {
+		t.Errorf("expected a warning per duplicate, got %v", warnings)
+	}
+}
+
+// TestRepairIDs tests that duplicates get new IDs and only the links inside the copied subtree follow them
+func TestRepairIDs(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useIDs(t, &sequenceIDs{ids: []string{"101", "300", "301"}})
+
+	file, err := os.Open("./files/duplicate_ids.org")
+	if err != nil {
+		t.Fatalf("failed to open duplicate_ids.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	repairs, links := of.RepairIDs()
+	if len(repairs) != 2 || links != 1 {
+		t.Fatalf("expected 2 repairs and 1 updated link, got %d and %d", len(repairs), links)
+	}
+
+	// 101 is already used, so the generator is asked again
+	if repairs[0].Old != NewUid(100) || repairs[0].New != NewUid(300) || repairs[1].New != NewUid(301) {
+		t.Errorf("unexpected repairs %v", repairs)
+	}
+
+	if len(of.DuplicateIDs()) != 0 {
+		t.Errorf("expected no duplicates after the repair")
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	expected := strings.Join([]string{
+		"* Project", "  :PROPERTIES:", "  :ID: 100", "  :END:",
+		"** Task", "   :PROPERTIES:", "   :ID: 101", "   :END:",
+		"   - Back to [[id:100][the project]]",
+		"* Project copy", "  :PROPERTIES:", "  :ID: 300", "  :END:",
+		"** Task", "   :PROPERTIES:", "   :ID: 301", "   :END:",
+		"   - Back to [[id:300][the project]]",
+		"* Elsewhere", "  :PROPERTIES:", "  :ID: 200", "  :END:",
+		"  See [[id:101][the task]].",
+	}, "\n") + "\n"
+
+	if builder.String() != expected {
+		t.Errorf("unexpected render:\n%s", builder.String())
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// TestKeywordFileRender tests that a file starting with a keyword block and preamble prose is rendered back unchanged
+func TestKeywordFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content, err := os.ReadFile("./files/keywords.org")
+	if err != nil {
+		t.Fatalf("failed to read keywords.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != string(content) {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", string(content), builder.String())
+	}
+}
+
+// TestKeywordParsing tests that keywords are parsed into Keyword items that can be looked up
+func TestKeywordParsing(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/keywords.org")
+	if err != nil {
+		t.Fatalf("failed to open keywords.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	if title := of.Keyword("title"); title != option.Some("Keyword test file") {
+		t.Errorf("expected title 'Keyword test file', got %v", title)
+	}
+
+	if options := of.Keyword("OPTIONS"); options != option.Some("") {
+		t.Errorf("expected empty OPTIONS keyword, got %v", options)
+	}
+
+	keyword, ok := of.GetUid(NewUid("0.k2641e9")).Split()
+	if !ok {
+		t.Fatalf("expected to find keyword with UID 0.k2641e9")
+	}
+
+	if _, ok := keyword.(*Keyword); !ok {
+		t.Errorf("expected *Keyword, got %T", keyword)
+	}
+
+	if keyword.Preview(-1) != "#+TITLE: Keyword test file" {
+		t.Errorf("unexpected preview '%s'", keyword.Preview(-1))
+	}
+
+	nested, ok := of.GetUid(NewUid("1.kba8a26")).Split()
+	if !ok {
+		t.Fatalf("expected to find indented keyword with UID 1.kba8a26")
+	}
+
+	if nested.(*Keyword).Key() != "NAME" {
+		t.Errorf("expected NAME keyword, got %s", nested.(*Keyword).Key())
+	}
+}
+
+// TestKeywordPreamble tests that lines before the first header become children of the file
+func TestKeywordPreamble(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/keywords.org")
+	if err != nil {
+		t.Fatalf("failed to open keywords.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	expected := []string{"*orgmcp.Keyword", "*orgmcp.Keyword", "*orgmcp.Keyword", "*orgmcp.Keyword", "*orgmcp.PlainText", "*orgmcp.Bullet", "*orgmcp.PlainText", "*orgmcp.Header"}
+	got := []string{}
+
+	for _, child := range of.Children() {
+		got = append(got, ColType.Value(child, ""))
+	}
+
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected children %v, got %v", expected, got)
+	}
+}
+
+// TestKeywordFileTags tests that #+FILETAGS are inherited by every header
+func TestKeywordFileTags(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/keywords.org")
+	if err != nil {
+		t.Fatalf("failed to open keywords.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+
+	header := of.GetUid(NewUid(1)).Unwrap()
+	if !slices.Contains(header.TagList(), "project") {
+		t.Errorf("expected header to inherit the project file tag, got %v", header.TagList())
+	}
+}
+
+// TestUnterminatedLastLine tests that the last line is parsed when the file does not end with a newline
+func TestUnterminatedLastLine(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of := OrgFileFromReader(context.TODO(), strings.NewReader("#+TITLE: no newline")).Unwrap()
+
+	if title := of.Keyword("TITLE"); title != option.Some("no newline") {
+		t.Errorf("expected title 'no newline', got %v", title)
+	}
+}
+
+// TestKeywordLowerCase tests that lower case keys are found case insensitively and written back as they were
+func TestKeywordLowerCase(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "#+title: Lower case\n#+Filetags: :work:\n"
+
+	of := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Unwrap()
+
+	if title := of.Keyword("TITLE"); title != option.Some("Lower case") {
+		t.Errorf("expected title 'Lower case', got %v", title)
+	}
+
+	if tags := of.Keywords("filetags"); !slices.Equal(tags, []string{":work:"}) {
+		t.Errorf("expected the file tags [:work:], got %v", tags)
+	}
+
+	if rendered := renderFile(&of); rendered != content {
+		t.Errorf("expected the keys to be kept\nExpected:\n%s\nGot:\n%s", content, rendered)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+func parseLogbookFile(t *testing.T) (OrgFile, string) {
+	content, err := os.ReadFile("./files/logbook.org")
+	if err != nil {
+		t.Fatalf("failed to read logbook.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	return of, string(content)
+}
+
+// TestLogbookFileRender tests that logbook drawers, including notes, render back unchanged
+func TestLogbookFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, content := parseLogbookFile(t)
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != content {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, builder.String())
+	}
+}
+
+// TestLogbookParsing tests that clock entries are parsed and summed over subheaders
+func TestLogbookParsing(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, _ := parseLogbookFile(t)
+
+	header := option.Cast[Render, *Header](of.GetUid(NewUid(1))).Unwrap()
+	if len(header.Children()) != 2 {
+		t.Fatalf("expected the logbook not to be parsed as children, got %d children", len(header.Children()))
+	}
+
+	clocks := header.Logbook().Unwrap().Clocks()
+	if len(clocks) != 2 {
+		t.Fatalf("expected 2 clock entries, got %d", len(clocks))
+	}
+
+	if clocks[1].Duration() != 83*time.Minute {
+		t.Errorf("expected the second entry to be 1:23, got %s", clocks[1].Duration())
+	}
+
+	if header.Clocked() != 83*time.Minute+30*time.Minute+615*time.Minute {
+		t.Errorf("expected the clocked time to include the subheader, got %s", header.Clocked())
+	}
+
+	if val := ColClocked.Value(header, ","); val != "12:08" {
+		t.Errorf("expected CLOCKED column 12:08, got %s", val)
+	}
+
+	running := of.RunningClock()
+	if running.IsNone() || running.Unwrap().Uid() != NewUid(3) {
+		t.Errorf("expected header 3 to have the running clock")
+	}
+}
+
+// TestLogbookClock tests clocking in and out and the Emacs format of the written entries
+func TestLogbookClock(t *testing.T) {
+	header := NewHeader(Todo, "Clocked task")
+	header.SetLevel(1)
+	start := time.Date(2026, 1, 5, 9, 0, 30, 0, time.Local)
+
+	if _, err := header.ClockOut(start); err == nil {
+		t.Errorf("expected clocking out without a running clock to fail")
+	}
+
+	if err := header.ClockIn(start); err != nil {
+		t.Fatalf("failed to clock in: %v", err)
+	}
+
+	if err := header.ClockIn(start); err == nil {
+		t.Errorf("expected clocking in twice to fail")
+	}
+
+	entry, err := header.ClockOut(start.Add(83 * time.Minute))
+	if err != nil {
+		t.Fatalf("failed to clock out: %v", err)
+	}
+
+	if entry.Duration() != 83*time.Minute {
+		t.Errorf("expected a duration of 1:23, got %s", entry.Duration())
+	}
+
+	builder := strings.Builder{}
+	header.Render(&builder, -1)
+
+	expected := "  :LOGBOOK:\n  CLOCK: [2026-01-05 Mon 09:00]--[2026-01-05 Mon 10:23] =>  1:23\n  :END:\n"
+	if !strings.HasSuffix(builder.String(), expected) {
+		t.Errorf("expected the logbook to be rendered as\n%s\ngot:\n%s", expected, builder.String())
+	}
+}
+
+// TestLogbookUnterminated tests that a logbook without an end does not swallow the headers after it
+func TestLogbookUnterminated(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "* A\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  :LOGBOOK:\n  CLOCK: [2026-01-05 Mon 09:00]--[2026-01-05 Mon 10:23] =>  1:23\n" +
+		"* B\n  :PROPERTIES:\n  :ID: 2\n  :END:\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	if header, ok := option.Cast[Render, *Header](of.GetUid(NewUid(2))).Split(); !ok || header.Level() != 1 {
+		t.Fatalf("expected header B to be parsed as a top level header")
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != content {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, builder.String())
+	}
+}
+
+// TestLogbookUnterminatedWithoutProperties tests that the lines of an unterminated logbook
+// directly below a headline are read again line by line
+func TestLogbookUnterminatedWithoutProperties(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	clock := "CLOCK: [2026-01-05 Mon 09:00]--[2026-01-05 Mon 10:00] =>  1:00"
+	content := "* H\n:LOGBOOK:\n" + clock + "\n* Other\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	if len(of.Children()) != 2 {
+		t.Fatalf("expected 2 top level headers, got %d", len(of.Children()))
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	lines := strings.Split(builder.String(), "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == ":LOGBOOK:" {
+			if i+1 >= len(lines) || strings.TrimSpace(lines[i+1]) != clock {
+				t.Errorf("expected the clock line after the logbook line, got:\n%s", builder.String())
+			}
+
+			return
+		}
+	}
+
+	t.Errorf("expected the logbook line on its own line, got:\n%s", builder.String())
+}
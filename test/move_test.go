@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+)
+
+func parseMoveFile(t *testing.T) OrgFile {
+	content, err := os.ReadFile("./files/move.org")
+	if err != nil {
+		t.Fatalf("failed to read move.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	return of
+}
+
+// TestMoveItems tests reordering, refiling, promoting and demoting, each case starts from move.org
+func TestMoveItems(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	tests := []struct {
+		name     string
+		move     func(of *OrgFile) error
+		expected string
+	}{
+		{
+			name: "ReorderBullet",
+			// the body counts text and bullets alike, index 1 is right after the text
+			move: func(of *OrgFile) error { return of.Reorder(NewUid("1.bdfda15"), 1) },
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Some notes about the plan.\n" +
+				"  - [ ] Second step\n    - detail\n  - [ ] First step\n" +
+				"** Research\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+				"*** Sources\n    :PROPERTIES:\n    :ID: 3\n    :END:\n    - a book\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"* Later\n  :PROPERTIES:\n  :ID: 5\n  :END:\n",
+		},
+		{
+			name: "ReorderHeaderByOffset",
+			move: func(of *OrgFile) error { return of.ReorderBy(NewUid(4), -1) },
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Some notes about the plan.\n" +
+				"  - [ ] First step\n  - [ ] Second step\n    - detail\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"** Research\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+				"*** Sources\n    :PROPERTIES:\n    :ID: 3\n    :END:\n    - a book\n" +
+				"* Later\n  :PROPERTIES:\n  :ID: 5\n  :END:\n",
+		},
+		{
+			name: "RefileSubtreeToRoot",
+			move: func(of *OrgFile) error { return of.MoveTo(NewUid(2), NewUid(0), -1) },
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Some notes about the plan.\n" +
+				"  - [ ] First step\n  - [ ] Second step\n    - detail\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"* Later\n  :PROPERTIES:\n  :ID: 5\n  :END:\n" +
+				"* Research\n  :PROPERTIES:\n  :ID: 2\n  :END:\n" +
+				"** Sources\n   :PROPERTIES:\n   :ID: 3\n   :END:\n   - a book\n",
+		},
+		{
+			name: "RefileBulletsAndText",
+			move: func(of *OrgFile) error {
+				if err := of.MoveTo(NewUid("1.bdfda15"), NewUid(5), -1); err != nil {
+					return err
+				}
+
+				return of.MoveTo(NewUid("1.t9ba9e1"), NewUid(5), 0)
+			},
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n" +
+				"  - [ ] First step\n" +
+				"** Research\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+				"*** Sources\n    :PROPERTIES:\n    :ID: 3\n    :END:\n    - a book\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"* Later\n  :PROPERTIES:\n  :ID: 5\n  :END:\n  Some notes about the plan.\n  - [ ] Second step\n    - detail\n",
+		},
+		{
+			name: "RefileBulletUnderBullet",
+			move: func(of *OrgFile) error { return of.MoveTo(NewUid("3.bf27549"), NewUid("1.bc74831"), -1) },
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Some notes about the plan.\n" +
+				"  - [ ] First step\n    - a book\n  - [ ] Second step\n    - detail\n" +
+				"** Research\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+				"*** Sources\n    :PROPERTIES:\n    :ID: 3\n    :END:\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"* Later\n  :PROPERTIES:\n  :ID: 5\n  :END:\n",
+		},
+		{
+			name: "PromoteHeader",
+			move: func(of *OrgFile) error { return of.Promote(NewUid(3)) },
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Some notes about the plan.\n" +
+				"  - [ ] First step\n  - [ ] Second step\n    - detail\n" +
+				"** Research\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+				"** Sources\n   :PROPERTIES:\n   :ID: 3\n   :END:\n   - a book\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"* Later\n  :PROPERTIES:\n  :ID: 5\n  :END:\n",
+		},
+		{
+			name: "DemoteHeader",
+			move: func(of *OrgFile) error { return of.Demote(NewUid(5)) },
+			expected: "* Plan\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Some notes about the plan.\n" +
+				"  - [ ] First step\n  - [ ] Second step\n    - detail\n" +
+				"** Research\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+				"*** Sources\n    :PROPERTIES:\n    :ID: 3\n    :END:\n    - a book\n" +
+				"** Writing\n   :PROPERTIES:\n   :ID: 4\n   :END:\n" +
+				"** Later\n   :PROPERTIES:\n   :ID: 5\n   :END:\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			of := parseMoveFile(t)
+
+			if err := tt.move(&of); err != nil {
+				t.Fatalf("move failed: %v", err)
+			}
+
+			if rendered := renderFile(&of); rendered != tt.expected {
+				t.Errorf("unexpected render:\n%s\nexpected:\n%s", rendered, tt.expected)
+			}
+		})
+	}
+}
+
+// TestMoveErrors tests that moves which would break the tree are rejected and leave the file unchanged
+func TestMoveErrors(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	tests := []struct {
+		name     string
+		move     func(of *OrgFile) error
+		expected string
+	}{
+		{"IntoOwnSubtree", func(of *OrgFile) error { return of.MoveTo(NewUid(2), NewUid(3), -1) }, "cannot move 2 into its own subtree"},
+		{"HeaderUnderBullet", func(of *OrgFile) error { return of.MoveTo(NewUid(4), NewUid("1.bc74831"), -1) }, "a header can only be moved under another header or the root of the file"},
+		{"IndexOutOfRange", func(of *OrgFile) error { return of.Reorder(NewUid(2), 2) }, "index 2 out of range, there are 1 siblings"},
+		{"PromoteTopLevel", func(of *OrgFile) error { return of.Promote(NewUid(1)) }, "1 is already a top level header"},
+		{"DemoteFirst", func(of *OrgFile) error { return of.Demote(NewUid(2)) }, "2 has no header before it to become a child of"},
+		{"SwapHeaderWithBullet", func(of *OrgFile) error { return of.Swap(NewUid(2), NewUid("1.bc74831")) }, "a header can only be swapped with another header"},
+	}
+
+	content, _ := os.ReadFile("./files/move.org")
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			of := parseMoveFile(t)
+
+			err := tt.move(&of)
+			if err == nil || err.Error() != tt.expected {
+				t.Fatalf("expected error '%s', got %v", tt.expected, err)
+			}
+
+			if rendered := renderFile(&of); rendered != string(content) {
+				t.Errorf("expected the file to be unchanged, got:\n%s", rendered)
+			}
+		})
+	}
+}
+
+// TestMoveWithoutId tests that a header read without an ID keeps its UID when it moves to another parent
+func TestMoveWithoutId(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "* Inbox\n** Task\n* Projects\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	task, projects := of.Children()[0].Children()[0].Uid(), of.Children()[1].Uid()
+
+	if err := of.MoveTo(task, projects, -1); err != nil {
+		t.Fatalf("failed to move: %v", err)
+	}
+
+	written, err := OrgFileFromReader(context.TODO(), strings.NewReader(renderFile(&of))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse the written file: %v", err)
+	}
+
+	header, ok := written.GetUid(task).Split()
+	if !ok || header.ParentUid() != projects {
+		t.Errorf("expected %s to be found under %s, got:\n%s", task, projects, renderFile(&written))
+	}
+}
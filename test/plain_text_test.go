@@ -356,10 +356,10 @@ func TestPlainTextFileUid(t *testing.T) {
 		t.Fatalf("failed to get header with UID 1")
 	}
 
-	text, ok := of.GetUid(NewUid("1.t0")).Split()
+	text, ok := of.GetUid(NewUid("1.tfa20d7")).Split()
 
 	if !ok {
-		t.Fatalf("failed to get plain text with UID 1.t0")
+		t.Fatalf("failed to get plain text with UID 1.tfa20d7")
 	}
 
 	builder := strings.Builder{}
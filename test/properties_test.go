@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// TestParsePropValue tests that timestamps and numbers are typed and that typed values render unchanged
+func TestParsePropValue(t *testing.T) {
+	cases := []struct {
+		value  string
+		isDate bool
+		isInt  bool
+	}{
+		{"[2026-10-17 Sat 09:30]", true, true},
+		{"<2026-10-20 Tue>", true, true},
+		{"42", false, true},
+		{"-3", false, true},
+		{"1.5", false, false},
+		{"-0.25", false, false},
+		{"1.50", false, false},
+		{"NaN", false, false},
+		{"007", false, true},
+		{"alice", false, false},
+		{"[[id:1234][a link]]", false, false},
+		{"[2026-10-17  Sat]", false, false},
+		{"", false, false},
+	}
+
+	for _, c := range cases {
+		value := ParsePropValue(c.value)
+
+		if value.String() != c.value {
+			t.Errorf("ParsePropValue(%q).String() = %q", c.value, value.String())
+		}
+
+		if value.Date().IsSome() != c.isDate {
+			t.Errorf("ParsePropValue(%q).Date() = %v, expected a date: %v", c.value, value.Date(), c.isDate)
+		}
+
+		if value.Int().IsSome() != c.isInt {
+			t.Errorf("ParsePropValue(%q).Int() = %v, expected an int: %v", c.value, value.Int(), c.isInt)
+		}
+	}
+}
+
+// TestHeaderProperties tests reading, setting and removing typed properties of a parsed header
+func TestHeaderProperties(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "* Task\n  :PROPERTIES:\n  :ID: 1\n  :CREATED: [2026-10-17 Sat 09:30]\n  :EFFORT: 3\n  :OWNER: alice\n  :ESTIMATE: 1.5\n  :END:\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	header, ok := option.Cast[Render, *Header](of.GetUid(NewUid(1))).Split()
+	if !ok {
+		t.Fatalf("header 1 not found")
+	}
+
+	created := header.PropValue("CREATED").Unwrap().Date()
+	if !created.IsSome() || !created.Unwrap().Equal(time.Date(2026, 10, 17, 9, 30, 0, 0, time.Local)) {
+		t.Errorf("expected CREATED to be parsed as a date, got %v", created)
+	}
+
+	if effort := header.PropValue("EFFORT").Unwrap().Int(); !effort.IsSome() || effort.Unwrap() != 3 {
+		t.Errorf("expected EFFORT to be parsed as 3, got %v", effort)
+	}
+
+	if json := header.PropertiesJSON(); json != `{"ID":1,"CREATED":"[2026-10-17 Sat 09:30]","EFFORT":3,"OWNER":"alice","ESTIMATE":1.5}` {
+		t.Errorf("unexpected properties JSON: %s", json)
+	}
+
+	header.SetProperty("EFFORT", "5")
+	if !header.RemoveProperty("OWNER") || header.RemoveProperty("OWNER") {
+		t.Errorf("expected OWNER to be removed exactly once")
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if !strings.Contains(builder.String(), "  :EFFORT: 5\n") || strings.Contains(builder.String(), "OWNER") {
+		t.Errorf("unexpected rendered properties:\n%s", builder.String())
+	}
+}
+
+// TestValidateProperty tests that property names and values that would break the drawer are rejected
+func TestValidateProperty(t *testing.T) {
+	if err := ValidateProperty("OWNER", "alice"); err != nil {
+		t.Errorf("expected OWNER to be valid, got %v", err)
+	}
+
+	for _, key := range []string{"", "TWO WORDS", "A:B", "END", "properties"} {
+		if err := ValidateProperty(key, "value"); err == nil {
+			t.Errorf("expected property name %q to be invalid", key)
+		}
+	}
+
+	if err := ValidateProperty("NOTE", "first\nsecond"); err == nil {
+		t.Errorf("expected a multi-line value to be invalid")
+	}
+}
+
+// TestPropertiesFileRender tests that property drawers keep their order through a round trip
+func TestPropertiesFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content, err := os.ReadFile("./files/properties.org")
+	if err != nil {
+		t.Fatalf("failed to read properties.org: %v", err)
+	}
+
+	// render a couple of times, map iteration order changes between runs
+	for range 10 {
+		of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+		if err != nil {
+			t.Fatalf("failed to parse org file: %v", err)
+		}
+
+		builder := strings.Builder{}
+		of.Render(&builder, -1)
+
+		if builder.String() != string(content) {
+			t.Fatalf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, builder.String())
+		}
+	}
+}
+
+// TestPropertiesOrder tests that new properties are appended and a generated ID goes first
+func TestPropertiesOrder(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "* Task\n  :PROPERTIES:\n  :OWNER: alice\n  :EFFORT: 1:00\n  :END:\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	header := of.Children()[0].(*Header)
+	header.SetProperty("ESTIMATE", "3")
+	header.SetProperty("OWNER", "bob")
+	header.RemoveProperty("EFFORT")
+	header.SetProperty("EFFORT", "2:00")
+
+	expected := []string{"ID", "OWNER", "ESTIMATE", "EFFORT"}
+	if keys := header.PropertyKeys(); strings.Join(keys, " ") != strings.Join(expected, " ") {
+		t.Errorf("expected properties in order %v, got %v", expected, keys)
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if !strings.Contains(builder.String(), "  :OWNER: bob\n  :ESTIMATE: 3\n  :EFFORT: 2:00\n  :END:\n") {
+		t.Errorf("unexpected rendered properties:\n%s", builder.String())
+	}
+}
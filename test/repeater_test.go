@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+func parseRepeatersFile(t *testing.T) (OrgFile, string) {
+	content, err := os.ReadFile("./files/repeaters.org")
+	if err != nil {
+		t.Fatalf("failed to read repeaters.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	return of, string(content)
+}
+
+// TestRepeaterFileRender tests that repeaters and warning periods survive a round trip
+func TestRepeaterFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, content := parseRepeatersFile(t)
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != content {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, builder.String())
+	}
+}
+
+// TestParseTimestamp tests that all cookies of a timestamp are parsed
+func TestParseTimestamp(t *testing.T) {
+	ts, err := ParseTimestamp("2026-10-18 Sun 07:30 .+2d/4d --1d")
+	if err != nil {
+		t.Fatalf("failed to parse timestamp: %v", err)
+	}
+
+	if !ts.WithTime() || ts.T.Hour() != 7 || ts.T.Minute() != 30 {
+		t.Errorf("expected a time of 07:30, got %s", ts.T)
+	}
+
+	repeater := ts.Repeater.Unwrap()
+	if repeater.Kind != RepeatRestart || repeater.Interval != (Interval{Value: 2, Unit: 'd'}) {
+		t.Errorf("unexpected repeater %s", repeater)
+	}
+
+	if repeater.Habit.Unwrap() != (Interval{Value: 4, Unit: 'd'}) {
+		t.Errorf("unexpected habit interval %s", repeater.Habit.Unwrap())
+	}
+
+	warning := ts.Warning.Unwrap()
+	if !warning.FirstOnly || warning.Interval != (Interval{Value: 1, Unit: 'd'}) {
+		t.Errorf("unexpected warning %s", warning)
+	}
+
+	if _, err := ParseTimestamp("next tuesday"); err == nil {
+		t.Errorf("expected an invalid timestamp to fail")
+	}
+}
+
+// TestRepeaterNext tests the three kinds of repeaters against a fixed completion time
+func TestRepeaterNext(t *testing.T) {
+	now := time.Date(2026, 10, 17, 14, 2, 0, 0, time.Local)
+	date := time.Date(2026, 9, 1, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name     string
+		repeater Repeater
+		expected time.Time
+	}{
+		{"Cumulate", Repeater{Kind: RepeatCumulate, Interval: Interval{Value: 1, Unit: 'w'}}, time.Date(2026, 9, 8, 0, 0, 0, 0, time.Local)},
+		{"CatchUp", Repeater{Kind: RepeatCatchUp, Interval: Interval{Value: 1, Unit: 'w'}}, time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)},
+		{"Restart", Repeater{Kind: RepeatRestart, Interval: Interval{Value: 3, Unit: 'd'}}, time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)},
+		{"Month", Repeater{Kind: RepeatCumulate, Interval: Interval{Value: 1, Unit: 'm'}}, time.Date(2026, 10, 1, 0, 0, 0, 0, time.Local)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if next := tt.repeater.Next(date, now, false); !next.Equal(tt.expected) {
+				t.Errorf("expected %s, got %s", tt.expected, next)
+			}
+		})
+	}
+}
+
+// TestRepeatingTaskDone tests that completing a repeating task moves it to its next occurrence
+func TestRepeatingTaskDone(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, _ := parseRepeatersFile(t)
+
+	header := option.Cast[Render, *Header](of.GetUid(NewUid(1))).Unwrap()
+	header.SetStatus(Done)
+
+	if header.Status() != RenderStatus(Todo) {
+		t.Errorf("expected the status to be reset to TODO, got %s", header.Status())
+	}
+
+	schedule := header.Schedule().Unwrap()
+	if got := schedule.Values[Scheduled].String(); got != "<2026-10-27 Tue +1w>" {
+		t.Errorf("expected the scheduled date to move a week, got %s", got)
+	}
+
+	if _, ok := schedule.Values[Closed]; ok {
+		t.Errorf("expected a repeating task not to be closed")
+	}
+
+	if header.GetProperty("LAST_REPEAT").IsNone() {
+		t.Errorf("expected the LAST_REPEAT property to be set")
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+func useLossless(t *testing.T) {
+	config := DefaultConfig()
+	config.Lossless = true
+	Configure(config)
+
+	t.Cleanup(func() { Configure(DefaultConfig()) })
+}
+
+func renderFile(of *OrgFile) string {
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	return builder.String()
+}
+
+// TestLosslessRoundTrip tests that every file of the corpus renders byte for byte the same in lossless mode
+func TestLosslessRoundTrip(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useLossless(t)
+
+	files, _ := filepath.Glob("./files/*.org")
+	corpus, _ := filepath.Glob("./files/roundtrip/*.org")
+
+	for _, path := range append(files, corpus...) {
+		t.Run(path, func(t *testing.T) {
+			content, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", path, err)
+			}
+
+			of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+			if err != nil {
+				t.Fatalf("failed to parse org file: %v", err)
+			}
+
+			if rendered := renderFile(&of); rendered != string(content) {
+				t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, rendered)
+			}
+		})
+	}
+}
+
+// TestLosslessEdit tests that only the lines of the items that changed differ from the original
+func TestLosslessEdit(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useLossless(t)
+
+	content, err := os.ReadFile("./files/roundtrip/lists.org")
+	if err != nil {
+		t.Fatalf("failed to read lists.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	header, ok := option.Cast[Render, *Header](of.GetUid(NewUid(401))).Split()
+	if !ok {
+		t.Fatalf("header 401 not found")
+	}
+
+	var bullet *Bullet
+	for _, child := range header.Children() {
+		if b, ok := child.(*Bullet); ok && b.Status() == "UNCHECKED" {
+			bullet = b
+			break
+		}
+	}
+
+	if bullet == nil {
+		t.Fatalf("no unchecked bullet found")
+	}
+
+	bullet.SetCheckbox(Checked)
+	header.SetStatus(Todo)
+
+	original := strings.Split(string(content), "\n")
+	rendered := strings.Split(renderFile(&of), "\n")
+
+	if len(original) != len(rendered) {
+		t.Fatalf("expected %d lines, got %d:\n%s", len(original), len(rendered), strings.Join(rendered, "\n"))
+	}
+
+	changed := map[string]string{
+		"* Lists":         "* TODO Lists",
+		"    - [ ] first": "    - [x] first",
+	}
+
+	for i := range original {
+		expected, ok := changed[original[i]]
+		if !ok {
+			expected = original[i]
+		}
+
+		if rendered[i] != expected {
+			t.Errorf("line %d: expected %q, got %q", i+1, expected, rendered[i])
+		}
+	}
+}
+
+// TestNormalizedRender tests that without lossless mode files are still written in the normalized form
+func TestNormalizedRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content := "\n* Header                :tag:\n  :PROPERTIES:\n  :ID:   1\n  :END:\n\n    - [X] done\n"
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	expected := "* Header :tag:\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  - [x] done\n"
+	if rendered := renderFile(&of); rendered != expected {
+		t.Errorf("expected normalized output\nExpected:\n%s\nGot:\n%s", expected, rendered)
+	}
+}
+
+// TestLosslessEditWithoutIds tests that only a header an operation changed gets its ID written
+func TestLosslessEditWithoutIds(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	useLossless(t)
+
+	content, err := os.ReadFile("./files/roundtrip/no_ids.org")
+	if err != nil {
+		t.Fatalf("failed to read no_ids.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	// the first of the two headers with this title, its drawer has no ID yet
+	var header *Header
+	for _, r := range of.ChildrenRec(-1) {
+		if h, ok := r.(*Header); ok && h.Content == "Reading list" && header == nil {
+			header = h
+		}
+	}
+
+	if header == nil {
+		t.Fatalf("header 'Reading list' not found")
+	}
+
+	uid := header.Uid()
+	header.SetContent("Reading queue")
+
+	expected := strings.Replace(string(content),
+		"** Reading list\n   :PROPERTIES:\n",
+		"** Reading queue\n   :PROPERTIES:\n   :ID: "+uid.String()+"\n",
+		1,
+	)
+
+	if rendered := renderFile(&of); rendered != expected {
+		t.Errorf("expected only the changed header to get an ID\nExpected:\n%s\nGot:\n%s", expected, rendered)
+	}
+
+	reparsed, err := OrgFileFromReader(context.TODO(), strings.NewReader(renderFile(&of))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse the written file: %v", err)
+	}
+
+	if found, ok := option.Cast[Render, *Header](reparsed.GetUid(uid)).Split(); !ok || found.Content != "Reading queue" {
+		t.Errorf("expected the written ID %s to keep addressing the header", uid)
+	}
+}
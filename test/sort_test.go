@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+)
+
+func childUids(r Render) (uids []string) {
+	for _, child := range r.Children() {
+		if _, ok := child.(*Header); ok {
+			uids = append(uids, child.Uid().String())
+		}
+	}
+
+	return
+}
+
+// TestSortHeaders tests every sort key, each case starts from sort.org
+func TestSortHeaders(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	tests := []struct {
+		name     string
+		options  SortOptions
+		expected []string
+	}{
+		{name: "Alpha", options: SortOptions{Key: SortAlpha}, expected: []string{"4", "3", "5", "2"}},
+		{name: "AlphaDescending", options: SortOptions{Key: SortAlpha, Descending: true}, expected: []string{"2", "5", "3", "4"}},
+		// the default keywords are TODO NEXT PROG | REVW DONE DELG, a header without a status goes last
+		{name: "Status", options: SortOptions{Key: SortStatus}, expected: []string{"3", "5", "2", "4"}},
+		// without a priority a header counts as B, ties are ordered by headline
+		{name: "Priority", options: SortOptions{Key: SortPriority}, expected: []string{"4", "3", "5", "2"}},
+		{name: "Scheduled", options: SortOptions{Key: SortScheduled}, expected: []string{"5", "2", "3", "4"}},
+		{name: "ScheduledDescending", options: SortOptions{Key: SortScheduled, Descending: true}, expected: []string{"3", "2", "5", "4"}},
+		{name: "Deadline", options: SortOptions{Key: SortDeadline, Stable: true}, expected: []string{"5", "2", "3", "4"}},
+		{name: "Closed", options: SortOptions{Key: SortClosed, Stable: true}, expected: []string{"2", "3", "4", "5"}},
+		// numbers are compared numerically, equal values keep their order when the sort is stable
+		{name: "PropertyStable", options: SortOptions{Key: SortProperty, Property: "EFFORT", Stable: true}, expected: []string{"3", "5", "2", "4"}},
+		{name: "PropertyDescending", options: SortOptions{Key: SortProperty, Property: "EFFORT", Descending: true}, expected: []string{"2", "3", "5", "4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			of := parseNamedFile(t, "sort.org", "sort.org")
+
+			if err := of.Sort(NewUid(1), tt.options); err != nil {
+				t.Fatalf("failed to sort: %v", err)
+			}
+
+			if got := childUids(of.GetUid(NewUid(1)).Unwrap()); !slices.Equal(got, tt.expected) {
+				t.Errorf("expected order %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestSortRecursive tests that a recursive sort orders the sub headers as well and that the body stays on top
+func TestSortRecursive(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of := parseNamedFile(t, "sort.org", "sort.org")
+
+	if err := of.Sort(NewUid(0), SortOptions{Key: SortAlpha, Recursive: true}); err != nil {
+		t.Fatalf("failed to sort: %v", err)
+	}
+
+	if got := childUids(of.GetUid(NewUid(3)).Unwrap()); !slices.Equal(got, []string{"7", "6"}) {
+		t.Errorf("expected the children of 3 to be sorted, got %v", got)
+	}
+
+	rendered := renderFile(of)
+	if !strings.HasPrefix(rendered, "* Projects\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  Notes stay above the sub headers.\n** [#A] Answer mail\n") {
+		t.Errorf("expected the body to stay above the sorted headers, got:\n%s", rendered)
+	}
+
+	if err := of.Sort(NewUid(1), SortOptions{Key: SortProperty}); err == nil {
+		t.Errorf("expected an error when sorting by property without a property name")
+	}
+
+	if err := of.Sort(NewUid(1), SortOptions{Key: "size"}); err == nil {
+		t.Errorf("expected an error for an unknown sort key")
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+func parseTablesFile(t *testing.T) (OrgFile, string) {
+	content, err := os.ReadFile("./files/tables.org")
+	if err != nil {
+		t.Fatalf("failed to read tables.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	return of, string(content)
+}
+
+// TestTableParsing tests that table lines are parsed into a single Table with its formulas
+func TestTableParsing(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, _ := parseTablesFile(t)
+
+	table, ok := option.Cast[Render, *Table](of.GetUid(NewUid("1.tbl0f4144"))).Split()
+	if !ok {
+		t.Fatalf("expected a table with UID 1.tbl0f4144")
+	}
+
+	rows := table.Rows()
+	if len(rows) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(rows))
+	}
+
+	if !slices.Equal(rows[1], []string{"Parser", "3", "alice"}) {
+		t.Errorf("unexpected row %v", rows[1])
+	}
+
+	if !slices.Equal(table.Formulas(), []string{"@>$2=vsum(@I..@II)"}) {
+		t.Errorf("unexpected formulas %v", table.Formulas())
+	}
+
+	if _, ok := of.GetUid(NewUid("1.tee8bcf")).Split(); !ok {
+		t.Errorf("expected the text after the table to be parsed as plain text")
+	}
+}
+
+// TestTableRender tests that aligned tables round trip and unaligned tables get aligned
+func TestTableRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, content := parseTablesFile(t)
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	expected := strings.Replace(content, "  |a|bb|\n  |-\n  |ccc||\n", "  | a   | bb |\n  |-----+----|\n  | ccc |    |\n", 1)
+	if builder.String() != expected {
+		t.Errorf("rendered output does not match\nExpected:\n%s\nGot:\n%s", expected, builder.String())
+	}
+}
+
+// TestTableEdit tests setting cells and adding and removing rows
+func TestTableEdit(t *testing.T) {
+	table := NewTable([][]string{{"Name", "Count"}, {"a", "1"}}, true)
+
+	if err := table.SetCell(1, 1, "12"); err != nil {
+		t.Fatalf("failed to set cell: %v", err)
+	}
+
+	if err := table.SetCell(0, 2, "Note"); err != nil {
+		t.Fatalf("failed to set cell: %v", err)
+	}
+
+	table.AppendRow([]string{"b|c", "3"})
+
+	if err := table.DeleteRow(5); err == nil {
+		t.Errorf("expected an error when deleting a row out of range")
+	}
+
+	builder := strings.Builder{}
+	table.Render(&builder, -1)
+
+	expected := "" +
+		"| Name    | Count | Note |\n" +
+		"|---------+-------+------|\n" +
+		"| a       |    12 |      |\n" +
+		"| b\\vertc |     3 |      |\n"
+
+	if builder.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, builder.String())
+	}
+
+	if err := table.DeleteRow(1); err != nil {
+		t.Fatalf("failed to delete row: %v", err)
+	}
+
+	if table.Csv() != "Name,Count,Note\nb\\vertc,3\n" {
+		t.Errorf("unexpected csv %q", table.Csv())
+	}
+}
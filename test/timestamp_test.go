@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+func parseTimesFile(t *testing.T) (OrgFile, string) {
+	content, err := os.ReadFile("./files/times.org")
+	if err != nil {
+		t.Fatalf("failed to read times.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	return of, string(content)
+}
+
+// TestTimesFileRender tests that times of day, time ranges and date ranges survive a round trip
+func TestTimesFileRender(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, content := parseTimesFile(t)
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != content {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", content, builder.String())
+	}
+}
+
+// TestTimesColumns tests that the date columns include times and ranges
+func TestTimesColumns(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	of, _ := parseTimesFile(t)
+
+	tests := []struct {
+		uid      int
+		column   Column
+		expected string
+	}{
+		{1, ColScheduled, "2026-10-20 09:00"},
+		{2, ColScheduled, "2026-10-20 14:00-15:30"},
+		{2, ColDeadline, "2026-10-21 17:00"},
+		{3, ColScheduled, "2026-10-22--2026-10-24"},
+		{4, ColScheduled, "2026-10-12 10:00--2026-10-13 16:00"},
+		{4, ColClosed, "2026-10-13 16:05"},
+	}
+
+	for _, tt := range tests {
+		header := of.GetUid(NewUid(tt.uid)).Unwrap()
+
+		if val := tt.column.Value(header, ","); val != tt.expected {
+			t.Errorf("expected %s of %d to be %s, got %s", tt.column.String(), tt.uid, tt.expected, val)
+		}
+	}
+}
+
+// TestTimestampRanges tests the parsed start and end of time and date ranges
+func TestTimestampRanges(t *testing.T) {
+	ts, err := ParseTimestamp("<2026-10-20 Tue 14:00-15:30>")
+	if err != nil {
+		t.Fatalf("failed to parse timestamp: %v", err)
+	}
+
+	if ts.IsDateRange() || !ts.EndOrStart().Equal(time.Date(2026, 10, 20, 15, 30, 0, 0, time.Local)) {
+		t.Errorf("expected a time range ending at 15:30, got %s", ts.EndOrStart())
+	}
+
+	ts, err = ParseTimestamp("[2026-10-22 Thu]--[2026-10-24 Sat]")
+	if err != nil {
+		t.Fatalf("failed to parse timestamp: %v", err)
+	}
+
+	if !ts.IsDateRange() || ts.Active() || ts.String() != "[2026-10-22 Thu]--[2026-10-24 Sat]" {
+		t.Errorf("expected an inactive date range, got %s", ts)
+	}
+
+	if err := ts.SetTimeRange(time.Date(2026, 10, 22, 10, 0, 0, 0, time.Local), option.Some(time.Date(2026, 10, 22, 9, 0, 0, 0, time.Local))); err == nil {
+		t.Errorf("expected an end time before the start time to be rejected")
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/p3rtang/org-mcp/orgmcp"
+)
+
+// TestTodoKeywordsFile tests that a `#+TODO:` line replaces the default TODO sequence
+func TestTodoKeywordsFile(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	content, err := os.ReadFile("./files/todo_keywords.org")
+	if err != nil {
+		t.Fatalf("failed to read todo_keywords.org: %v", err)
+	}
+
+	of, err := OrgFileFromReader(context.TODO(), strings.NewReader(string(content))).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	keywords := of.TodoKeywords()
+	if !slices.Equal(keywords.Active, []HeaderStatus{"TODO", "WAIT", "BLOCKED"}) {
+		t.Errorf("unexpected active states %v", keywords.Active)
+	}
+
+	if !slices.Equal(keywords.Done, []HeaderStatus{"DONE", "CANCELLED"}) {
+		t.Errorf("unexpected done states %v", keywords.Done)
+	}
+
+	tests := []struct {
+		uid     int
+		status  RenderStatus
+		content string
+	}{
+		{1, "TODO", "Write the report"},
+		{2, "WAIT", "Feedback from the team"},
+		{3, "CANCELLED", "Book a meeting room"},
+		{4, "", "NEXT is not a keyword in this file"},
+	}
+
+	for _, tt := range tests {
+		header, ok := of.GetUid(NewUid(tt.uid)).Split()
+		if !ok {
+			t.Fatalf("header %d not found", tt.uid)
+		}
+
+		status := header.Status()
+		if status == RenderStatus(None) {
+			status = ""
+		}
+
+		if status != tt.status {
+			t.Errorf("header %d: expected status %q, got %q", tt.uid, tt.status, status)
+		}
+
+		if header.Preview(-1) != tt.content {
+			t.Errorf("header %d: expected content %q, got %q", tt.uid, tt.content, header.Preview(-1))
+		}
+	}
+
+	builder := strings.Builder{}
+	of.Render(&builder, -1)
+
+	if builder.String() != string(content) {
+		t.Errorf("rendered output does not match original\nExpected:\n%s\nGot:\n%s", string(content), builder.String())
+	}
+}
+
+// TestTodoKeywordsDoneState tests that custom done states close a header
+func TestTodoKeywordsDoneState(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	file, err := os.Open("./files/todo_keywords.org")
+	if err != nil {
+		t.Fatalf("failed to open todo_keywords.org: %v", err)
+	}
+	defer file.Close()
+
+	of := OrgFileFromReader(context.TODO(), file).Unwrap()
+	header := of.GetUid(NewUid(2)).Unwrap().(*Header)
+
+	header.SetStatus("CANCELLED")
+
+	schedule, ok := header.Schedule().Split()
+	if !ok {
+		t.Fatalf("expected CANCELLED to add a CLOSED timestamp")
+	}
+
+	if schedule.Values[Closed].T.IsZero() {
+		t.Errorf("expected CLOSED timestamp to be set")
+	}
+}
+
+// TestParseTodoKeywords tests parsing of the `#+TODO:` value
+func TestParseTodoKeywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		active []HeaderStatus
+		done   []HeaderStatus
+		err    bool
+	}{
+		{
+			name:   "With separator",
+			input:  "TODO NEXT | DONE",
+			active: []HeaderStatus{"TODO", "NEXT"},
+			done:   []HeaderStatus{"DONE"},
+		},
+		{
+			name:   "Without separator",
+			input:  "TODO FEEDBACK VERIFY DONE",
+			active: []HeaderStatus{"TODO", "FEEDBACK", "VERIFY"},
+			done:   []HeaderStatus{"DONE"},
+		},
+		{
+			name:   "Fast access keys",
+			input:  "TODO(t) WAIT(w@/!) | DONE(d!) CANCELED(c@)",
+			active: []HeaderStatus{"TODO", "WAIT"},
+			done:   []HeaderStatus{"DONE", "CANCELED"},
+		},
+		{
+			name:  "Empty",
+			input: "  ",
+			err:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keywords, err := ParseTodoKeywords(tt.input)
+			if tt.err {
+				if err == nil {
+					t.Errorf("expected an error for %q", tt.input)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !slices.Equal(keywords.Active, tt.active) || !slices.Equal(keywords.Done, tt.done) {
+				t.Errorf("got %s, want %v | %v", keywords, tt.active, tt.done)
+			}
+		})
+	}
+}
+
+// TestConfiguredTodoKeywords tests that the configured sequence is used for files without a `#+TODO:` line
+func TestConfiguredTodoKeywords(t *testing.T) {
+	os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+
+	keywords, _ := ParseTodoKeywords("OPEN | CLOSED")
+	Configure(Config{TodoKeywords: keywords})
+	defer Configure(DefaultConfig())
+
+	of := OrgFileFromReader(context.TODO(), strings.NewReader("* OPEN First\n* TODO Second\n")).Unwrap()
+	children := of.Children()
+
+	if children[0].Status() != "OPEN" {
+		t.Errorf("expected OPEN status, got %s", children[0].Status())
+	}
+
+	if children[1].Status() != RenderStatus(None) {
+		t.Errorf("expected TODO to be part of the title, got status %s", children[1].Status())
+	}
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/p3rtang/org-mcp/mcp"
+)
+
+type RestoreBackupInputSchema struct {
+	Path     string `json:"path,omitempty" jsonschema:"description=The file path to the Org file to restore. It will target the ./.tasks.org by default and you don't have to pass this in unless you want to target a different file.,required=false"`
+	Backup   string `json:"backup,omitempty" jsonschema:"description=The name of the backup to restore as listed by this tool; leave it out to list the backups.,required=false"`
+	ShowDiff bool   `json:"show_diff,omitempty" jsonschema:"description=Whether to show the diff of changes made to the Org file.,default=false"`
+}
+
+var RestoreBackupTool = mcp.GenericTool[RestoreBackupInputSchema]{
+	Name: "restore_backup",
+	Description: `
+Lists the backups of an Org file or replaces the file with one of them.
+A backup is made every time a tool overwrites a file, when the server runs with a backup directory. Only the newest backups are kept.
+
+Without a backup name the backups are listed, the newest first, with their name, time and size.
+With a backup name the file is replaced with that backup. The current content is backed up first,
+so restoring that new backup undoes the restore.
+`,
+
+	Callback: func(ctx context.Context, input RestoreBackupInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		if input.Backup == "" {
+			backups, err := mcp.ListBackups(path)
+			if err != nil {
+				return nil, err
+			}
+
+			list := []map[string]any{}
+			for _, backup := range backups {
+				list = append(list, map[string]any{
+					"name": backup.Name,
+					"time": backup.Time.Format("2006-01-02 15:04:05"),
+					"size": backup.Size,
+				})
+			}
+
+			return append(resp, map[string]any{"backups": list}), nil
+		}
+
+		diff, err := mcp.RestoreBackup(path, input.Backup)
+		if err != nil {
+			return
+		}
+
+		options.Files.Invalidate(path)
+
+		resp = append(resp, map[string]any{"restored": input.Backup})
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
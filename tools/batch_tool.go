@@ -0,0 +1,414 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/itertools"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+// referenceRegex matches a reference to the item created by an earlier operation of a batch, like $1.
+var referenceRegex = regexp.MustCompile(`^\$(\d+)$`)
+
+// referenceFields are the fields of an operation that hold a UID, only these can reference an earlier operation
+// so text like $100 in the content of an item is kept as is.
+var referenceFields = []string{"uid", "parent", "other"}
+
+type BatchInputSchema struct {
+	Operations   []mcp.OneOf[*BatchInputUnion] `json:"operations" jsonschema:"description=The operations to perform; they are applied in order and either all of them are written or none."`
+	Path         string                        `json:"path,omitempty" jsonschema:"description=The path to the Org file to modify; if not provided it will default to the current workspace file,required=false"`
+	DryRun       bool                          `json:"dry_run,omitempty" jsonschema:"description=Apply the operations without writing the file; to check that they all succeed.,required=false"`
+	ShowDiff     bool                          `json:"show_diff,omitempty" jsonschema:"description=Whether to show a diff of the changes made; default is false,default=false"`
+	ShowAffected *bool                         `json:"show_affected,omitempty" jsonschema:"description=Whether to include the affected items in the response with their uid.,default=true,required=false"`
+	Columns      []*orgmcp.Column              `json:"columns,omitempty" jsonschema:"description=List of columns to include in the output. If not specified defaults to [UID ; PARENT ; PREVIEW]."`
+}
+
+type BatchInputUnion struct {
+	tag string
+
+	Header BatchInputHeader
+	Bullet BatchInputBullet
+	Text   BatchInputText
+	Move   BatchInputMove
+}
+
+func NewBatchInputUnion[T BatchInputHeader | BatchInputBullet | BatchInputText | BatchInputMove](input T) *BatchInputUnion {
+	switch v := any(input).(type) {
+	case BatchInputHeader:
+		v.Tool = "manage_header"
+		return &BatchInputUnion{tag: v.Tool, Header: v}
+	case BatchInputBullet:
+		v.Tool = "manage_bullet"
+		return &BatchInputUnion{tag: v.Tool, Bullet: v}
+	case BatchInputText:
+		v.Tool = "manage_text"
+		return &BatchInputUnion{tag: v.Tool, Text: v}
+	case BatchInputMove:
+		v.Tool = "move_item"
+		return &BatchInputUnion{tag: v.Tool, Move: v}
+	default:
+		panic(fmt.Sprintf("unsupported type for BatchInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
+func (b *BatchInputUnion) Value() any {
+	switch b.tag {
+	case "manage_header":
+		return b.Header
+	case "manage_bullet":
+		return b.Bullet
+	case "manage_text":
+		return b.Text
+	case "move_item":
+		return b.Move
+	default:
+		return nil
+	}
+}
+
+func (b *BatchInputUnion) Tag() string {
+	return b.tag
+}
+
+func (b *BatchInputUnion) FromJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw["tool"] {
+	case "manage_header":
+		b.tag = "manage_header"
+		return json.Unmarshal(data, &b.Header)
+	case "manage_bullet":
+		b.tag = "manage_bullet"
+		return json.Unmarshal(data, &b.Bullet)
+	case "manage_text":
+		b.tag = "manage_text"
+		return json.Unmarshal(data, &b.Text)
+	case "move_item":
+		b.tag = "move_item"
+		return json.Unmarshal(data, &b.Move)
+	default:
+		return fmt.Errorf("invalid tool: %s", raw["tool"])
+	}
+}
+
+// operation returns the operation of the tool, it is what references are resolved in. It is nil when the
+// operation is missing.
+func (b *BatchInputUnion) operation() mcp.TaggedUnion {
+	switch {
+	case b.tag == "manage_header" && b.Header.Operation.Value != nil:
+		return b.Header.Operation.Value
+	case b.tag == "manage_bullet" && b.Bullet.Operation.Value != nil:
+		return b.Bullet.Operation.Value
+	case b.tag == "manage_text" && b.Text.Operation.Value != nil:
+		return b.Text.Operation.Value
+	case b.tag == "move_item" && b.Move.Operation.Value != nil:
+		return b.Move.Operation.Value
+	default:
+		return nil
+	}
+}
+
+// creates reports whether the operation adds an item that later operations can reference.
+func (b *BatchInputUnion) creates() bool {
+	return b.tag != "move_item" && b.operation().Tag() == "add"
+}
+
+func (b *BatchInputUnion) apply(ctx context.Context, of *orgmcp.OrgFile, files *fileSet) ApplyResult {
+	switch b.tag {
+	case "manage_header":
+		return b.Header.Operation.Value.Apply(ctx, of)
+	case "manage_bullet":
+		return b.Bullet.Operation.Value.Apply(ctx, of)
+	case "manage_text":
+		return b.Text.Operation.Value.Apply(ctx, of)
+	case "move_item":
+		return b.Move.Operation.Value.Apply(ctx, of, files)
+	default:
+		return ApplyResult{err: fmt.Errorf("invalid tool: %s", b.tag)}
+	}
+}
+
+type BatchInputHeader struct {
+	Tool      string                       `json:"tool" jsonschema:"description=Add; update or remove a header like manage_header.,enum=manage_header"`
+	Operation mcp.OneOf[*HeaderInputUnion] `json:"operation" jsonschema:"description=An operation of manage_header."`
+}
+
+type BatchInputBullet struct {
+	Tool      string                       `json:"tool" jsonschema:"description=Add; update or remove a bullet like manage_bullet.,enum=manage_bullet"`
+	Operation mcp.OneOf[*BulletInputUnion] `json:"operation" jsonschema:"description=An operation of manage_bullet."`
+}
+
+type BatchInputText struct {
+	Tool      string                     `json:"tool" jsonschema:"description=Read; add; update or remove text like manage_text.,enum=manage_text"`
+	Operation mcp.OneOf[*TextInputUnion] `json:"operation" jsonschema:"description=An operation of manage_text."`
+}
+
+type BatchInputMove struct {
+	Tool      string                     `json:"tool" jsonschema:"description=Move; refile; archive or sort items like move_item.,enum=move_item"`
+	Operation mcp.OneOf[*MoveInputUnion] `json:"operation" jsonschema:"description=An operation of move_item."`
+}
+
+// references returns the operations a batch operation references, by their 1-based index.
+func references(op *BatchInputUnion) (refs []int, err error) {
+	raw, err := rawOperation(op)
+	if err != nil {
+		return
+	}
+
+	walkReferences(raw, func(s string) string {
+		if match := referenceRegex.FindStringSubmatch(s); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			refs = append(refs, n)
+		}
+
+		return s
+	})
+
+	return
+}
+
+// resolve replaces the references of an operation with the UIDs created by the earlier operations.
+func resolve(op *BatchInputUnion, created map[int]orgmcp.Uid) error {
+	raw, err := rawOperation(op)
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	walkReferences(raw, func(s string) string {
+		if match := referenceRegex.FindStringSubmatch(s); match != nil {
+			n, _ := strconv.Atoi(match[1])
+			replaced = true
+
+			return created[n].String()
+		}
+
+		return s
+	})
+
+	if !replaced {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return op.operation().FromJSON(data)
+}
+
+// rawOperation returns the operation of a batch operation as decoded JSON.
+func rawOperation(op *BatchInputUnion) (raw map[string]any, err error) {
+	data, err := json.Marshal(op.operation().Value())
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &raw)
+	return
+}
+
+// walkReferences replaces the values of the UID fields of an operation.
+func walkReferences(raw map[string]any, replace func(string) string) {
+	for _, field := range referenceFields {
+		if value, ok := raw[field].(string); ok {
+			raw[field] = replace(value)
+		}
+	}
+}
+
+// createdUid returns the UID of the single item an operation added.
+func createdUid(res ApplyResult) option.Option[orgmcp.Uid] {
+	if len(res.affectedItems) != 1 {
+		return option.None[orgmcp.Uid]()
+	}
+
+	for _, item := range res.affectedItems {
+		return option.Some(item.Uid())
+	}
+
+	return option.None[orgmcp.Uid]()
+}
+
+var BatchTool = mcp.GenericTool[BatchInputSchema]{
+	Name: "batch_edit",
+	Description: `
+Apply operations of manage_header, manage_bullet, manage_text and move_item in one transaction: either every operation succeeds and the file is written, or nothing is written.
+Each operation names its tool and holds an operation exactly like the list items of that tool, for example
+` + "`" + `{"tool": "manage_header", "operation": {"method": "add", "parent": "0", "content": "Release"}}` + "`" + `.
+
+## References
+An operation can use the UID of an item added by an earlier operation of the same batch with ` + "`$n`" + ` in its uid, parent or other field, where n is the 1-based position of that operation.
+For example ` + "`" + `{"tool": "manage_bullet", "operation": {"method": "add", "parent": "$1", "content": "Write notes"}}` + "`" + ` adds a bullet to the header added by the first operation.
+
+## Results
+The response lists every operation with its status: ok, failed or skipped. After the first failure the remaining operations are skipped and the file stays as it was.
+Added items include their uid. With dry_run the operations are checked without writing anything.
+`,
+	Callback: func(ctx context.Context, input BatchInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		// validate every operation before any of them is applied
+		for i, op := range input.Operations {
+			if op.Value == nil || op.Value.operation() == nil {
+				return nil, fmt.Errorf("operation %d: the operation of the tool is missing", i+1)
+			}
+
+			refs, err := references(op.Value)
+			if err != nil {
+				return nil, fmt.Errorf("operation %d: %w", i+1, err)
+			}
+
+			for _, ref := range refs {
+				if ref < 1 || ref > i {
+					return nil, fmt.Errorf("operation %d: $%d does not refer to an earlier operation", i+1, ref)
+				}
+
+				if !input.Operations[ref-1].Value.creates() {
+					return nil, fmt.Errorf("operation %d: $%d does not add an item", i+1, ref)
+				}
+			}
+		}
+
+		// the loaded file is a copy only this call works on, when it is not written the next call reads the file again
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		files := newFileSet(&orgFile, path, options.Files)
+
+		if len(input.Columns) == 0 {
+			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColParentValue, &orgmcp.ColPreviewValue}
+		}
+
+		results := []map[string]any{}
+		created := map[int]orgmcp.Uid{}
+		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
+		failed := false
+
+		for i, op := range input.Operations {
+			result := map[string]any{
+				"operation": i + 1,
+				"tool":      op.Value.Tag(),
+				"method":    op.Value.operation().Tag(),
+			}
+			results = append(results, result)
+
+			if failed {
+				result["status"] = "skipped"
+				continue
+			}
+
+			res := ApplyResult{err: resolve(op.Value, created)}
+			if res.err == nil {
+				res = op.Value.apply(ctx, &orgFile, files)
+				// later operations look up the items this one added or changed by their UID
+				orgFile.Reindex()
+			}
+
+			if res.err == nil && op.Value.creates() {
+				if uid, ok := createdUid(res).Split(); ok {
+					created[i+1] = uid
+					result["uid"] = uid.String()
+				} else {
+					res.err = fmt.Errorf("added %d items, only an operation that adds one item can be referenced", len(res.affectedItems))
+				}
+			}
+
+			if res.err != nil {
+				result["status"] = "failed"
+				result["error"] = res.err.Error()
+				failed = true
+				continue
+			}
+
+			result["status"] = "ok"
+			if len(res.output) > 0 {
+				result["output"] = res.output
+			}
+
+			maps.Copy(affectedItems, res.affectedItems)
+		}
+
+		resp = append(resp, map[string]any{"results": results})
+
+		if failed || input.DryRun {
+			// drop the changed copies, nothing of the batch is written
+			files.invalidate()
+
+			resp = append(resp, map[string]any{"committed": false})
+
+			return
+		}
+
+		if (input.ShowAffected == nil || *input.ShowAffected == true) && len(affectedItems) > 0 {
+			locationTable := orgFile.BuildLocationTable()
+			ordered := []orgmcp.Render{}
+
+			// an item moved twice is in the map under both uids
+			for _, item := range itertools.Collect(maps.Values(affectedItems)) {
+				if !slices.Contains(ordered, item) {
+					ordered = append(ordered, item)
+				}
+			}
+
+			slices.SortFunc(ordered, func(a, b orgmcp.Render) int {
+				return (*locationTable)[a.Uid()] - (*locationTable)[b.Uid()]
+			})
+
+			resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
+		}
+
+		// a conflict or lock in any of the files stops the batch before the first file is written
+		if err = mcp.CheckWrite(orgFile, path); err == nil {
+			err = files.check()
+		}
+
+		if err != nil {
+			files.invalidate()
+
+			return
+		}
+
+		// the other files are written first, a failed write leaves the moved subtrees in this file
+		diffs, err := files.write(ctx)
+		if err != nil {
+			return
+		}
+
+		diff, err := writeJournaled(ctx, options, "batch_edit", orgFile, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, map[string]any{"committed": true})
+
+		if input.ShowDiff {
+			resp = append(resp, diffs...)
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
@@ -0,0 +1,267 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/itertools"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+type BlockInputSchema struct {
+	Blocks       []mcp.OneOf[*BlockInputUnion] `json:"blocks" jsonschema:"description=The list of block modifications to perform"`
+	Path         string                        `json:"path,omitempty" jsonschema:"description=The path to the Org file to modify; if not provided it will default to the current workspace file,required=false"`
+	ShowDiff     bool                          `json:"show_diff,omitempty" jsonschema:"description=Whether to show a diff of the changes made; default is false,default=false"`
+	ShowAffected *bool                         `json:"show_affected,omitempty" jsonschema:"description=Whether to include the affected items in the response. This will include all items that were modified as well as their children.,default=true,required=false"`
+	Columns      []*orgmcp.Column              `json:"columns,omitempty" jsonschema:"description=List of columns to include in the output. If not specified defaults to [UID ; PREVIEW]."`
+}
+
+type BlockInputUnion struct {
+	tag string
+
+	Add    BlockInputAdd
+	Update BlockInputUpdate
+	Remove BlockInputRemove
+}
+
+func NewBlockInputUnion[T BlockInputAdd | BlockInputUpdate | BlockInputRemove](input T) *BlockInputUnion {
+	switch any(input).(type) {
+	case BlockInputAdd:
+		return &BlockInputUnion{
+			tag: "add",
+			Add: any(input).(BlockInputAdd),
+		}
+	case BlockInputUpdate:
+		return &BlockInputUnion{
+			tag:    "update",
+			Update: any(input).(BlockInputUpdate),
+		}
+	case BlockInputRemove:
+		return &BlockInputUnion{
+			tag:    "remove",
+			Remove: any(input).(BlockInputRemove),
+		}
+	default:
+		panic(fmt.Sprintf("unsupported type for BlockInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
+func (b *BlockInputUnion) Value() any {
+	switch b.tag {
+	case "add":
+		return b.Add
+	case "update":
+		return b.Update
+	case "remove":
+		return b.Remove
+	default:
+		return nil
+	}
+}
+
+func (b *BlockInputUnion) Tag() string {
+	return b.tag
+}
+
+func (b *BlockInputUnion) FromJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw["method"] {
+	case "add":
+		b.tag = "add"
+		return json.Unmarshal(data, &b.Add)
+	case "update":
+		b.tag = "update"
+		return json.Unmarshal(data, &b.Update)
+	case "remove":
+		b.tag = "remove"
+		return json.Unmarshal(data, &b.Remove)
+	default:
+		return fmt.Errorf("invalid method: %s", raw["method"])
+	}
+}
+
+type BlockInputAdd struct {
+	Method     string `json:"method" jsonschema:"description=Add a new block under the specified parent element.,enum=add"`
+	Parent     string `json:"parent" jsonschema:"description=The UID of the parent element under which the block will be added. This can be either a header or a bullet point."`
+	Kind       string `json:"kind" jsonschema:"description=The kind of block (e.g. SRC; EXAMPLE; QUOTE; VERSE). Any other word creates a custom block."`
+	Parameters string `json:"parameters,omitempty" jsonschema:"description=Everything after the kind on the begin line. For source blocks this is the language followed by switches and header arguments (e.g. 'go -n :results output')."`
+	Content    string `json:"content" jsonschema:"description=The content of the block. It is stored verbatim and may contain newlines."`
+}
+
+func (b *BlockInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+	parent, ok := of.GetUid(orgmcp.NewUid(b.Parent)).Split()
+
+	if !ok {
+		res.err = fmt.Errorf("Parent uid %s not found.", b.Parent)
+		return
+	}
+
+	if strings.TrimSpace(b.Kind) == "" || strings.ContainsAny(strings.TrimSpace(b.Kind), " \t\n") {
+		res.err = fmt.Errorf("Invalid block kind '%s', use a single word like SRC or QUOTE.", b.Kind)
+		return
+	}
+
+	block := orgmcp.NewBlock(b.Kind, b.Parameters, b.Content)
+	if err := parent.AddChildren(&block); err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[block.Uid()] = &block
+
+	return
+}
+
+type BlockInputUpdate struct {
+	Method     string  `json:"method" jsonschema:"description=Update the content or parameters of a block.,enum=update"`
+	Uid        string  `json:"uid" jsonschema:"description=The UID of the block to update."`
+	Parameters *string `json:"parameters,omitempty" jsonschema:"description=The new parameters of the begin line. Omit this field to keep them unchanged; an empty string clears them."`
+	Content    *string `json:"content,omitempty" jsonschema:"description=The new content of the block. Omit this field to keep the content unchanged."`
+}
+
+func (b *BlockInputUpdate) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+	block, ok := option.Cast[orgmcp.Render, *orgmcp.Block](of.GetUid(orgmcp.NewUid(b.Uid))).Split()
+
+	if !ok {
+		res.err = fmt.Errorf("Uid %s is not a block in %s.", b.Uid, of.Name())
+		return
+	}
+
+	if b.Parameters != nil {
+		block.SetParameters(*b.Parameters)
+	}
+
+	if b.Content != nil {
+		block.SetContent(*b.Content)
+	}
+
+	res.affectedItems[block.Uid()] = block
+
+	return
+}
+
+type BlockInputRemove struct {
+	Method string `json:"method" jsonschema:"description=Remove the block.,enum=remove"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the block to remove."`
+}
+
+func (b *BlockInputRemove) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+	block, ok := option.Cast[orgmcp.Render, *orgmcp.Block](of.GetUid(orgmcp.NewUid(b.Uid))).Split()
+
+	if !ok {
+		res.err = fmt.Errorf("Uid %s is not a block in %s.", b.Uid, of.Name())
+		return
+	}
+
+	p_uid := block.ParentUid()
+	if parent, ok := of.GetUid(p_uid).Split(); ok {
+		parent.RemoveChildren(block.Uid())
+		res.affectedItems[p_uid] = parent
+	} else {
+		res.err = fmt.Errorf("Parent with uid %s not found for block with uid %s", p_uid, b.Uid)
+	}
+
+	return
+}
+
+var BlockTool = mcp.GenericTool[BlockInputSchema]{
+	Name: "manage_block",
+	Description: `
+Add, update or remove #+BEGIN/#+END blocks in an Org file, like source code, examples and quotes.
+The content of a block is kept verbatim, use blocks for code snippets, logs or anything else that should not be parsed as org markup.
+Lines starting with '*' or '#+' are escaped with a comma in the file, the content you pass in and get back is unescaped.
+
+## Methods
+` +
+		"`add`: Adds a new block to the specified parent element. The parent is passed via the parent parameter.\n" +
+		"`update`: Updates the content or parameters of the block. The block is identified by its uid.\n" +
+		"`remove`: Removes the block. The block is identified by its uid.\n" +
+		`
+## UID Constructions
+Blocks can be added to a header or a bullet point.
+The uid is the parent uid followed by '.blk' and a hash of the begin line and content, e.g. parent_uid.blk3f9a0c.
+Updating the content or parameters gives the block a new uid which is returned in the output.
+A second block with the same begin line and content under the same parent gets a '-2' suffix.
+`,
+	Callback: func(ctx context.Context, input BlockInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		if len(input.Columns) == 0 {
+			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue}
+		}
+
+		affectedCount := 0
+		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
+
+		for _, mt := range input.Blocks {
+			var res ApplyResult
+
+			switch mt.Value.Tag() {
+			case "add":
+				res = mt.Value.Add.Apply(ctx, &orgFile)
+			case "update":
+				res = mt.Value.Update.Apply(ctx, &orgFile)
+			case "remove":
+				res = mt.Value.Remove.Apply(ctx, &orgFile)
+			}
+
+			orgFile.Reindex()
+
+			if res.err != nil {
+				resp = append(resp, res.err.Error())
+			}
+
+			maps.Copy(affectedItems, res.affectedItems)
+			affectedCount += len(res.affectedItems)
+		}
+
+		ordered := []orgmcp.Render{}
+
+		if input.ShowAffected == nil || *input.ShowAffected == true {
+			locationTable := orgFile.BuildLocationTable()
+			ordered = append(ordered, itertools.Collect(maps.Values(affectedItems))...)
+
+			slices.SortFunc(ordered, func(a, b orgmcp.Render) int {
+				return (*locationTable)[a.Uid()] - (*locationTable)[b.Uid()]
+			})
+
+			resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
+			resp = append(resp, map[string]any{
+				"affected_count": affectedCount,
+			})
+		}
+
+		diff, err := options.Files.Write(ctx, orgFile, path)
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
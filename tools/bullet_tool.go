@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"maps"
+	"reflect"
 	"slices"
 
 	"github.com/p3rtang/org-mcp/mcp"
@@ -29,6 +30,28 @@ type BulletInputUnion struct {
 	Remove BulletInputRemove
 }
 
+func NewBulletInputUnion[T BulletInputAdd | BulletInputUpdate | BulletInputRemove](input T) *BulletInputUnion {
+	switch any(input).(type) {
+	case BulletInputAdd:
+		return &BulletInputUnion{
+			tag: "add",
+			Add: any(input).(BulletInputAdd),
+		}
+	case BulletInputUpdate:
+		return &BulletInputUnion{
+			tag:    "update",
+			Update: any(input).(BulletInputUpdate),
+		}
+	case BulletInputRemove:
+		return &BulletInputUnion{
+			tag:    "remove",
+			Remove: any(input).(BulletInputRemove),
+		}
+	default:
+		panic(fmt.Sprintf("unsupported type for BulletInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
 func (b *BulletInputUnion) Value() any {
 	switch b.tag {
 	case "add":
@@ -67,6 +90,20 @@ func (b *BulletInputUnion) FromJSON(data []byte) error {
 	}
 }
 
+// Apply applies the operation of the union to the file.
+func (b *BulletInputUnion) Apply(ctx context.Context, of *orgmcp.OrgFile) ApplyResult {
+	switch b.tag {
+	case "add":
+		return b.Add.Apply(ctx, of)
+	case "update":
+		return b.Update.Apply(ctx, of)
+	case "remove":
+		return b.Remove.Apply(ctx, of)
+	default:
+		return ApplyResult{err: fmt.Errorf("invalid method: %s", b.tag)}
+	}
+}
+
 type BulletInputAdd struct {
 	Method   string `json:"method" jsonschema:"description=The action to perform on the bullet point.,enum=add,required=true"`
 	Parent   string `json:"parent" jsonschema:"description=UID of the parent header or bullet under which to add the new bullet point."`
@@ -170,9 +207,11 @@ var BulletTool = mcp.GenericTool[BulletInput]{
 		"- 'add': Adds a new bullet point at the specified index under the given parent (can be another bullet). Requires 'content' and 'checkbox' parameters.\n" +
 		"- 'remove': Removes the bullet point identified by its uid.\n" +
 		"- 'update': Updates the content of the bullet point. Requires 'content' parameter.\n\n" +
-		"When targeting a bullet, the uid is constructed as `parent_uid + '.b' + bullet_index`.\n" +
-		"The 'bullet_index' specifies the position of the bullet point under the header (0-based index).\n\n" +
-		"Bullets are hierarchical meaning that bullets can have sub-bullets. Sub-bullets will use parent_bullet_uid + '.b' + bullet_sub_index like parent_uid.b0.b1\n",
+		"The uid of a bullet is the parent uid followed by '.b' and a hash of its content, like parent_uid.b3f9a0c.\n" +
+		"Adding or removing other bullets never changes it, so uids stay valid within a batch and across calls.\n" +
+		"Updating the content gives the bullet a new uid which is returned in the output, toggling the checkbox keeps it.\n" +
+		"A second bullet with the same content under the same parent gets a '-2' suffix.\n\n" +
+		"Bullets are hierarchical meaning that bullets can have sub-bullets. Sub-bullets extend the uid of their parent bullet like parent_uid.b3f9a0c.b1d2e3f\n",
 	Callback: bulletFunc,
 }
 
@@ -185,25 +224,19 @@ func bulletFunc(ctx context.Context, input BulletInput, options mcp.FuncOptions)
 		path = input.Path
 	}
 
-	orgFile, err := mcp.LoadOrgFile(ctx, path)
+	orgFile, err := options.Files.Load(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error loading org file: %v", err)
 	}
 
+	resp = append(resp, fileWarnings(&orgFile)...)
+
 	affectedCount := 0
 	affectedItems := map[orgmcp.Uid]orgmcp.Render{}
 
 	for _, mt := range input.Bullets {
-		var res ApplyResult
-
-		switch mt.Value.Tag() {
-		case "add":
-			res = mt.Value.Add.Apply(ctx, &orgFile)
-		case "update":
-			res = mt.Value.Update.Apply(ctx, &orgFile)
-		case "remove":
-			res = mt.Value.Remove.Apply(ctx, &orgFile)
-		}
+		res := mt.Value.Apply(ctx, &orgFile)
+		orgFile.Reindex()
 
 		if res.err != nil {
 			resp = append(resp, res.err)
@@ -229,7 +262,7 @@ func bulletFunc(ctx context.Context, input BulletInput, options mcp.FuncOptions)
 		})
 	}
 
-	diff, err := mcp.WriteOrgFileToDisk(ctx, orgFile, path)
+	diff, err := writeJournaled(ctx, options, "manage_bullet", orgFile, path)
 	if input.ShowDiff {
 		resp = append(resp, diff)
 	}
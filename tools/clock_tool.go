@@ -0,0 +1,231 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/itertools"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+type ClockInputSchema struct {
+	Clocks       []mcp.OneOf[*ClockInputUnion] `json:"clocks" jsonschema:"description=The list of clock operations to perform"`
+	Path         string                        `json:"path,omitempty" jsonschema:"description=The path to the Org file to modify; if not provided it will default to the current workspace file,required=false"`
+	ShowDiff     bool                          `json:"show_diff,omitempty" jsonschema:"description=Whether to show a diff of the changes made; default is false,default=false"`
+	ShowAffected *bool                         `json:"show_affected,omitempty" jsonschema:"description=Whether to include the affected items in the response. This will include all items that were modified as well as their children.,default=true,required=false"`
+	Columns      []*orgmcp.Column              `json:"columns,omitempty" jsonschema:"description=List of columns to include in the output. If not specified defaults to [UID ; PREVIEW ; CLOCKED]."`
+}
+
+type ClockInputUnion struct {
+	tag string
+
+	ClockIn  ClockInputIn
+	ClockOut ClockInputOut
+}
+
+func NewClockInputUnion[T ClockInputIn | ClockInputOut](input T) *ClockInputUnion {
+	switch any(input).(type) {
+	case ClockInputIn:
+		return &ClockInputUnion{
+			tag:     "clock_in",
+			ClockIn: any(input).(ClockInputIn),
+		}
+	case ClockInputOut:
+		return &ClockInputUnion{
+			tag:      "clock_out",
+			ClockOut: any(input).(ClockInputOut),
+		}
+	default:
+		panic(fmt.Sprintf("unsupported type for ClockInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
+func (c *ClockInputUnion) Value() any {
+	switch c.tag {
+	case "clock_in":
+		return c.ClockIn
+	case "clock_out":
+		return c.ClockOut
+	default:
+		return nil
+	}
+}
+
+func (c *ClockInputUnion) Tag() string {
+	return c.tag
+}
+
+func (c *ClockInputUnion) FromJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw["method"] {
+	case "clock_in":
+		c.tag = "clock_in"
+		return json.Unmarshal(data, &c.ClockIn)
+	case "clock_out":
+		c.tag = "clock_out"
+		return json.Unmarshal(data, &c.ClockOut)
+	default:
+		return fmt.Errorf("invalid method: %s", raw["method"])
+	}
+}
+
+type ClockInputIn struct {
+	Method string `json:"method" jsonschema:"description=Start the clock on a header. A clock running on another header is stopped first.,enum=clock_in"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the header to clock in on."`
+}
+
+func (c *ClockInputIn) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+	header, ok := option.Cast[orgmcp.Render, *orgmcp.Header](of.GetUid(orgmcp.NewUid(c.Uid))).Split()
+
+	if !ok {
+		res.err = fmt.Errorf("Uid %s is not a header in %s.", c.Uid, of.Name())
+		return
+	}
+
+	now := orgmcp.Now()
+
+	if running, ok := of.RunningClock().Split(); ok {
+		if running.Uid() == header.Uid() {
+			res.err = fmt.Errorf("The clock is already running on %s.", c.Uid)
+			return
+		}
+
+		if _, err := running.ClockOut(now); err != nil {
+			res.err = err
+			return
+		}
+
+		res.affectedItems[running.Uid()] = running
+	}
+
+	if err := header.ClockIn(now); err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[header.Uid()] = header
+
+	return
+}
+
+type ClockInputOut struct {
+	Method string `json:"method" jsonschema:"description=Stop the running clock.,enum=clock_out"`
+	Uid    string `json:"uid,omitempty" jsonschema:"description=The UID of the header to clock out of. If omitted the running clock of the file is stopped.,required=false"`
+}
+
+func (c *ClockInputOut) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	var header *orgmcp.Header
+	var ok bool
+
+	if c.Uid == "" {
+		if header, ok = of.RunningClock().Split(); !ok {
+			res.err = fmt.Errorf("No clock is running in %s.", of.Name())
+			return
+		}
+	} else if header, ok = option.Cast[orgmcp.Render, *orgmcp.Header](of.GetUid(orgmcp.NewUid(c.Uid))).Split(); !ok {
+		res.err = fmt.Errorf("Uid %s is not a header in %s.", c.Uid, of.Name())
+		return
+	}
+
+	if _, err := header.ClockOut(orgmcp.Now()); err != nil {
+		res.err = fmt.Errorf("Cannot clock out of %s: %s", header.Uid(), err)
+		return
+	}
+
+	res.affectedItems[header.Uid()] = header
+
+	return
+}
+
+var ClockTool = mcp.GenericTool[ClockInputSchema]{
+	Name: "manage_clock",
+	Description: `
+Track the time spent on headers with the org mode clock.
+Clock entries are written to the :LOGBOOK: drawer of the header in the same format Emacs uses, e.g.
+CLOCK: [2026-01-05 Mon 09:00]--[2026-01-05 Mon 10:23] =>  1:23
+Only one clock runs at a time, clocking in on a header stops the clock running on any other header.
+Use the CLOCKED column to see the total clocked time of a header and its subheaders.
+
+## Methods
+` +
+		"`clock_in`: Starts the clock on the header identified by its uid.\n" +
+		"`clock_out`: Stops the running clock, optionally on the header identified by its uid.\n",
+	Callback: func(ctx context.Context, input ClockInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		if len(input.Columns) == 0 {
+			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue, &orgmcp.ColClockedValue}
+		}
+
+		affectedCount := 0
+		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
+
+		for _, mt := range input.Clocks {
+			var res ApplyResult
+
+			switch mt.Value.Tag() {
+			case "clock_in":
+				res = mt.Value.ClockIn.Apply(ctx, &orgFile)
+			case "clock_out":
+				res = mt.Value.ClockOut.Apply(ctx, &orgFile)
+			}
+
+			orgFile.Reindex()
+
+			if res.err != nil {
+				resp = append(resp, res.err.Error())
+			}
+
+			maps.Copy(affectedItems, res.affectedItems)
+			affectedCount += len(res.affectedItems)
+		}
+
+		ordered := []orgmcp.Render{}
+
+		if input.ShowAffected == nil || *input.ShowAffected == true {
+			locationTable := orgFile.BuildLocationTable()
+			ordered = append(ordered, itertools.Collect(maps.Values(affectedItems))...)
+
+			slices.SortFunc(ordered, func(a, b orgmcp.Render) int {
+				return (*locationTable)[a.Uid()] - (*locationTable)[b.Uid()]
+			})
+
+			resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
+			resp = append(resp, map[string]any{
+				"affected_count": affectedCount,
+			})
+		}
+
+		diff, err := options.Files.Write(ctx, orgFile, path)
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
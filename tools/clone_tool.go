@@ -0,0 +1,322 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"slices"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/itertools"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+type CloneInputSchema struct {
+	Clones       []mcp.OneOf[*CloneInputUnion] `json:"clones" jsonschema:"description=The list of clone operations to perform; they are applied in order."`
+	Path         string                        `json:"path,omitempty" jsonschema:"description=The path to the Org file to modify; if not provided it will default to the current workspace file,required=false"`
+	ShowDiff     bool                          `json:"show_diff,omitempty" jsonschema:"description=Whether to show a diff of the changes made; default is false,default=false"`
+	ShowAffected *bool                         `json:"show_affected,omitempty" jsonschema:"description=Whether to include the new headers in the response with their uid.,default=true,required=false"`
+	Columns      []*orgmcp.Column              `json:"columns,omitempty" jsonschema:"description=List of columns to include in the output. If not specified defaults to [UID ; PARENT ; PREVIEW]."`
+}
+
+type CloneInputUnion struct {
+	tag string
+
+	Clone       CloneInputClone
+	Instantiate CloneInputInstantiate
+	List        CloneInputList
+}
+
+func NewCloneInputUnion[T CloneInputClone | CloneInputInstantiate | CloneInputList](input T) *CloneInputUnion {
+	switch v := any(input).(type) {
+	case CloneInputClone:
+		return &CloneInputUnion{tag: "clone", Clone: v}
+	case CloneInputInstantiate:
+		return &CloneInputUnion{tag: "instantiate", Instantiate: v}
+	case CloneInputList:
+		return &CloneInputUnion{tag: "list_templates", List: v}
+	default:
+		panic(fmt.Sprintf("unsupported type for CloneInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
+func (c *CloneInputUnion) Value() any {
+	switch c.tag {
+	case "clone":
+		return c.Clone
+	case "instantiate":
+		return c.Instantiate
+	case "list_templates":
+		return c.List
+	default:
+		return nil
+	}
+}
+
+func (c *CloneInputUnion) Tag() string {
+	return c.tag
+}
+
+func (c *CloneInputUnion) FromJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw["method"] {
+	case "clone":
+		c.tag = "clone"
+		return json.Unmarshal(data, &c.Clone)
+	case "instantiate":
+		c.tag = "instantiate"
+		return json.Unmarshal(data, &c.Instantiate)
+	case "list_templates":
+		c.tag = "list_templates"
+		return json.Unmarshal(data, &c.List)
+	default:
+		return fmt.Errorf("invalid method: %s", raw["method"])
+	}
+}
+
+// cloneOptions converts the options of clone and instantiate, variables are always substituted for a template.
+func cloneOptions(resetCheckboxes bool, resetStatus bool, shift string, variables map[string]string, template bool) (options orgmcp.CloneOptions, err error) {
+	options = orgmcp.CloneOptions{
+		ResetCheckboxes: resetCheckboxes,
+		ResetStatus:     resetStatus,
+		Variables:       variables,
+	}
+
+	if template && options.Variables == nil {
+		options.Variables = map[string]string{}
+	}
+
+	if shift != "" {
+		var interval orgmcp.Interval
+		if interval, err = orgmcp.ParseOffset(shift); err != nil {
+			return
+		}
+
+		options.Shift = option.Some(interval)
+	}
+
+	return
+}
+
+type CloneInputClone struct {
+	Method          string            `json:"method" jsonschema:"description=Copy a header with everything below it; the copy is placed right after the original.,enum=clone"`
+	Uid             string            `json:"uid" jsonschema:"description=The UID of the header to copy."`
+	ResetCheckboxes bool              `json:"reset_checkboxes,omitempty" jsonschema:"description=Uncheck every checkbox of the copy.,required=false"`
+	ResetStatus     bool              `json:"reset_status,omitempty" jsonschema:"description=Set DONE headers of the copy back to the first TODO state and drop their CLOSED date and logbook.,required=false"`
+	Shift           string            `json:"shift,omitempty" jsonschema:"description=Move every SCHEDULED and DEADLINE date of the copy by an offset like +1w; -3d or +1m.,required=false"`
+	Variables       map[string]string `json:"variables,omitempty" jsonschema:"description=Values for the {{name}} placeholders in the copy.,required=false"`
+}
+
+func (c *CloneInputClone) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	options, err := cloneOptions(c.ResetCheckboxes, c.ResetStatus, c.Shift, c.Variables, c.Variables != nil)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	clone, err := of.Clone(orgmcp.NewUid(c.Uid), options)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[clone.Uid()] = clone
+
+	return
+}
+
+type CloneInputInstantiate struct {
+	Method          string            `json:"method" jsonschema:"description=Create a copy of a template with its placeholders filled in.,enum=instantiate"`
+	Template        string            `json:"template" jsonschema:"description=The UID or headline of the template header."`
+	TemplateFile    string            `json:"template_file,omitempty" jsonschema:"description=The Org file with the templates; relative paths are relative to the directory of the file being modified. Defaults to the same file.,required=false"`
+	Parent          string            `json:"parent,omitempty" jsonschema:"description=The UID of the header to add the copy under; defaults to the root (0).,required=false"`
+	Index           *int              `json:"index,omitempty" jsonschema:"description=The 0-based position among the sub headers of the parent; defaults to the end.,required=false"`
+	ResetCheckboxes bool              `json:"reset_checkboxes,omitempty" jsonschema:"description=Uncheck every checkbox of the copy.,required=false"`
+	ResetStatus     bool              `json:"reset_status,omitempty" jsonschema:"description=Set DONE headers of the copy back to the first TODO state and drop their CLOSED date and logbook.,required=false"`
+	Shift           string            `json:"shift,omitempty" jsonschema:"description=Move every SCHEDULED and DEADLINE date of the copy by an offset like +1w; -3d or +1m.,required=false"`
+	Variables       map[string]string `json:"variables,omitempty" jsonschema:"description=Values for the {{name}} placeholders in the copy.,required=false"`
+}
+
+func (c *CloneInputInstantiate) Apply(ctx context.Context, of *orgmcp.OrgFile, path string, cache *mcp.FileCache) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	options, err := cloneOptions(c.ResetCheckboxes, c.ResetStatus, c.Shift, c.Variables, true)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	templates, err := templateFile(ctx, of, path, c.TemplateFile, cache)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	parent := orgmcp.NewUid(0)
+	if c.Parent != "" {
+		parent = orgmcp.NewUid(c.Parent)
+	}
+
+	index := -1
+	if c.Index != nil {
+		index = *c.Index
+	}
+
+	clone, err := templates.Instantiate(c.Template, of, parent, index, options)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[clone.Uid()] = clone
+
+	return
+}
+
+type CloneInputList struct {
+	Method       string `json:"method" jsonschema:"description=List the templates with their placeholders.,enum=list_templates"`
+	TemplateFile string `json:"template_file,omitempty" jsonschema:"description=The Org file with the templates; defaults to the same file.,required=false"`
+}
+
+func (c *CloneInputList) Apply(ctx context.Context, of *orgmcp.OrgFile, path string, cache *mcp.FileCache) (res ApplyResult) {
+	templates, err := templateFile(ctx, of, path, c.TemplateFile, cache)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	list := []map[string]any{}
+	for _, template := range templates.Templates() {
+		list = append(list, map[string]any{
+			"uid":       template.Uid().String(),
+			"headline":  template.Content,
+			"variables": template.Placeholders(),
+		})
+	}
+
+	res.output = append(res.output, map[string]any{"templates": list})
+
+	return
+}
+
+// templateFile returns the file with the templates, it is only read so it is loaded outside of a fileSet.
+func templateFile(ctx context.Context, of *orgmcp.OrgFile, path string, file string, cache *mcp.FileCache) (*orgmcp.OrgFile, error) {
+	if file == "" {
+		return of, nil
+	}
+
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(filepath.Dir(path), file)
+	}
+
+	if filepath.Clean(file) == filepath.Clean(path) {
+		return of, nil
+	}
+
+	templates, err := cache.Load(ctx, file)
+	if err != nil {
+		return nil, err
+	}
+	defer cache.Release(file)
+
+	return &templates, nil
+}
+
+var CloneTool = mcp.GenericTool[CloneInputSchema]{
+	Name: "clone_subtree",
+	Description: `
+Copy a header with its subtree, or create a new subtree from a template, instead of writing a recurring checklist from scratch.
+Every header of a copy gets a new ID, ` + "`id:`" + ` links between headers of the copy point to the new IDs.
+
+## Methods
+` +
+		"`clone`: Copies a header with everything below it, the copy is placed right after the original.\n" +
+		"`instantiate`: Copies a template under a parent, at the end or at the given index.\n" +
+		"`list_templates`: Lists the templates of a file with the names of their placeholders.\n" +
+		`
+## Templates
+A template is a header tagged :template:, either in this file or in a separate file passed as template_file.
+The copy loses the template tag. Placeholders like ` + "`{{version}}`" + ` in headlines, text, bullets, tables, blocks and property values
+are replaced with the variables, every placeholder of a template needs a value.
+
+## Options
+reset_checkboxes unchecks all checkboxes, reset_status sets DONE headers back to TODO and shift moves SCHEDULED and DEADLINE dates, for example by +1w.
+`,
+	Callback: func(ctx context.Context, input CloneInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		if len(input.Columns) == 0 {
+			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColParentValue, &orgmcp.ColPreviewValue}
+		}
+
+		affectedCount := 0
+		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
+
+		for _, ct := range input.Clones {
+			var res ApplyResult
+
+			switch ct.Value.Tag() {
+			case "clone":
+				res = ct.Value.Clone.Apply(ctx, &orgFile)
+			case "instantiate":
+				res = ct.Value.Instantiate.Apply(ctx, &orgFile, path, options.Files)
+			case "list_templates":
+				res = ct.Value.List.Apply(ctx, &orgFile, path, options.Files)
+			}
+
+			orgFile.Reindex()
+
+			if res.err != nil {
+				resp = append(resp, res.err.Error())
+			}
+
+			resp = append(resp, res.output...)
+
+			maps.Copy(affectedItems, res.affectedItems)
+			affectedCount += len(res.affectedItems)
+		}
+
+		if (input.ShowAffected == nil || *input.ShowAffected == true) && affectedCount > 0 {
+			locationTable := orgFile.BuildLocationTable()
+			ordered := itertools.Collect(maps.Values(affectedItems))
+
+			slices.SortFunc(ordered, func(a, b orgmcp.Render) int {
+				return (*locationTable)[a.Uid()] - (*locationTable)[b.Uid()]
+			})
+
+			resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
+			resp = append(resp, map[string]any{
+				"affected_count": affectedCount,
+			})
+		}
+
+		diff, err := options.Files.Write(ctx, orgFile, path)
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
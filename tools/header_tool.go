@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"maps"
 	"slices"
+	"strings"
 
 	"github.com/p3rtang/org-mcp/mcp"
 	"github.com/p3rtang/org-mcp/orgmcp"
@@ -92,12 +93,96 @@ func (u *HeaderInputUnion) FromJSON(data []byte) error {
 	}
 }
 
+// Apply applies the operation of the union to the file.
+func (u *HeaderInputUnion) Apply(ctx context.Context, of *orgmcp.OrgFile) ApplyResult {
+	switch u.tag {
+	case "add":
+		return u.Add.Apply(ctx, of)
+	case "update":
+		return u.Update.Apply(ctx, of)
+	case "remove":
+		return u.Remove.Apply(ctx, of)
+	default:
+		return ApplyResult{err: fmt.Errorf("invalid method: %s", u.tag)}
+	}
+}
+
+// TodoStatus is a status passed in by the client, it is validated against the TODO keywords of the target file.
+type TodoStatus string
+
+// GetSchema lists the default TODO keywords in the description. There is no enum, a file can define
+// its own keywords and Lookup checks the status against the keywords of the file.
+func (s TodoStatus) GetSchema() map[string]any {
+	keywords := []string{}
+	for _, state := range orgmcp.CurrentConfig().TodoKeywords.All() {
+		keywords = append(keywords, string(state))
+	}
+
+	return map[string]any{
+		"type": "string",
+		"description": fmt.Sprintf("The default TODO keywords are %s. A file can define its own keywords with #+TODO: lines, "+
+			"any keyword of the file is accepted.", strings.Join(keywords, ", ")),
+	}
+}
+
+// Lookup resolves the status against the TODO keywords of the file.
+func (s TodoStatus) Lookup(of *orgmcp.OrgFile) (orgmcp.HeaderStatus, error) {
+	keywords := of.TodoKeywords()
+
+	status, ok := keywords.Lookup(string(s))
+	if !ok {
+		return status, fmt.Errorf("Status %s is not a TODO keyword of this file, valid keywords are: %s", s, keywords)
+	}
+
+	return status, nil
+}
+
+// PriorityInput is a priority passed in by the client, e.g. `A` or `[#A]`. NONE clears the priority.
+type PriorityInput string
+
+// Parse returns None for an empty input or NONE.
+func (p PriorityInput) Parse() (option.Option[orgmcp.Priority], error) {
+	if p == "" || strings.EqualFold(string(p), string(orgmcp.None)) {
+		return option.None[orgmcp.Priority](), nil
+	}
+
+	priority := orgmcp.NewPriority(string(p))
+	if priority.IsNone() {
+		return priority, fmt.Errorf("Invalid priority %s, use a single letter like A; B or C or NONE to clear it.", p)
+	}
+
+	return priority, nil
+}
+
+// DateInput is a SCHEDULED or DEADLINE date passed in by the client, see orgmcp.ParseDate. NONE clears the date.
+type DateInput string
+
+// Parse resolves the date against the configured now, None means the date should be cleared.
+func (d DateInput) Parse() (option.Option[orgmcp.Timestamp], error) {
+	if strings.EqualFold(strings.TrimSpace(string(d)), "NONE") {
+		return option.None[orgmcp.Timestamp](), nil
+	}
+
+	ts, err := orgmcp.ParseDate(string(d), orgmcp.Now())
+	if err != nil {
+		return option.None[orgmcp.Timestamp](), fmt.Errorf("Invalid date %s, use YYYY-MM-DD optionally followed by HH:MM or HH:MM-HH:MM; a relative date like +3d; tomorrow; next monday or end of month; or NONE to clear it.", d)
+	}
+
+	return option.Some(ts), nil
+}
+
+const dateInputDescription = "Accepts YYYY-MM-DD; YYYY-MM-DD HH:MM; YYYY-MM-DD HH:MM-HH:MM; org timestamps with repeaters like <2026-10-20 Tue +1w>; and relative dates like +3d; -1w; today; tomorrow; monday; next monday; next week; end of week; end of month; optionally followed by a time like 'tomorrow 14:00'."
+
 type HeaderInputAdd struct {
-	Method  string   `json:"method" jsonschema:"description=Add a new header.,enum=add"`
-	Parent  string   `json:"parent" jsonschema:"description=UID of the parent header under which to add the new header."`
-	Content string   `json:"content" jsonschema:"description=The content of the new header."`
-	Status  string   `json:"status,omitempty" jsonschema:"description=The status of the new header (e.g. TODO; DONE). Use 'NONE' or omit the field to leave status empty.,enum=TODO;NEXT;PROG;REVW;DONE;DELG;NONE"`
-	Tags    []string `json:"tags,omitempty" jsonschema:"description=List of tags to set for the new header. An empty list or omitting this field will leave tags empty."`
+	Method     string            `json:"method" jsonschema:"description=Add a new header.,enum=add"`
+	Parent     string            `json:"parent" jsonschema:"description=UID of the parent header under which to add the new header."`
+	Content    string            `json:"content" jsonschema:"description=The content of the new header."`
+	Status     TodoStatus        `json:"status,omitempty" jsonschema:"description=The status of the new header (e.g. TODO; DONE). Use 'NONE' or omit the field to leave status empty."`
+	Priority   PriorityInput     `json:"priority,omitempty" jsonschema:"description=The priority cookie of the new header (e.g. A; B; C). Omit the field to leave the priority empty."`
+	Tags       []string          `json:"tags,omitempty" jsonschema:"description=List of tags to set for the new header. An empty list or omitting this field will leave tags empty."`
+	Scheduled  DateInput         `json:"scheduled,omitempty" jsonschema:"description=The SCHEDULED date of the new header. Omit the field to leave it unscheduled."`
+	Deadline   DateInput         `json:"deadline,omitempty" jsonschema:"description=The DEADLINE of the new header. Omit the field to leave it without a deadline."`
+	Properties map[string]string `json:"properties,omitempty" jsonschema:"description=Properties to write to the properties drawer of the new header (e.g. {\"OWNER\": \"alice\"}). The ID property is generated and cannot be set."`
 }
 
 func (h HeaderInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
@@ -114,15 +199,49 @@ func (h HeaderInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res Appl
 		return
 	}
 
-	header := orgmcp.NewHeader(
-		orgmcp.HeaderStatus(h.Status),
+	status, err := h.Status.Lookup(of)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	priority, err := h.Priority.Parse()
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	dates, err := parseDates(h.Scheduled, h.Deadline)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	if err := validateProperties(h.Properties, nil); err != nil {
+		res.err = err
+		return
+	}
+
+	header := of.NewHeader(
+		status,
 		h.Content,
 	)
 
+	header.Priority = priority
+
 	if len(h.Tags) != 0 {
 		header.Tags = option.Some(orgmcp.TagList(h.Tags))
 	}
 
+	for status, date := range dates {
+		header.SetSchedule(status, date)
+	}
+
+	// new properties are added in a stable order, a map has none
+	for _, key := range slices.Sorted(maps.Keys(h.Properties)) {
+		header.SetProperty(key, h.Properties[key])
+	}
+
 	parent.AddChildren(&header)
 
 	res.affectedItems[parent.Uid()] = &header
@@ -131,11 +250,16 @@ func (h HeaderInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res Appl
 }
 
 type HeaderInputUpdate struct {
-	Method  string   `json:"method" jsonschema:"description=Update an existing header.,enum=update"`
-	Uid     string   `json:"uid" jsonschema:"description=UID of the header to update."`
-	Content string   `json:"content,omitempty" jsonschema:"description=The new content of the header. Omit this field to keep the content unchanged."`
-	Status  string   `json:"status,omitempty" jsonschema:"description=The new status of the header (e.g. TODO; DONE). Use 'NONE' to clear status. An empty string or omitting this field will leave status unchanged.,enum=TODO;NEXT;PROG;REVW;DONE;DELG;NONE"`
-	Tags    []string `json:"tags,omitempty" jsonschema:"description=List of tags to set for the header. Both an empty list and omitting this field will leave tags unchanged."`
+	Method          string            `json:"method" jsonschema:"description=Update an existing header.,enum=update"`
+	Uid             string            `json:"uid" jsonschema:"description=UID of the header to update."`
+	Content         string            `json:"content,omitempty" jsonschema:"description=The new content of the header. Omit this field to keep the content unchanged."`
+	Status          TodoStatus        `json:"status,omitempty" jsonschema:"description=The new status of the header (e.g. TODO; DONE). Use 'NONE' to clear status. An empty string or omitting this field will leave status unchanged. Completing a task with a repeating SCHEDULED or DEADLINE date moves the date to its next occurrence and resets the status instead."`
+	Priority        PriorityInput     `json:"priority,omitempty" jsonschema:"description=The new priority cookie of the header (e.g. A; B; C). Use 'NONE' to clear the priority. An empty string or omitting this field will leave the priority unchanged."`
+	Tags            []string          `json:"tags,omitempty" jsonschema:"description=List of tags to set for the header. Both an empty list and omitting this field will leave tags unchanged."`
+	Scheduled       DateInput         `json:"scheduled,omitempty" jsonschema:"description=The new SCHEDULED date of the header. Use 'NONE' to clear it. Omitting this field will leave it unchanged. A repeater of the current date is kept."`
+	Deadline        DateInput         `json:"deadline,omitempty" jsonschema:"description=The new DEADLINE of the header. Use 'NONE' to clear it. Omitting this field will leave it unchanged. A repeater of the current date is kept."`
+	Properties      map[string]string `json:"properties,omitempty" jsonschema:"description=Properties to set on the header (e.g. {\"EFFORT\": \"2:00\"}). Existing values are overwritten and properties that are not listed are left unchanged. The ID property cannot be changed."`
+	UnsetProperties []string          `json:"unset_properties,omitempty" jsonschema:"description=Names of properties to remove from the header. The ID property cannot be removed."`
 }
 
 func (h HeaderInputUpdate) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
@@ -147,23 +271,103 @@ func (h HeaderInputUpdate) Apply(ctx context.Context, of *orgmcp.OrgFile) (res A
 		return
 	}
 
-	if h.Content != "" {
-		header.SetContent(h.Content)
+	priority, err := h.Priority.Parse()
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	dates, err := parseDates(h.Scheduled, h.Deadline)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	if err := validateProperties(h.Properties, h.UnsetProperties); err != nil {
+		res.err = err
+		return
+	}
+
+	// set the dates first, so completing a repeating task repeats from the new date
+	for status, date := range dates {
+		header.SetSchedule(status, date)
 	}
 
 	if h.Status != "" {
-		header.SetStatus(orgmcp.HeaderStatus(h.Status))
+		status, err := h.Status.Lookup(of)
+		if err != nil {
+			res.err = err
+			return
+		}
+
+		header.SetStatus(status)
+	}
+
+	if h.Priority != "" {
+		header.Priority = priority
+	}
+
+	if h.Content != "" {
+		header.SetContent(h.Content)
 	}
 
 	if len(h.Tags) != 0 {
 		header.Tags = option.Some(orgmcp.TagList(h.Tags))
 	}
 
+	for _, key := range h.UnsetProperties {
+		header.RemoveProperty(key)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(h.Properties)) {
+		header.SetProperty(key, h.Properties[key])
+	}
+
 	res.affectedItems[header.Uid()] = header
 
 	return
 }
 
+// parseDates parses the dates that were passed in, omitted dates are not included.
+func parseDates(scheduled DateInput, deadline DateInput) (dates map[orgmcp.ScheduleStatus]option.Option[orgmcp.Timestamp], err error) {
+	dates = map[orgmcp.ScheduleStatus]option.Option[orgmcp.Timestamp]{}
+
+	for status, input := range map[orgmcp.ScheduleStatus]DateInput{orgmcp.Scheduled: scheduled, orgmcp.Deadline: deadline} {
+		if input == "" {
+			continue
+		}
+
+		if dates[status], err = input.Parse(); err != nil {
+			return
+		}
+	}
+
+	return
+}
+
+// validateProperties checks the properties that are set or unset, the ID property is reserved for the UID.
+func validateProperties(set map[string]string, unset []string) error {
+	for _, key := range slices.Sorted(maps.Keys(set)) {
+		if key == "ID" {
+			return errors.New("The ID property is the UID of the header and cannot be changed.")
+		}
+
+		if slices.Contains(unset, key) {
+			return fmt.Errorf("Property %s cannot be both set and unset.", key)
+		}
+
+		if err := orgmcp.ValidateProperty(key, set[key]); err != nil {
+			return fmt.Errorf("Cannot set property %s: %s", key, err)
+		}
+	}
+
+	if slices.Contains(unset, "ID") {
+		return errors.New("The ID property is the UID of the header and cannot be removed.")
+	}
+
+	return nil
+}
+
 type HeaderInputRemove struct {
 	Method string `json:"method" jsonschema:"description=Remove an existing header.,enum=remove"`
 	Uid    string `json:"uid" jsonschema:"description=UID of the header to remove."`
@@ -202,7 +406,13 @@ var HeaderTool = mcp.GenericTool[HeaderInput]{
 		"For any method you can use a depth parameter to specify how many levels of children to return.\n" +
 		"- 'add': Adds a new header at the specified index under the given paren (pass this in the parent field of the function). Requires 'content' parameter.\n" +
 		"- 'remove': Removes the header identified by its uid.\n" +
-		"- 'update': Updates the header's content; status; or tags. Requires 'content'; 'status'; or 'tags' parameters.\n\n" +
+		"- 'update': Updates the header's content; status; priority; tags; dates or properties. Requires 'content'; 'status'; 'priority'; 'tags'; 'scheduled'; 'deadline'; 'properties' or 'unset_properties' parameters.\n\n" +
+		"## Dates\n" +
+		"The scheduled and deadline parameters set the SCHEDULED and DEADLINE dates, 'NONE' clears a date. " + dateInputDescription + "\n" +
+		"Relative dates are resolved against the current date of the server.\n\n" +
+		"## Properties\n" +
+		"The properties parameter sets entries of the :PROPERTIES: drawer; like owners; estimates or links; and unset_properties removes them. " +
+		"Use the PROPERTIES column to read the drawer as JSON. The ID property holds the UID and cannot be changed.\n\n" +
 		"It is recommended to pass uid's as string to the function. While they will almost certainly be numbers; this is not guaranteed.",
 	Callback: func(ctx context.Context, input HeaderInput, options mcp.FuncOptions) (resp []any, err error) {
 		var path string
@@ -212,25 +422,19 @@ var HeaderTool = mcp.GenericTool[HeaderInput]{
 			path = input.Path
 		}
 
-		orgFile, err := mcp.LoadOrgFile(ctx, path)
+		orgFile, err := options.Files.Load(ctx, path)
 		if err != nil {
 			return
 		}
 
+		resp = append(resp, fileWarnings(&orgFile)...)
+
 		affectedCount := 0
 		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
 
 		for _, mt := range input.Headers {
-			var res ApplyResult
-
-			switch mt.Value.Tag() {
-			case "add":
-				res = mt.Value.Add.Apply(ctx, &orgFile)
-			case "update":
-				res = mt.Value.Update.Apply(ctx, &orgFile)
-			case "remove":
-				res = mt.Value.Remove.Apply(ctx, &orgFile)
-			}
+			res := mt.Value.Apply(ctx, &orgFile)
+			orgFile.Reindex()
 
 			if res.err != nil {
 				resp = append(resp, res.err.Error())
@@ -256,7 +460,7 @@ var HeaderTool = mcp.GenericTool[HeaderInput]{
 			})
 		}
 
-		diff, err := mcp.WriteOrgFileToDisk(ctx, orgFile, path)
+		diff, err := writeJournaled(ctx, options, "manage_header", orgFile, path)
 		if input.ShowDiff {
 			resp = append(resp, diff)
 		}
@@ -0,0 +1,493 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/itertools"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+type MoveInputSchema struct {
+	Moves        []mcp.OneOf[*MoveInputUnion] `json:"moves" jsonschema:"description=The list of moves to perform; they are applied in order."`
+	Path         string                       `json:"path,omitempty" jsonschema:"description=The path to the Org file to modify; if not provided it will default to the current workspace file,required=false"`
+	ShowDiff     bool                         `json:"show_diff,omitempty" jsonschema:"description=Whether to show a diff of the changes made; default is false,default=false"`
+	ShowAffected *bool                        `json:"show_affected,omitempty" jsonschema:"description=Whether to include the moved items in the response with their new uid.,default=true,required=false"`
+	Columns      []*orgmcp.Column             `json:"columns,omitempty" jsonschema:"description=List of columns to include in the output. If not specified defaults to [UID ; PARENT ; PREVIEW]."`
+}
+
+type MoveInputUnion struct {
+	tag string
+
+	Reorder MoveInputReorder
+	Swap    MoveInputSwap
+	Refile  MoveInputRefile
+	Promote MoveInputPromote
+	Demote  MoveInputDemote
+	Archive MoveInputArchive
+	Sort    MoveInputSort
+}
+
+func NewMoveInputUnion[T MoveInputReorder | MoveInputSwap | MoveInputRefile | MoveInputPromote | MoveInputDemote | MoveInputArchive | MoveInputSort](input T) *MoveInputUnion {
+	switch v := any(input).(type) {
+	case MoveInputReorder:
+		return &MoveInputUnion{tag: "reorder", Reorder: v}
+	case MoveInputSwap:
+		return &MoveInputUnion{tag: "swap", Swap: v}
+	case MoveInputRefile:
+		return &MoveInputUnion{tag: "refile", Refile: v}
+	case MoveInputPromote:
+		return &MoveInputUnion{tag: "promote", Promote: v}
+	case MoveInputDemote:
+		return &MoveInputUnion{tag: "demote", Demote: v}
+	case MoveInputArchive:
+		return &MoveInputUnion{tag: "archive", Archive: v}
+	case MoveInputSort:
+		return &MoveInputUnion{tag: "sort", Sort: v}
+	default:
+		panic(fmt.Sprintf("unsupported type for MoveInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
+func (m *MoveInputUnion) Value() any {
+	switch m.tag {
+	case "reorder":
+		return m.Reorder
+	case "swap":
+		return m.Swap
+	case "refile":
+		return m.Refile
+	case "promote":
+		return m.Promote
+	case "demote":
+		return m.Demote
+	case "archive":
+		return m.Archive
+	case "sort":
+		return m.Sort
+	default:
+		return nil
+	}
+}
+
+func (m *MoveInputUnion) Tag() string {
+	return m.tag
+}
+
+func (m *MoveInputUnion) FromJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw["method"] {
+	case "reorder":
+		m.tag = "reorder"
+		return json.Unmarshal(data, &m.Reorder)
+	case "swap":
+		m.tag = "swap"
+		return json.Unmarshal(data, &m.Swap)
+	case "refile":
+		m.tag = "refile"
+		return json.Unmarshal(data, &m.Refile)
+	case "promote":
+		m.tag = "promote"
+		return json.Unmarshal(data, &m.Promote)
+	case "demote":
+		m.tag = "demote"
+		return json.Unmarshal(data, &m.Demote)
+	case "archive":
+		m.tag = "archive"
+		return json.Unmarshal(data, &m.Archive)
+	case "sort":
+		m.tag = "sort"
+		return json.Unmarshal(data, &m.Sort)
+	default:
+		return fmt.Errorf("invalid method: %s", raw["method"])
+	}
+}
+
+// Apply applies the operation of the union to the file, refile and archive can change the other files of the set.
+func (m *MoveInputUnion) Apply(ctx context.Context, of *orgmcp.OrgFile, files *fileSet) ApplyResult {
+	switch m.tag {
+	case "reorder":
+		return m.Reorder.Apply(ctx, of)
+	case "swap":
+		return m.Swap.Apply(ctx, of)
+	case "refile":
+		return m.Refile.Apply(ctx, of, files)
+	case "promote":
+		return m.Promote.Apply(ctx, of)
+	case "demote":
+		return m.Demote.Apply(ctx, of)
+	case "archive":
+		return m.Archive.Apply(ctx, of, files)
+	case "sort":
+		return m.Sort.Apply(ctx, of)
+	default:
+		return ApplyResult{err: fmt.Errorf("invalid method: %s", m.tag)}
+	}
+}
+
+// movedItem records the moved item in the result, or the error of the move. The item is added under
+// its uid after the move, which changes with the parent for anything but a header.
+func movedItem(res *ApplyResult, item orgmcp.Render, err error) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[item.Uid()] = item
+}
+
+type MoveInputReorder struct {
+	Method string `json:"method" jsonschema:"description=Move an item to another position among its siblings.,enum=reorder"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the header or body item to move."`
+	Index  *int   `json:"index,omitempty" jsonschema:"description=The new 0-based position among the siblings of the same kind; -1 moves it to the end. Pass either index or offset."`
+	Offset *int   `json:"offset,omitempty" jsonschema:"description=The number of positions to move the item; negative moves it up. Pass either index or offset."`
+}
+
+func (m *MoveInputReorder) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	item, ok := of.GetUid(orgmcp.NewUid(m.Uid)).Split()
+	if !ok {
+		movedItem(&res, nil, fmt.Errorf("Uid %s not found.", m.Uid))
+		return
+	}
+
+	var err error
+
+	switch {
+	case m.Index != nil && m.Offset == nil:
+		err = of.Reorder(item.Uid(), *m.Index)
+	case m.Offset != nil && m.Index == nil:
+		err = of.ReorderBy(item.Uid(), *m.Offset)
+	default:
+		err = fmt.Errorf("Pass either index or offset to reorder %s.", m.Uid)
+	}
+
+	movedItem(&res, item, err)
+
+	return
+}
+
+type MoveInputSwap struct {
+	Method string `json:"method" jsonschema:"description=Swap the positions of two siblings.,enum=swap"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the first item."`
+	Other  string `json:"other" jsonschema:"description=The UID of the sibling to swap with; both must be headers or both body items."`
+}
+
+func (m *MoveInputSwap) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	left, leftOk := of.GetUid(orgmcp.NewUid(m.Uid)).Split()
+	right, rightOk := of.GetUid(orgmcp.NewUid(m.Other)).Split()
+
+	if err := of.Swap(orgmcp.NewUid(m.Uid), orgmcp.NewUid(m.Other)); err != nil {
+		res.err = err
+		return
+	}
+
+	if leftOk && rightOk {
+		res.affectedItems[left.Uid()] = left
+		res.affectedItems[right.Uid()] = right
+	}
+
+	return
+}
+
+type MoveInputRefile struct {
+	Method string `json:"method" jsonschema:"description=Move an item with everything below it under a new parent.,enum=refile"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the header or body item to move."`
+	Parent string `json:"parent" jsonschema:"description=The UID of the new parent. Headers can be moved under a header or the root (0); bullets and text also under a bullet."`
+	Index  *int   `json:"index,omitempty" jsonschema:"description=The 0-based position among the children of the same kind of the new parent; defaults to the end.,required=false"`
+	File   string `json:"file,omitempty" jsonschema:"description=The Org file to move the item to; parent is a UID in that file. Relative paths are relative to the directory of the file being modified. Defaults to the same file.,required=false"`
+}
+
+func (m *MoveInputRefile) Apply(ctx context.Context, of *orgmcp.OrgFile, files *fileSet) (res ApplyResult) {
+	item, ok := of.GetUid(orgmcp.NewUid(m.Uid)).Split()
+	if !ok {
+		movedItem(&res, nil, fmt.Errorf("Uid %s not found.", m.Uid))
+		return
+	}
+
+	index := -1
+	if m.Index != nil {
+		index = *m.Index
+	}
+
+	target := of
+	if m.File != "" {
+		var err error
+		if target, err = files.get(ctx, m.File, false); err != nil {
+			movedItem(&res, nil, err)
+			return
+		}
+	}
+
+	movedItem(&res, item, of.MoveToFile(item.Uid(), target, orgmcp.NewUid(m.Parent), index))
+
+	return
+}
+
+type MoveInputPromote struct {
+	Method string `json:"method" jsonschema:"description=Move a header one level up; it becomes the next sibling of its parent.,enum=promote"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the header to promote."`
+}
+
+func (m *MoveInputPromote) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	item, ok := of.GetUid(orgmcp.NewUid(m.Uid)).Split()
+	if !ok {
+		movedItem(&res, nil, fmt.Errorf("Uid %s not found.", m.Uid))
+		return
+	}
+
+	movedItem(&res, item, of.Promote(item.Uid()))
+
+	return
+}
+
+type MoveInputDemote struct {
+	Method string `json:"method" jsonschema:"description=Move a header one level down; it becomes the last child of the header before it.,enum=demote"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the header to demote."`
+}
+
+func (m *MoveInputDemote) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	item, ok := of.GetUid(orgmcp.NewUid(m.Uid)).Split()
+	if !ok {
+		movedItem(&res, nil, fmt.Errorf("Uid %s not found.", m.Uid))
+		return
+	}
+
+	movedItem(&res, item, of.Demote(item.Uid()))
+
+	return
+}
+
+type MoveInputArchive struct {
+	Method   string `json:"method" jsonschema:"description=Archive a header with its subtree like org-archive-subtree.,enum=archive"`
+	Uid      string `json:"uid,omitempty" jsonschema:"description=The UID of the header to archive. Pass either uid or all_done.,required=false"`
+	AllDone  bool   `json:"all_done,omitempty" jsonschema:"description=Archive every DONE subtree of the file that was not archived before instead of a single header.,required=false"`
+	Location string `json:"location,omitempty" jsonschema:"description=Where to archive to as file::heading like org-archive-location; for example %s_archive:: or ::* Archive. Defaults to the ARCHIVE property or #+ARCHIVE keyword and else %s_archive::.,required=false"`
+}
+
+func (m *MoveInputArchive) Apply(ctx context.Context, of *orgmcp.OrgFile, files *fileSet) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	headers := []*orgmcp.Header{}
+
+	switch {
+	case m.AllDone && m.Uid == "":
+		headers = of.DoneSubtrees()
+	case m.Uid != "" && !m.AllDone:
+		header, ok := option.Cast[orgmcp.Render, *orgmcp.Header](of.GetUid(orgmcp.NewUid(m.Uid))).Split()
+		if !ok {
+			res.err = fmt.Errorf("Uid %s is not a header.", m.Uid)
+			return
+		}
+
+		headers = append(headers, header)
+	default:
+		res.err = errors.New("Pass either uid or all_done to archive.")
+		return
+	}
+
+	for _, header := range headers {
+		location := m.Location
+		if location == "" {
+			location = of.ArchiveLocation(header)
+		}
+
+		file, heading := of.ArchiveTarget(location)
+
+		target := of
+		if file != "" {
+			var err error
+			if target, err = files.get(ctx, file, true); err != nil {
+				res.err = err
+				return
+			}
+		}
+
+		if err := of.Archive(header.Uid(), target, heading); err != nil {
+			res.err = err
+			return
+		}
+
+		res.affectedItems[header.Uid()] = header
+	}
+
+	return
+}
+
+type MoveInputSort struct {
+	Method     string `json:"method" jsonschema:"description=Sort the sub headers of a header like org-sort.,enum=sort"`
+	Uid        string `json:"uid" jsonschema:"description=The UID of the header whose sub headers are sorted; 0 sorts the top level headers."`
+	By         string `json:"by" jsonschema:"description=What to sort by.,enum=alpha;status;priority;scheduled;deadline;closed;property"`
+	Property   string `json:"property,omitempty" jsonschema:"description=The property to sort by when by is property.,required=false"`
+	Descending bool   `json:"descending,omitempty" jsonschema:"description=Sort in descending order; headers without a value still go last.,required=false"`
+	Stable     bool   `json:"stable,omitempty" jsonschema:"description=Keep the current order of headers with the same value instead of ordering them by headline.,required=false"`
+	Recursive  bool   `json:"recursive,omitempty" jsonschema:"description=Sort the sub headers of the sorted headers as well.,required=false"`
+}
+
+func (m *MoveInputSort) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	key, err := orgmcp.ParseSortKey(m.By)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	err = of.Sort(orgmcp.NewUid(m.Uid), orgmcp.SortOptions{
+		Key:        key,
+		Property:   m.Property,
+		Descending: m.Descending,
+		Stable:     m.Stable,
+		Recursive:  m.Recursive,
+	})
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	parent := of.GetUid(orgmcp.NewUid(m.Uid)).Unwrap()
+	for _, child := range parent.Children() {
+		if header, ok := child.(*orgmcp.Header); ok {
+			res.affectedItems[header.Uid()] = header
+		}
+	}
+
+	return
+}
+
+var MoveTool = mcp.GenericTool[MoveInputSchema]{
+	Name: "move_item",
+	Description: `
+Reorder, refile, promote or demote headers and body items like bullets, text, tables and blocks.
+Items are moved with everything below them and keep their IDs, use this instead of removing and adding an item again.
+Levels of headers and the indentation of everything in a moved subtree are fixed automatically.
+
+## Methods
+` +
+		"`reorder`: Moves an item among its siblings, either to an absolute index or by an offset.\n" +
+		"`swap`: Swaps two siblings.\n" +
+		"`refile`: Moves an item under a new parent, at the end or at the given index.\n" +
+		"`promote`: Moves a header one level up, it is placed right after its old parent.\n" +
+		"`demote`: Moves a header one level down, it becomes the last child of the header before it.\n" +
+		"`archive`: Archives a header, or every DONE subtree with `all_done`, like org mode.\n" +
+		"`sort`: Sorts the sub headers of a header by status, priority, a date, a property or headline.\n" +
+		`
+## Positions
+The body of a header always comes before its sub headers, so positions are counted among the siblings of the same kind:
+for a header the index is its position among the sub headers of the parent, for a bullet or text its position in the body.
+
+## Sorting
+` + "`sort`" + ` only moves headers, the body stays above the sub headers. Statuses follow the order of the TODO keywords of the file,
+headers without a priority count as [#B] and headers without the date or property go last, also when sorting in descending order.
+
+## Other files
+` + "`refile`" + ` with a ` + "`file`" + ` moves a subtree to another Org file, both files are written. IDs, properties and schedules are kept,
+an ID that is already used in the other file is an error.
+
+` + "`archive`" + ` moves a subtree to the archive location: the ARCHIVE property of the header or an ancestor, the #+ARCHIVE keyword of the file
+or ` + "`%s_archive::`" + `, a file next to this one with _archive appended. The part after :: is a heading like ` + "`* Archive`" + ` to archive under,
+which is created when it does not exist yet, ` + "`::* Archive`" + ` archives within the same file.
+The archived header gets the ARCHIVE_TIME, ARCHIVE_FILE, ARCHIVE_OLPATH, ARCHIVE_CATEGORY and ARCHIVE_TODO properties.
+
+## UIDs
+Headers keep their uid. Bullets, text and other body items get a new uid because it starts with the uid of their parent,
+the new uid is returned in the output.
+`,
+	Callback: func(ctx context.Context, input MoveInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		files := newFileSet(&orgFile, path, options.Files)
+
+		if len(input.Columns) == 0 {
+			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColParentValue, &orgmcp.ColPreviewValue}
+		}
+
+		affectedCount := 0
+		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
+
+		for _, mt := range input.Moves {
+			res := mt.Value.Apply(ctx, &orgFile, files)
+			orgFile.Reindex()
+
+			if res.err != nil {
+				resp = append(resp, res.err.Error())
+			}
+
+			maps.Copy(affectedItems, res.affectedItems)
+			affectedCount += len(res.affectedItems)
+		}
+
+		ordered := []orgmcp.Render{}
+
+		if input.ShowAffected == nil || *input.ShowAffected == true {
+			locationTable := orgFile.BuildLocationTable()
+
+			// an item moved twice is in the map under both uids
+			for _, item := range itertools.Collect(maps.Values(affectedItems)) {
+				if !slices.Contains(ordered, item) {
+					ordered = append(ordered, item)
+				}
+			}
+
+			slices.SortFunc(ordered, func(a, b orgmcp.Render) int {
+				return (*locationTable)[a.Uid()] - (*locationTable)[b.Uid()]
+			})
+
+			resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
+			resp = append(resp, map[string]any{
+				"affected_count": affectedCount,
+			})
+		}
+
+		// a conflict or lock in any of the files stops the move before the first file is written
+		if err = mcp.CheckWrite(orgFile, path); err == nil {
+			err = files.check()
+		}
+
+		if err != nil {
+			files.invalidate()
+
+			return
+		}
+
+		// the other files are written first, a failed write leaves the moved subtrees in this file
+		diffs, err := files.write(ctx)
+		if err != nil {
+			return
+		}
+
+		diff, err := options.Files.Write(ctx, orgFile, path)
+		if input.ShowDiff {
+			resp = append(resp, diffs...)
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/p3rtang/org-mcp/mcp"
+)
+
+type RepairIDsInputSchema struct {
+	Path     string `json:"path,omitempty" jsonschema:"description=The file path to the Org file to modify. It will target the ./.tasks.org by default and you don't have to pass this in unless you want to target a different file.,required=false"`
+	ShowDiff bool   `json:"show_diff,omitempty" jsonschema:"description=Whether to show the diff of changes made to the Org file.,default=false"`
+}
+
+var RepairIDsTool = mcp.GenericTool[RepairIDsInputSchema]{
+	Name: "repair_ids",
+	Description: `
+Gives headers that share an ID with an earlier header of the file a new ID.
+Duplicate IDs happen when a subtree is copied, tools warn about them because a UID only targets the first header with that ID.
+
+The first header keeps its ID, every later duplicate gets a new one.
+id: links inside a copied subtree that point at a header of that subtree are updated to the new ID,
+links anywhere else keep pointing at the first header.
+
+The response lists the old and new uid of every repaired header and the number of updated links.
+`,
+
+	Callback: func(ctx context.Context, input RepairIDsInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		repairs, links := orgFile.RepairIDs()
+
+		repaired := []map[string]string{}
+		for _, repair := range repairs {
+			repaired = append(repaired, map[string]string{
+				"old_uid": repair.Old.String(),
+				"uid":     repair.New.String(),
+				"preview": repair.Header.Preview(-1),
+			})
+		}
+
+		resp = append(resp, map[string]any{
+			"repaired":      repaired,
+			"updated_links": links,
+		})
+
+		diff, err := options.Files.Write(ctx, orgFile, path)
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
@@ -14,7 +14,7 @@ var StatusTool = mcp.GenericTool[StatusInputSchema]{
 	Name: "status_overview",
 	Description: `
 Provides an overview of task statuses in the Org file.
-It counts the number of tasks in each status category (TODO, NEXT, PROG, REVW, DONE, DELG).
+It counts the number of tasks in each status category, the categories are the TODO keywords of the file (TODO, NEXT, PROG, REVW, DONE, DELG unless configured otherwise).
 You will also receive all the uid's of the headers for each category in a list.
 
 This tool will also return an overview of all tags used in the Org file and the count of how many times each tag is used.
@@ -30,17 +30,19 @@ so a header with the tag "project" and a child header without any tags will stil
 			path = input.Path
 		}
 
-		orgFile, err := mcp.LoadOrgFile(ctx, path)
+		orgFile, err := options.Files.Load(ctx, path)
 		if err != nil {
 			return
 		}
 
-		resp = []any{map[string]any{
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		resp = append(resp, map[string]any{
 			"status_overview": orgFile.GetStatusOverview(),
 			"tag_overview":    orgFile.GetTagOverview(),
-		}}
+		})
 
-		_, err = mcp.WriteOrgFileToDisk(ctx, orgFile, path)
+		_, err = options.Files.Write(ctx, orgFile, path)
 
 		return
 	},
@@ -0,0 +1,361 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/utils/itertools"
+	"github.com/p3rtang/org-mcp/utils/option"
+)
+
+type TableInputSchema struct {
+	Tables       []mcp.OneOf[*TableInputUnion] `json:"tables" jsonschema:"description=The list of table operations to perform"`
+	Path         string                        `json:"path,omitempty" jsonschema:"description=The path to the Org file to modify; if not provided it will default to the current workspace file,required=false"`
+	ShowDiff     bool                          `json:"show_diff,omitempty" jsonschema:"description=Whether to show a diff of the changes made; default is false,default=false"`
+	ShowAffected *bool                         `json:"show_affected,omitempty" jsonschema:"description=Whether to include the affected items in the response. This will include all items that were modified as well as their children.,default=true,required=false"`
+	Columns      []*orgmcp.Column              `json:"columns,omitempty" jsonschema:"description=List of columns to include in the output. If not specified defaults to [UID ; PREVIEW]."`
+}
+
+type TableInputUnion struct {
+	tag string
+
+	Read      TableInputRead
+	Add       TableInputAdd
+	SetCell   TableInputSetCell
+	AppendRow TableInputAppendRow
+	DeleteRow TableInputDeleteRow
+	Remove    TableInputRemove
+}
+
+func NewTableInputUnion[T TableInputRead | TableInputAdd | TableInputSetCell | TableInputAppendRow | TableInputDeleteRow | TableInputRemove](input T) *TableInputUnion {
+	switch v := any(input).(type) {
+	case TableInputRead:
+		return &TableInputUnion{tag: "read", Read: v}
+	case TableInputAdd:
+		return &TableInputUnion{tag: "add", Add: v}
+	case TableInputSetCell:
+		return &TableInputUnion{tag: "set_cell", SetCell: v}
+	case TableInputAppendRow:
+		return &TableInputUnion{tag: "append_row", AppendRow: v}
+	case TableInputDeleteRow:
+		return &TableInputUnion{tag: "delete_row", DeleteRow: v}
+	case TableInputRemove:
+		return &TableInputUnion{tag: "remove", Remove: v}
+	default:
+		panic(fmt.Sprintf("unsupported type for TableInputUnion: %s", reflect.TypeOf(input)))
+	}
+}
+
+func (t *TableInputUnion) Value() any {
+	switch t.tag {
+	case "read":
+		return t.Read
+	case "add":
+		return t.Add
+	case "set_cell":
+		return t.SetCell
+	case "append_row":
+		return t.AppendRow
+	case "delete_row":
+		return t.DeleteRow
+	case "remove":
+		return t.Remove
+	default:
+		return nil
+	}
+}
+
+func (t *TableInputUnion) Tag() string {
+	return t.tag
+}
+
+func (t *TableInputUnion) FromJSON(data []byte) error {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	switch raw["method"] {
+	case "read":
+		t.tag = "read"
+		return json.Unmarshal(data, &t.Read)
+	case "add":
+		t.tag = "add"
+		return json.Unmarshal(data, &t.Add)
+	case "set_cell":
+		t.tag = "set_cell"
+		return json.Unmarshal(data, &t.SetCell)
+	case "append_row":
+		t.tag = "append_row"
+		return json.Unmarshal(data, &t.AppendRow)
+	case "delete_row":
+		t.tag = "delete_row"
+		return json.Unmarshal(data, &t.DeleteRow)
+	case "remove":
+		t.tag = "remove"
+		return json.Unmarshal(data, &t.Remove)
+	default:
+		return fmt.Errorf("invalid method: %s", raw["method"])
+	}
+}
+
+func getTable(of *orgmcp.OrgFile, uid string) (*orgmcp.Table, error) {
+	table, ok := option.Cast[orgmcp.Render, *orgmcp.Table](of.GetUid(orgmcp.NewUid(uid))).Split()
+	if !ok {
+		return nil, fmt.Errorf("Uid %s is not a table in %s.", uid, of.Name())
+	}
+
+	return table, nil
+}
+
+type TableInputRead struct {
+	Method string `json:"method" jsonschema:"description=Read the rows of a table as CSV. Horizontal lines are left out.,enum=read"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the table."`
+}
+
+func (t *TableInputRead) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	table, err := getTable(of, t.Uid)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	res.output = append(res.output, table.Csv())
+
+	return
+}
+
+type TableInputAdd struct {
+	Method string `json:"method" jsonschema:"description=Add a new table under the specified parent element.,enum=add"`
+	Parent string `json:"parent" jsonschema:"description=The UID of the parent element under which the table will be added. This can be either a header or a bullet point."`
+	Csv    string `json:"csv" jsonschema:"description=The content of the table as CSV; one line per row."`
+	Header bool   `json:"header,omitempty" jsonschema:"description=Whether the first row is a header row; a horizontal line is added below it."`
+}
+
+func (t *TableInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+	parent, ok := of.GetUid(orgmcp.NewUid(t.Parent)).Split()
+
+	if !ok {
+		res.err = fmt.Errorf("Parent uid %s not found.", t.Parent)
+		return
+	}
+
+	table, err := orgmcp.NewTableFromCsv(t.Csv, t.Header)
+	if err != nil {
+		res.err = fmt.Errorf("Invalid CSV for table: %w", err)
+		return
+	}
+
+	if err := parent.AddChildren(&table); err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[table.Uid()] = &table
+
+	return
+}
+
+type TableInputSetCell struct {
+	Method string `json:"method" jsonschema:"description=Set the value of a single cell.,enum=set_cell"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the table."`
+	Row    int    `json:"row" jsonschema:"description=The index of the row starting at 0. Horizontal lines are not counted; a header row is row 0."`
+	Column int    `json:"column" jsonschema:"description=The index of the column starting at 0. The row is extended with empty cells when needed."`
+	Value  string `json:"value" jsonschema:"description=The new value of the cell."`
+}
+
+func (t *TableInputSetCell) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	table, err := getTable(of, t.Uid)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	if err := table.SetCell(t.Row, t.Column, t.Value); err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[table.Uid()] = table
+
+	return
+}
+
+type TableInputAppendRow struct {
+	Method string   `json:"method" jsonschema:"description=Append a row to the end of the table.,enum=append_row"`
+	Uid    string   `json:"uid" jsonschema:"description=The UID of the table."`
+	Cells  []string `json:"cells" jsonschema:"description=The values of the cells in the new row."`
+}
+
+func (t *TableInputAppendRow) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	table, err := getTable(of, t.Uid)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	table.AppendRow(t.Cells)
+	res.affectedItems[table.Uid()] = table
+
+	return
+}
+
+type TableInputDeleteRow struct {
+	Method string `json:"method" jsonschema:"description=Delete a row from the table.,enum=delete_row"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the table."`
+	Row    int    `json:"row" jsonschema:"description=The index of the row starting at 0. Horizontal lines are not counted; a header row is row 0."`
+}
+
+func (t *TableInputDeleteRow) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	table, err := getTable(of, t.Uid)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	if err := table.DeleteRow(t.Row); err != nil {
+		res.err = err
+		return
+	}
+
+	res.affectedItems[table.Uid()] = table
+
+	return
+}
+
+type TableInputRemove struct {
+	Method string `json:"method" jsonschema:"description=Remove the whole table.,enum=remove"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the table to remove."`
+}
+
+func (t *TableInputRemove) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	res.affectedItems = make(map[orgmcp.Uid]orgmcp.Render)
+
+	table, err := getTable(of, t.Uid)
+	if err != nil {
+		res.err = err
+		return
+	}
+
+	p_uid := table.ParentUid()
+	if parent, ok := of.GetUid(p_uid).Split(); ok {
+		parent.RemoveChildren(table.Uid())
+		res.affectedItems[p_uid] = parent
+	} else {
+		res.err = fmt.Errorf("Parent with uid %s not found for table with uid %s", p_uid, t.Uid)
+	}
+
+	return
+}
+
+var TableTool = mcp.GenericTool[TableInputSchema]{
+	Name: "manage_table",
+	Description: `
+Read and edit org tables. Tables are aligned automatically when the file is written.
+
+## Methods
+` +
+		"`read`: Returns the rows of the table identified by uid as CSV.\n" +
+		"`add`: Adds a new table from CSV to the specified parent element. Set header to add a horizontal line below the first row.\n" +
+		"`set_cell`: Sets the value of the cell at row and column.\n" +
+		"`append_row`: Appends a row with the given cells to the end of the table.\n" +
+		"`delete_row`: Deletes the row at the given index.\n" +
+		"`remove`: Removes the whole table.\n" +
+		`
+## Rows and columns
+Rows and columns are counted from 0. Horizontal lines are not counted, so in a table with a header row the header is row 0 and the first data row is row 1.
+
+## UID Constructions
+Tables can be added to a header or a bullet point.
+The uid is the parent uid followed by '.tbl' and a hash of its cells, e.g. parent_uid.tbl3f9a0c.
+Editing the table gives it a new uid which is returned in the output, adding or removing other items never changes it.
+A second table with the same cells under the same parent gets a '-2' suffix.
+`,
+	Callback: func(ctx context.Context, input TableInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		orgFile, err := options.Files.Load(ctx, path)
+		if err != nil {
+			return
+		}
+
+		resp = append(resp, fileWarnings(&orgFile)...)
+
+		if len(input.Columns) == 0 {
+			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue}
+		}
+
+		affectedCount := 0
+		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
+
+		for _, mt := range input.Tables {
+			var res ApplyResult
+
+			switch mt.Value.Tag() {
+			case "read":
+				res = mt.Value.Read.Apply(ctx, &orgFile)
+			case "add":
+				res = mt.Value.Add.Apply(ctx, &orgFile)
+			case "set_cell":
+				res = mt.Value.SetCell.Apply(ctx, &orgFile)
+			case "append_row":
+				res = mt.Value.AppendRow.Apply(ctx, &orgFile)
+			case "delete_row":
+				res = mt.Value.DeleteRow.Apply(ctx, &orgFile)
+			case "remove":
+				res = mt.Value.Remove.Apply(ctx, &orgFile)
+			}
+
+			orgFile.Reindex()
+
+			if res.err != nil {
+				resp = append(resp, res.err.Error())
+			}
+
+			resp = append(resp, res.output...)
+			maps.Copy(affectedItems, res.affectedItems)
+			affectedCount += len(res.affectedItems)
+		}
+
+		ordered := []orgmcp.Render{}
+
+		if (input.ShowAffected == nil || *input.ShowAffected == true) && affectedCount > 0 {
+			locationTable := orgFile.BuildLocationTable()
+			ordered = append(ordered, itertools.Collect(maps.Values(affectedItems))...)
+
+			slices.SortFunc(ordered, func(a, b orgmcp.Render) int {
+				return (*locationTable)[a.Uid()] - (*locationTable)[b.Uid()]
+			})
+
+			resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
+			resp = append(resp, map[string]any{
+				"affected_count": affectedCount,
+			})
+		}
+
+		diff, err := options.Files.Write(ctx, orgFile, path)
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
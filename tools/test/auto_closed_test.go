@@ -113,7 +113,12 @@ func TestAutoClosed(t *testing.T) {
 		})
 	}
 
-	// Clean up
+	// Clean up, the tool calls changed the file so it is read again
+	of, err = mcp.LoadOrgFile(context.TODO(), "./test.org")
+	if err != nil {
+		t.Fatalf("failed to load org file: %v", err)
+	}
+
 	err = RemoveTestHeader(&of, doneUid)
 	err = RemoveTestHeader(&of, revwUid)
 	err = RemoveTestHeader(&of, todoUid)
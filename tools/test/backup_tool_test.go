@@ -0,0 +1,72 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func TestRestoreBackupTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.BackupDir = "backups"
+	config.Backups = 5
+	orgmcp.Configure(config)
+
+	path := t.TempDir() + "/backup.org"
+	content := "* First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n* Copy\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	options := mcp.FuncOptions{DefaultPath: path, Files: mcp.NewFileCache()}
+
+	if _, err := tools.RepairIDsTool.Callback(context.TODO(), tools.RepairIDsInputSchema{}, options); err != nil {
+		t.Fatalf("RepairIDsTool failed: %v", err)
+	}
+
+	res, err := tools.RestoreBackupTool.Callback(context.TODO(), tools.RestoreBackupInputSchema{}, options)
+	if err != nil {
+		t.Fatalf("RestoreBackupTool failed: %v", err)
+	}
+
+	var listed struct {
+		Backups []struct {
+			Name string `json:"name"`
+		} `json:"backups"`
+	}
+
+	output, _ := json.Marshal(res[0])
+	if err := json.Unmarshal(output, &listed); err != nil || len(listed.Backups) != 1 {
+		t.Fatalf("expected one backup, got %s", output)
+	}
+
+	res, err = tools.RestoreBackupTool.Callback(context.TODO(), tools.RestoreBackupInputSchema{Backup: listed.Backups[0].Name}, options)
+	if err != nil {
+		t.Fatalf("RestoreBackupTool failed: %v", err)
+	}
+
+	if written, _ := os.ReadFile(path); string(written) != content {
+		t.Errorf("expected the file before the repair, got\n%s", written)
+	}
+
+	// the cache must not hand out the repaired file
+	res, _ = tools.StatusTool.Callback(context.TODO(), tools.StatusInputSchema{}, options)
+	if warning, ok := res[0].(string); !ok || !strings.HasPrefix(warning, "Warning: Duplicate ID 1") {
+		t.Errorf("expected the duplicate ID to be back, got %#v", res)
+	}
+}
@@ -0,0 +1,202 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func batchHeader[T tools.HeaderInputAdd | tools.HeaderInputUpdate | tools.HeaderInputRemove](op T) mcp.OneOf[*tools.BatchInputUnion] {
+	return mcp.NewOneOf(tools.NewBatchInputUnion(tools.BatchInputHeader{Operation: mcp.NewOneOf(tools.NewHeaderInputUnion(op))}))
+}
+
+func batchBullet[T tools.BulletInputAdd | tools.BulletInputUpdate | tools.BulletInputRemove](op T) mcp.OneOf[*tools.BatchInputUnion] {
+	return mcp.NewOneOf(tools.NewBatchInputUnion(tools.BatchInputBullet{Operation: mcp.NewOneOf(tools.NewBulletInputUnion(op))}))
+}
+
+type batchResults struct {
+	Results []struct {
+		Operation int    `json:"operation"`
+		Status    string `json:"status"`
+		Uid       string `json:"uid"`
+		Error     string `json:"error"`
+	} `json:"results"`
+}
+
+func TestBatchTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/batch.org"
+	content := "* TODO First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	options := mcp.FuncOptions{DefaultPath: path, Files: mcp.NewFileCache()}
+
+	call := func(input tools.BatchInputSchema) (batchResults, []any) {
+		t.Helper()
+
+		res, err := tools.BatchTool.Callback(context.TODO(), input, options)
+		if err != nil {
+			t.Fatalf("BatchTool failed: %v", err)
+		}
+
+		var results batchResults
+		output, _ := json.Marshal(res[0])
+		if err := json.Unmarshal(output, &results); err != nil {
+			t.Fatalf("unexpected response %s", output)
+		}
+
+		return results, res
+	}
+
+	expectFile := func(expected string) {
+		t.Helper()
+
+		if written, _ := os.ReadFile(path); string(written) != expected {
+			t.Errorf("unexpected file\nExpected:\n%s\nGot:\n%s", expected, written)
+		}
+	}
+
+	// a failing operation leaves the file as it was and skips the rest
+	results, res := call(tools.BatchInputSchema{Operations: []mcp.OneOf[*tools.BatchInputUnion]{
+		batchHeader(tools.HeaderInputAdd{Method: "add", Parent: "0", Content: "Release"}),
+		batchHeader(tools.HeaderInputUpdate{Method: "update", Uid: "404", Status: "DONE"}),
+		batchBullet(tools.BulletInputAdd{Method: "add", Parent: "$1", Content: "Write notes"}),
+	}})
+
+	if results.Results[0].Status != "ok" || results.Results[1].Status != "failed" || results.Results[2].Status != "skipped" ||
+		!strings.Contains(results.Results[1].Error, "404") {
+		t.Errorf("unexpected results %#v", results)
+	}
+
+	if output, _ := json.Marshal(res); !strings.Contains(string(output), `"committed":false`) {
+		t.Errorf("expected the batch not to be committed, got %s", output)
+	}
+
+	expectFile(content)
+
+	// a dry run applies everything but writes nothing
+	results, _ = call(tools.BatchInputSchema{DryRun: true, Operations: []mcp.OneOf[*tools.BatchInputUnion]{
+		batchHeader(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "DONE"}),
+	}})
+
+	if results.Results[0].Status != "ok" {
+		t.Errorf("unexpected results %#v", results)
+	}
+
+	expectFile(content)
+
+	// later operations use the header added by the first one
+	results, _ = call(tools.BatchInputSchema{Operations: []mcp.OneOf[*tools.BatchInputUnion]{
+		batchHeader(tools.HeaderInputAdd{Method: "add", Parent: "1", Content: "Release"}),
+		batchBullet(tools.BulletInputAdd{Method: "add", Parent: "$1", Content: "Write notes", Checkbox: "Unchecked"}),
+		batchHeader(tools.HeaderInputUpdate{Method: "update", Uid: "$1", Status: "NEXT"}),
+	}})
+
+	uid := results.Results[0].Uid
+	if uid == "" || results.Results[1].Status != "ok" || results.Results[2].Status != "ok" {
+		t.Fatalf("unexpected results %#v", results)
+	}
+
+	expectFile(content + "** NEXT Release\n   :PROPERTIES:\n   :ID: " + uid + "\n   :END:\n   - [ ] Write notes\n")
+
+	// text that looks like a reference is not one
+	results, _ = call(tools.BatchInputSchema{Operations: []mcp.OneOf[*tools.BatchInputUnion]{
+		batchBullet(tools.BulletInputAdd{Method: "add", Parent: uid, Content: "$1"}),
+		batchBullet(tools.BulletInputAdd{Method: "add", Parent: uid, Content: "$100"}),
+	}})
+
+	if results.Results[0].Status != "ok" || results.Results[1].Status != "ok" {
+		t.Fatalf("unexpected results %#v", results)
+	}
+
+	if written, _ := os.ReadFile(path); !strings.HasSuffix(string(written), "   * $1\n   * $100\n") {
+		t.Errorf("expected the text to be kept as is, got\n%s", written)
+	}
+
+	// references are checked before anything is applied
+	_, err := tools.BatchTool.Callback(context.TODO(), tools.BatchInputSchema{Operations: []mcp.OneOf[*tools.BatchInputUnion]{
+		batchHeader(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "DONE"}),
+		batchHeader(tools.HeaderInputUpdate{Method: "update", Uid: "$1", Status: "DONE"}),
+	}}, options)
+	if err == nil || !strings.Contains(err.Error(), "does not add an item") {
+		t.Errorf("expected a reference to an update to be rejected, got %v", err)
+	}
+
+	var input tools.BatchInputSchema
+	data := `{"operations": [{"tool": "manage_bullet", "operation": {"method": "add", "parent": "$2", "content": "Early"}}]}`
+	if err := json.Unmarshal([]byte(data), &input); err != nil {
+		t.Fatalf("failed to parse input: %v", err)
+	}
+
+	if _, err := tools.BatchTool.Callback(context.TODO(), input, options); err == nil || !strings.Contains(err.Error(), "earlier operation") {
+		t.Errorf("expected a reference to a later operation to be rejected, got %v", err)
+	}
+}
+
+// TestBatchToolLocked tests that a batch refiling to another file writes neither file when the main file is locked
+func TestBatchToolLocked(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.RespectLocks = true
+	orgmcp.Configure(config)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "batch.org")
+	target := filepath.Join(dir, "target.org")
+	content := "* TODO First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+	targetContent := "* Projects\n  :PROPERTIES:\n  :ID: 2\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	if err := os.WriteFile(target, []byte(targetContent), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	if err := os.Symlink("sam@laptop.4242:1760000000", filepath.Join(dir, ".#batch.org")); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+
+	options := mcp.FuncOptions{DefaultPath: path, Files: mcp.NewFileCache()}
+
+	refile := tools.BatchInputMove{Operation: mcp.NewOneOf(tools.NewMoveInputUnion(tools.MoveInputRefile{Method: "refile", Uid: "1", Parent: "2", File: "target.org"}))}
+	_, err := tools.BatchTool.Callback(context.TODO(), tools.BatchInputSchema{Operations: []mcp.OneOf[*tools.BatchInputUnion]{
+		mcp.NewOneOf(tools.NewBatchInputUnion(refile)),
+	}}, options)
+
+	var locked *mcp.LockedError
+	if !errors.As(err, &locked) {
+		t.Errorf("expected a locked error, got %v", err)
+	}
+
+	if written, _ := os.ReadFile(target); string(written) != targetContent {
+		t.Errorf("expected the target not to be written, got\n%s", written)
+	}
+
+	if written, _ := os.ReadFile(path); string(written) != content {
+		t.Errorf("expected the file not to be written, got\n%s", written)
+	}
+}
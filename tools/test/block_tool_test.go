@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func IntoBlockOneOfArray[T tools.BlockInputAdd | tools.BlockInputUpdate | tools.BlockInputRemove](t ...T) []mcp.OneOf[*tools.BlockInputUnion] {
+	entries := []mcp.OneOf[*tools.BlockInputUnion]{}
+
+	for _, input := range t {
+		entries = append(entries, mcp.OneOf[*tools.BlockInputUnion]{
+			Value: tools.NewBlockInputUnion(input),
+		})
+	}
+
+	return entries
+}
+
+func TestBlockTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	// Save original test.org content to restore at end
+	originalContent, err := os.ReadFile("./test.org")
+	if err != nil {
+		t.Fatalf("failed to read test.org: %v", err)
+	}
+	defer func() {
+		os.WriteFile("./test.org", originalContent, 0644)
+	}()
+
+	headerUid := "99998888"
+	newContent := "fmt.Println(\"updated\")"
+	newParameters := "go -n"
+	// the UID of a block follows its begin line and content
+	blockUid := headerUid + ".blk4215f6"
+	updatedUid := headerUid + ".blke25146"
+
+	tests := []ManageBlockTest{
+		{
+			name: "AddSourceBlock",
+			input: tools.BlockInputSchema{
+				Blocks: IntoBlockOneOfArray(tools.BlockInputAdd{
+					Method:     "add",
+					Parent:     headerUid,
+					Kind:       "src",
+					Parameters: "go",
+					Content:    "func main() {\n    - not a bullet\n}",
+				}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColContentValue},
+			},
+			expected: []any{"UID,CONTENT\n99998888.blk4215f6,  #+BEGIN_SRC go\\n  func main() {\\n      - not a bullet\\n  }\\n  #+END_SRC\n"},
+		},
+		{
+			name: "UpdateSourceBlock",
+			input: tools.BlockInputSchema{
+				Blocks: IntoBlockOneOfArray(tools.BlockInputUpdate{
+					Method:     "update",
+					Uid:        blockUid,
+					Parameters: &newParameters,
+					Content:    &newContent,
+				}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue},
+			},
+			expected: []any{"UID,PREVIEW\n99998888.blke25146,#+BEGIN_SRC go -n\\nfmt.Println(\"updated\")\n"},
+		},
+		{
+			name: "UpdateNonBlockFails",
+			input: tools.BlockInputSchema{
+				Blocks: IntoBlockOneOfArray(tools.BlockInputUpdate{
+					Method:  "update",
+					Uid:     headerUid,
+					Content: &newContent,
+				}),
+			},
+			expected: []any{"Uid 99998888 is not a block in ./test.org."},
+		},
+		{
+			name: "RemoveSourceBlock",
+			input: tools.BlockInputSchema{
+				Blocks: IntoBlockOneOfArray(tools.BlockInputRemove{
+					Method: "remove",
+					Uid:    updatedUid,
+				}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColChildrenCountValue},
+			},
+			expected: []any{"UID,CHILDREN_COUNT\n99998888,0\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.BlockTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: "./test.org"})
+			if err != nil {
+				t.Errorf("BlockTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				found := false
+				for _, v := range res {
+					if str, ok := v.(string); ok && EqualString(str, expectedStr.(string)) {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+}
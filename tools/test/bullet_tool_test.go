@@ -0,0 +1,105 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func IntoBulletOneOfArray[T tools.BulletInputAdd | tools.BulletInputUpdate | tools.BulletInputRemove](t ...T) []mcp.OneOf[*tools.BulletInputUnion] {
+	entries := []mcp.OneOf[*tools.BulletInputUnion]{}
+
+	for _, input := range t {
+		entries = append(entries, mcp.OneOf[*tools.BulletInputUnion]{
+			Value: tools.NewBulletInputUnion(input),
+		})
+	}
+
+	return entries
+}
+
+// TestBulletUidStability tests that removing a bullet does not change the UID of its siblings,
+// neither later in the same batch nor in the next call.
+func TestBulletUidStability(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/bullets.org"
+	content := "* Header\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  - [ ] First\n  - [ ] Second\n  - [ ] Third\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	tests := []ManageBulletTest{
+		{
+			name: "RemoveThenUpdateInOneBatch",
+			input: tools.BulletInput{
+				Bullets: append(
+					IntoBulletOneOfArray(tools.BulletInputRemove{Method: "remove", Uid: "1.b3d49e1"}),
+					IntoBulletOneOfArray(tools.BulletInputUpdate{Method: "update", Uid: "1.b30b6de", Checkbox: "Checked"})...,
+				),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue},
+			},
+			expected: []any{"UID,PREVIEW\n1,Header\n1.b30b6de,Third\n"},
+		},
+		{
+			name: "UpdateInNextCall",
+			input: tools.BulletInput{
+				Bullets: IntoBulletOneOfArray(tools.BulletInputUpdate{Method: "update", Uid: "1.b86b7fd", Content: "Second changed"}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColStatusValue},
+			},
+			expected: []any{"UID,STATUS\n1.b070da5,UNCHECKED\n"},
+		},
+		{
+			name: "RemovedUidNotFound",
+			input: tools.BulletInput{
+				Bullets: IntoBulletOneOfArray(tools.BulletInputRemove{Method: "remove", Uid: "1.b3d49e1"}),
+			},
+			expected: []any{"Uid 1.b3d49e1 not found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.BulletTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("BulletTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				found := false
+				for _, v := range res {
+					if err, ok := v.(error); ok {
+						v = err.Error()
+					}
+
+					if str, ok := v.(string); ok && EqualString(str, expectedStr.(string)) {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read org file: %v", err)
+	}
+
+	expected := "* Header\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  - [ ] Second changed\n  - [x] Third\n"
+	if string(written) != expected {
+		t.Errorf("expected file %q, got %q", expected, string(written))
+	}
+}
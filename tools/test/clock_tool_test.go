@@ -0,0 +1,113 @@
+package test
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func IntoClockOneOfArray[T tools.ClockInputIn | tools.ClockInputOut](t ...T) []mcp.OneOf[*tools.ClockInputUnion] {
+	entries := []mcp.OneOf[*tools.ClockInputUnion]{}
+
+	for _, input := range t {
+		entries = append(entries, mcp.OneOf[*tools.ClockInputUnion]{
+			Value: tools.NewClockInputUnion(input),
+		})
+	}
+
+	return entries
+}
+
+func TestClockTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/clock.org"
+	content := "* TODO First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n* TODO Second\n  :PROPERTIES:\n  :ID: 2\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	uidColumns := []*orgmcp.Column{&orgmcp.ColUidValue}
+
+	tests := []ManageClockTest{
+		{
+			name: "ClockIn",
+			input: tools.ClockInputSchema{
+				Clocks:  IntoClockOneOfArray(tools.ClockInputIn{Method: "clock_in", Uid: "1"}),
+				Columns: uidColumns,
+			},
+			expected: []any{"UID\n1\n"},
+		},
+		{
+			name: "ClockInTwice",
+			input: tools.ClockInputSchema{
+				Clocks: IntoClockOneOfArray(tools.ClockInputIn{Method: "clock_in", Uid: "1"}),
+			},
+			expected: []any{"The clock is already running on 1."},
+		},
+		{
+			name: "ClockInStopsRunningClock",
+			input: tools.ClockInputSchema{
+				Clocks:  IntoClockOneOfArray(tools.ClockInputIn{Method: "clock_in", Uid: "2"}),
+				Columns: uidColumns,
+			},
+			expected: []any{"UID\n1\n2\n"},
+		},
+		{
+			name: "ClockOutRunning",
+			input: tools.ClockInputSchema{
+				Clocks:  IntoClockOneOfArray(tools.ClockInputOut{Method: "clock_out"}),
+				Columns: uidColumns,
+			},
+			expected: []any{"UID\n2\n"},
+		},
+		{
+			name: "ClockOutWithoutClock",
+			input: tools.ClockInputSchema{
+				Clocks: IntoClockOneOfArray(tools.ClockInputOut{Method: "clock_out", Uid: "1"}),
+			},
+			expected: []any{"Cannot clock out of 1: no clock is running"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.ClockTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("ClockTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				if !slices.ContainsFunc(res, func(v any) bool {
+					str, ok := v.(string)
+					return ok && EqualString(str, expectedStr.(string))
+				}) {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read org file: %v", err)
+	}
+
+	if count := strings.Count(string(written), "] => "); count != 2 {
+		t.Errorf("expected 2 finished clock entries in the file, got %d:\n%s", count, written)
+	}
+
+	if !ContainsString(string(written), "  :LOGBOOK:\n  CLOCK: [") {
+		t.Errorf("expected a logbook drawer in the file, got:\n%s", written)
+	}
+}
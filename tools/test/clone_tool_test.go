@@ -0,0 +1,130 @@
+package test
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func IntoCloneOneOfArray[T tools.CloneInputClone | tools.CloneInputInstantiate | tools.CloneInputList](t ...T) []mcp.OneOf[*tools.CloneInputUnion] {
+	entries := []mcp.OneOf[*tools.CloneInputUnion]{}
+
+	for _, input := range t {
+		entries = append(entries, mcp.OneOf[*tools.CloneInputUnion]{
+			Value: tools.NewCloneInputUnion(input),
+		})
+	}
+
+	return entries
+}
+
+func TestCloneTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	dir := t.TempDir()
+	path := dir + "/tasks.org"
+	content := "* DONE Weekly review\n  SCHEDULED: <2026-10-16 Fri> CLOSED: [2026-10-16 Fri 17:00]\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  - [x] Inbox zero\n"
+	templates := "* Onboard {{name}} :template:\n  :PROPERTIES:\n  :ID: 50\n  :END:\n  - [ ] Laptop for {{name}}\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	if err := os.WriteFile(dir+"/templates.org", []byte(templates), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	columns := []*orgmcp.Column{&orgmcp.ColPreviewValue, &orgmcp.ColStatusValue}
+
+	tests := []ManageCloneTest{
+		{
+			name: "CloneWithReset",
+			input: tools.CloneInputSchema{
+				Clones:  IntoCloneOneOfArray(tools.CloneInputClone{Method: "clone", Uid: "1", ResetCheckboxes: true, ResetStatus: true, Shift: "+1w"}),
+				Columns: columns,
+			},
+			expected: []any{"PREVIEW,STATUS\nWeekly review,TODO\n"},
+		},
+		{
+			name: "InstantiateMissingVariable",
+			input: tools.CloneInputSchema{
+				Clones: IntoCloneOneOfArray(tools.CloneInputInstantiate{Method: "instantiate", Template: "50", TemplateFile: "templates.org"}),
+			},
+			expected: []any{"no value for the template variables name"},
+		},
+		{
+			name: "Instantiate",
+			input: tools.CloneInputSchema{
+				Clones: IntoCloneOneOfArray(tools.CloneInputInstantiate{
+					Method:       "instantiate",
+					Template:     "Onboard {{name}}",
+					TemplateFile: "templates.org",
+					Variables:    map[string]string{"name": "Alex"},
+				}),
+				Columns: columns,
+			},
+			expected: []any{"PREVIEW,STATUS\nOnboard Alex,NONE\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.CloneTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("CloneTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				found := false
+				for _, v := range res {
+					if str, ok := v.(string); ok && EqualString(str, expectedStr.(string)) {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	res, err := tools.CloneTool.Callback(context.TODO(), tools.CloneInputSchema{
+		Clones: IntoCloneOneOfArray(tools.CloneInputList{Method: "list_templates", TemplateFile: "templates.org"}),
+	}, mcp.FuncOptions{DefaultPath: path})
+	if err != nil {
+		t.Fatalf("CloneTool failed: %v", err)
+	}
+
+	expected := []map[string]any{{"uid": "50", "headline": "Onboard {{name}}", "variables": []string{"name"}}}
+	if !slices.ContainsFunc(res, func(v any) bool { return reflect.DeepEqual(v, map[string]any{"templates": expected}) }) {
+		t.Errorf("expected the template with its variables, got %#v", res)
+	}
+
+	written, _ := os.ReadFile(path)
+	for _, expected := range []string{
+		"* TODO Weekly review\n  SCHEDULED: <2026-10-23 Fri>\n",
+		"  - [ ] Inbox zero\n",
+		"* Onboard Alex\n",
+		"  - [ ] Laptop for Alex\n",
+	} {
+		if !strings.Contains(string(written), expected) {
+			t.Errorf("expected the file to contain %q, got %q", expected, string(written))
+		}
+	}
+
+	if unchanged, _ := os.ReadFile(dir + "/templates.org"); string(unchanged) != templates {
+		t.Errorf("expected the template file to be unchanged, got %q", string(unchanged))
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
 	"github.com/p3rtang/org-mcp/tools"
 )
 
@@ -67,3 +70,431 @@ func TestHeaderGetMethod(t *testing.T) {
 		})
 	}
 }
+
+func TestHeaderInvalidStatus(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/todo.org"
+	content := "#+TODO: TODO WAIT | DONE\n* TODO Task\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	tests := []ManageHeaderTest{
+		{
+			name: "UnknownStatus",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "PROG"})},
+				},
+			},
+			expected: []any{"Status PROG is not a TODO keyword of this file, valid keywords are: TODO WAIT | DONE"},
+		},
+		{
+			name: "FileStatus",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "wait"})},
+				},
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColStatusValue},
+			},
+			expected: []any{"UID,STATUS\n1,WAIT\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.HeaderTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("HeaderTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				if !slices.ContainsFunc(res, func(v any) bool {
+					str, ok := v.(string)
+					return ok && EqualString(str, expectedStr.(string))
+				}) {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+}
+
+func TestTodoStatusSchema(t *testing.T) {
+	keywords, _ := orgmcp.ParseTodoKeywords("OPEN | CLOSED")
+	orgmcp.Configure(orgmcp.Config{TodoKeywords: keywords})
+	defer orgmcp.Configure(orgmcp.DefaultConfig())
+
+	schema := tools.TodoStatus("").GetSchema()
+
+	// files can define their own keywords, so the schema does not restrict the values
+	if _, ok := schema["enum"]; ok {
+		t.Errorf("expected no enum, got %v", schema["enum"])
+	}
+
+	if description := schema["description"].(string); !strings.Contains(description, "OPEN, CLOSED") {
+		t.Errorf("expected the default keywords in the description, got %q", description)
+	}
+
+	update := mcp.GenerateSchema(tools.HeaderInputUpdate{})["properties"].(map[string]any)["status"].(map[string]any)
+	if description := update["description"].(string); !strings.HasPrefix(description, "The new status of the header") ||
+		!strings.Contains(description, "OPEN, CLOSED") {
+		t.Errorf("expected the field description followed by the keywords, got %q", description)
+	}
+}
+
+func TestHeaderPriority(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/priority.org"
+	content := "* TODO [#B] Task\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	tests := []ManageHeaderTest{
+		{
+			name: "SetPriority",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Priority: "a"})},
+				},
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPriorityValue, &orgmcp.ColContentValue},
+			},
+			expected: []any{"UID,PRIORITY,CONTENT\n1,A,* TODO [#A] Task\n"},
+		},
+		{
+			name: "InvalidPriority",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "DONE", Priority: "urgent"})},
+				},
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColStatusValue},
+			},
+			expected: []any{"Invalid priority urgent, use a single letter like A; B or C or NONE to clear it."},
+		},
+		{
+			name: "ClearPriority",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Priority: "NONE"})},
+				},
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPriorityValue, &orgmcp.ColContentValue},
+			},
+			expected: []any{"UID,PRIORITY,CONTENT\n1,,* TODO Task\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.HeaderTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("HeaderTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				if !slices.ContainsFunc(res, func(v any) bool {
+					str, ok := v.(string)
+					return ok && EqualString(str, expectedStr.(string))
+				}) {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+}
+
+func TestHeaderRepeat(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/repeat.org"
+	content := "* TODO Weekly chore\n  SCHEDULED: <2026-10-20 Tue +1w>\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	input := tools.HeaderInput{
+		Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+			{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "DONE"})},
+		},
+		Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColStatusValue, &orgmcp.ColScheduledValue, &orgmcp.ColClosedValue},
+	}
+
+	res, err := tools.HeaderTool.Callback(context.TODO(), input, mcp.FuncOptions{DefaultPath: path})
+	if err != nil {
+		t.Fatalf("HeaderTool failed: %v", err)
+	}
+
+	expected := "UID,STATUS,SCHEDULED,CLOSED\n1,TODO,2026-10-27,\n"
+	if !slices.ContainsFunc(res, func(v any) bool {
+		str, ok := v.(string)
+		return ok && EqualString(str, expected)
+	}) {
+		t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expected)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read org file: %v", err)
+	}
+
+	if !ContainsString(string(written), "SCHEDULED: <2026-10-27 Tue +1w>") || !ContainsString(string(written), ":LAST_REPEAT: [") {
+		t.Errorf("expected the repeated date and LAST_REPEAT in the file, got:\n%s", written)
+	}
+}
+
+func TestHeaderSchedule(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	// 2026-10-17 is a saturday
+	config := orgmcp.DefaultConfig()
+	config.Now = func() time.Time { return time.Date(2026, 10, 17, 9, 30, 0, 0, time.Local) }
+	orgmcp.Configure(config)
+	defer orgmcp.Configure(orgmcp.DefaultConfig())
+
+	path := t.TempDir() + "/schedule.org"
+	content := "* TODO Task\n  :PROPERTIES:\n  :ID: 1\n  :END:\n* TODO Weekly chore\n  SCHEDULED: <2026-10-20 Tue +1w>\n  :PROPERTIES:\n  :ID: 2\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	columns := []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColScheduledValue, &orgmcp.ColDeadlineValue}
+
+	tests := []ManageHeaderTest{
+		{
+			name: "SetRelativeDates",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Scheduled: "+3d", Deadline: "next monday 17:00"})},
+				},
+				Columns: columns,
+			},
+			expected: []any{"UID,SCHEDULED,DEADLINE\n1,2026-10-20,2026-10-19 17:00\n"},
+		},
+		{
+			name: "KeepRepeater",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "2", Scheduled: "2026-10-22"})},
+				},
+				Columns: columns,
+			},
+			expected: []any{"UID,SCHEDULED,DEADLINE\n2,2026-10-22,\n"},
+		},
+		{
+			name: "ClearDeadline",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Deadline: "none"})},
+				},
+				Columns: columns,
+			},
+			expected: []any{"UID,SCHEDULED,DEADLINE\n1,2026-10-20,\n"},
+		},
+		{
+			name: "AddScheduled",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputAdd{Method: "add", Parent: "0", Content: "New task", Status: "TODO", Scheduled: "end of month"})},
+				},
+				Columns: []*orgmcp.Column{&orgmcp.ColScheduledValue, &orgmcp.ColPreviewValue},
+			},
+			expected: []any{"SCHEDULED,PREVIEW\n2026-10-31,New task\n"},
+		},
+		{
+			name: "InvalidDate",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "DONE", Scheduled: "someday"})},
+				},
+				Columns: columns,
+			},
+			expected: []any{"Invalid date someday, use YYYY-MM-DD optionally followed by HH:MM or HH:MM-HH:MM; a relative date like +3d; tomorrow; next monday or end of month; or NONE to clear it."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.HeaderTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("HeaderTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				if !slices.ContainsFunc(res, func(v any) bool {
+					str, ok := v.(string)
+					return ok && EqualString(str, expectedStr.(string))
+				}) {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read org file: %v", err)
+	}
+
+	for _, expected := range []string{"* TODO Task\n  SCHEDULED: <2026-10-20 Tue>\n", "SCHEDULED: <2026-10-22 Thu +1w>", "SCHEDULED: <2026-10-31 Sat>"} {
+		if !ContainsString(string(written), expected) {
+			t.Errorf("expected %q in the file, got:\n%s", expected, written)
+		}
+	}
+}
+
+func TestHeaderProperties(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/properties.org"
+	content := "* TODO Task\n  :PROPERTIES:\n  :ID: 1\n  :OWNER: alice\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	columns := []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPropertiesValue}
+
+	tests := []ManageHeaderTest{
+		{
+			name: "SetProperties",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Properties: map[string]string{"ESTIMATE": "3", "OWNER": "bob"}})},
+				},
+				Columns: columns,
+			},
+			expected: []any{"UID,PROPERTIES\n1,\"{\"\"ID\"\":1,\"\"OWNER\"\":\"\"bob\"\",\"\"ESTIMATE\"\":3}\"\n"},
+		},
+		{
+			name: "UnsetProperty",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", UnsetProperties: []string{"OWNER"}, Properties: map[string]string{"CREATED": "[2026-10-17 Sat 09:30]"}})},
+				},
+				Columns: columns,
+			},
+			expected: []any{"UID,PROPERTIES\n1,\"{\"\"ID\"\":1,\"\"ESTIMATE\"\":3,\"\"CREATED\"\":\"\"[2026-10-17 Sat 09:30]\"\"}\"\n"},
+		},
+		{
+			name: "AddWithProperties",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputAdd{Method: "add", Parent: "1", Content: "Subtask", Properties: map[string]string{"LINK": "[[https://example.com][ticket]]"}})},
+				},
+				Columns: []*orgmcp.Column{&orgmcp.ColPreviewValue},
+			},
+			expected: []any{"PREVIEW\nSubtask\n"},
+		},
+		{
+			name: "ChangeId",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Properties: map[string]string{"ID": "2"}})},
+				},
+			},
+			expected: []any{"The ID property is the UID of the header and cannot be changed."},
+		},
+		{
+			name: "InvalidName",
+			input: tools.HeaderInput{
+				Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+					{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: "1", Status: "DONE", Properties: map[string]string{"TWO WORDS": "x"}})},
+				},
+			},
+			expected: []any{"Cannot set property TWO WORDS: invalid property name 'TWO WORDS', names cannot be empty or contain colons or whitespace"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.HeaderTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("HeaderTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				if !slices.ContainsFunc(res, func(v any) bool {
+					str, ok := v.(string)
+					return ok && EqualString(str, expectedStr.(string))
+				}) {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read org file: %v", err)
+	}
+
+	for _, expected := range []string{"* TODO Task\n", "  :ESTIMATE: 3\n", "  :CREATED: [2026-10-17 Sat 09:30]\n", "   :LINK: [[https://example.com][ticket]]\n"} {
+		if !ContainsString(string(written), expected) {
+			t.Errorf("expected %q in the file, got:\n%s", expected, written)
+		}
+	}
+
+	if ContainsString(string(written), "OWNER") || ContainsString(string(written), "TWO WORDS") {
+		t.Errorf("expected OWNER to be removed and TWO WORDS to be rejected, got:\n%s", written)
+	}
+}
+
+// TestHeaderIdWrittenOnEdit tests that a header without an ID only gets one written when it is edited
+func TestHeaderIdWrittenOnEdit(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/ids.org"
+	content := "* One\n* Two\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	of, err := orgmcp.OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	uid := of.Children()[1].Uid().String()
+
+	input := tools.HeaderInput{
+		Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+			{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: uid, Status: "TODO"})},
+		},
+	}
+
+	if _, err := tools.HeaderTool.Callback(context.TODO(), input, mcp.FuncOptions{DefaultPath: path}); err != nil {
+		t.Fatalf("HeaderTool failed: %v", err)
+	}
+
+	written, _ := os.ReadFile(path)
+	expected := "* One\n* TODO Two\n  :PROPERTIES:\n  :ID: " + uid + "\n  :END:\n"
+
+	if string(written) != expected {
+		t.Errorf("unexpected file content\nExpected:\n%s\nGot:\n%s", expected, written)
+	}
+}
@@ -0,0 +1,234 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func IntoMoveOneOfArray[T tools.MoveInputReorder | tools.MoveInputSwap | tools.MoveInputRefile | tools.MoveInputPromote | tools.MoveInputDemote | tools.MoveInputArchive | tools.MoveInputSort](t ...T) []mcp.OneOf[*tools.MoveInputUnion] {
+	entries := []mcp.OneOf[*tools.MoveInputUnion]{}
+
+	for _, input := range t {
+		entries = append(entries, mcp.OneOf[*tools.MoveInputUnion]{
+			Value: tools.NewMoveInputUnion(input),
+		})
+	}
+
+	return entries
+}
+
+func TestMoveTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/move.org"
+	content := "* Inbox\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  - Call the bank\n** Report\n   :PROPERTIES:\n   :ID: 2\n   :END:\n" +
+		"* Work\n  :PROPERTIES:\n  :ID: 3\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	zero := 0
+	columns := []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColParentValue, &orgmcp.ColLevelValue}
+
+	tests := []ManageMoveTest{
+		{
+			name: "RefileHeaderAndBullet",
+			input: tools.MoveInputSchema{
+				Moves: append(
+					IntoMoveOneOfArray(tools.MoveInputRefile{Method: "refile", Uid: "2", Parent: "3"}),
+					IntoMoveOneOfArray(tools.MoveInputRefile{Method: "refile", Uid: "1.be9f7ec", Parent: "2", Index: &zero})...,
+				),
+				Columns: columns,
+			},
+			expected: []any{"UID,PARENT,LEVEL\n2,3,2\n2.be9f7ec,2,2\n"},
+		},
+		{
+			name: "PromoteHeader",
+			input: tools.MoveInputSchema{
+				Moves:   IntoMoveOneOfArray(tools.MoveInputPromote{Method: "promote", Uid: "2"}),
+				Columns: columns,
+			},
+			expected: []any{"UID,PARENT,LEVEL\n2,0,1\n"},
+		},
+		{
+			name: "ReorderNeedsIndexOrOffset",
+			input: tools.MoveInputSchema{
+				Moves: IntoMoveOneOfArray(tools.MoveInputReorder{Method: "reorder", Uid: "2"}),
+			},
+			expected: []any{"Pass either index or offset to reorder 2."},
+		},
+		{
+			name: "SortDescending",
+			input: tools.MoveInputSchema{
+				Moves:   IntoMoveOneOfArray(tools.MoveInputSort{Method: "sort", Uid: "0", By: "alpha", Descending: true}),
+				Columns: columns,
+			},
+			expected: []any{"UID,PARENT,LEVEL\n3,0,1\n2,0,1\n1,0,1\n"},
+		},
+		{
+			name: "SortUnknownKey",
+			input: tools.MoveInputSchema{
+				Moves: IntoMoveOneOfArray(tools.MoveInputSort{Method: "sort", Uid: "0", By: "size"}),
+			},
+			expected: []any{"invalid sort key size, valid keys are: [alpha status priority scheduled deadline closed property]"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.MoveTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("MoveTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				found := false
+				for _, v := range res {
+					if str, ok := v.(string); ok && EqualString(str, expectedStr.(string)) {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	written, _ := os.ReadFile(path)
+	expected := "* Work\n  :PROPERTIES:\n  :ID: 3\n  :END:\n" +
+		"* Report\n  :PROPERTIES:\n  :ID: 2\n  :END:\n  - Call the bank\n" +
+		"* Inbox\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if string(written) != expected {
+		t.Errorf("expected file %q, got %q", expected, string(written))
+	}
+}
+
+// TestMoveToolOtherFiles tests refiling to another file and archiving, every file the moves touch is written
+func TestMoveToolOtherFiles(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	dir := t.TempDir()
+	path := dir + "/inbox.org"
+	content := "* TODO Plan trip\n  SCHEDULED: <2026-10-20 Tue>\n  :PROPERTIES:\n  :ID: 1\n  :END:\n" +
+		"* DONE Pay rent\n  :PROPERTIES:\n  :ID: 2\n  :END:\n" +
+		"* DONE Renew passport\n  :PROPERTIES:\n  :ID: 3\n  :ARCHIVE: ::* Archive\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	if err := os.WriteFile(dir+"/projects.org", []byte("* Travel\n  :PROPERTIES:\n  :ID: 10\n  :END:\n"), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	res, err := tools.MoveTool.Callback(context.TODO(), tools.MoveInputSchema{
+		Moves: append(
+			IntoMoveOneOfArray(tools.MoveInputRefile{Method: "refile", Uid: "1", Parent: "10", File: "projects.org"}),
+			IntoMoveOneOfArray(tools.MoveInputArchive{Method: "archive", AllDone: true})...,
+		),
+		Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColLevelValue},
+	}, mcp.FuncOptions{DefaultPath: path})
+	if err != nil {
+		t.Fatalf("MoveTool failed: %v", err)
+	}
+
+	for _, v := range res {
+		if str, ok := v.(string); ok && !strings.HasPrefix(str, "UID") {
+			t.Errorf("unexpected error: %s", str)
+		}
+	}
+
+	files := map[string]string{
+		"projects.org": "* Travel\n  :PROPERTIES:\n  :ID: 10\n  :END:\n" +
+			"** TODO Plan trip\n   SCHEDULED: <2026-10-20 Tue>\n   :PROPERTIES:\n   :ID: 1\n   :END:\n",
+		"inbox.org_archive": "* DONE Pay rent\n  :PROPERTIES:\n  :ID: 2\n",
+		"inbox.org":         "* Archive\n",
+	}
+
+	for file, expected := range files {
+		written, err := os.ReadFile(dir + "/" + file)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", file, err)
+		}
+
+		if !strings.HasPrefix(string(written), expected) {
+			t.Errorf("expected %s to start with %q, got %q", file, expected, string(written))
+		}
+	}
+
+	inbox, _ := os.ReadFile(path)
+	if !strings.Contains(string(inbox), "** DONE Renew passport\n") || !strings.Contains(string(inbox), ":ARCHIVE_FILE: "+path+"\n") {
+		t.Errorf("expected the passport to be archived under the Archive heading of the same file, got %q", string(inbox))
+	}
+}
+
+// TestMoveToolLocked tests that a refile to another file writes neither file when the main file is locked
+func TestMoveToolLocked(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.RespectLocks = true
+	orgmcp.Configure(config)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "inbox.org")
+	target := filepath.Join(dir, "projects.org")
+	content := "* TODO Plan trip\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+	targetContent := "* Travel\n  :PROPERTIES:\n  :ID: 10\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	if err := os.WriteFile(target, []byte(targetContent), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	if err := os.Symlink("sam@laptop.4242:1760000000", filepath.Join(dir, ".#inbox.org")); err != nil {
+		t.Fatalf("failed to create lock file: %v", err)
+	}
+
+	options := mcp.FuncOptions{DefaultPath: path, Files: mcp.NewFileCache()}
+
+	_, err := tools.MoveTool.Callback(context.TODO(), tools.MoveInputSchema{
+		Moves: IntoMoveOneOfArray(tools.MoveInputRefile{Method: "refile", Uid: "1", Parent: "10", File: "projects.org"}),
+	}, options)
+
+	var locked *mcp.LockedError
+	if !errors.As(err, &locked) {
+		t.Errorf("expected a locked error, got %v", err)
+	}
+
+	if written, _ := os.ReadFile(target); string(written) != targetContent {
+		t.Errorf("expected the target not to be written, got\n%s", written)
+	}
+
+	if written, _ := os.ReadFile(path); string(written) != content {
+		t.Errorf("expected the file not to be written, got\n%s", written)
+	}
+}
@@ -0,0 +1,57 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func TestRepairIDsTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/duplicates.org"
+	content := "* First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n* Copy\n  :PROPERTIES:\n  :ID: 1\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	options := mcp.FuncOptions{DefaultPath: path}
+
+	res, err := tools.StatusTool.Callback(context.TODO(), tools.StatusInputSchema{}, options)
+	if err != nil {
+		t.Fatalf("StatusTool failed: %v", err)
+	}
+
+	if warning, ok := res[0].(string); !ok || !strings.HasPrefix(warning, "Warning: Duplicate ID 1: 'Copy' has the same ID as 'First'") {
+		t.Errorf("expected a duplicate ID warning first, got %#v", res)
+	}
+
+	res, err = tools.RepairIDsTool.Callback(context.TODO(), tools.RepairIDsInputSchema{}, options)
+	if err != nil {
+		t.Fatalf("RepairIDsTool failed: %v", err)
+	}
+
+	output, _ := json.Marshal(res)
+	if !strings.Contains(string(output), `"old_uid":"1","preview":"Copy"`) || !strings.Contains(string(output), `"updated_links":0`) {
+		t.Errorf("unexpected response %s", output)
+	}
+
+	res, _ = tools.StatusTool.Callback(context.TODO(), tools.StatusInputSchema{}, options)
+	if len(res) != 1 {
+		t.Errorf("expected no warnings after the repair, got %#v", res)
+	}
+
+	written, _ := os.ReadFile(path)
+	if strings.Count(string(written), ":ID: 1\n") != 1 {
+		t.Errorf("expected only the first header to keep ID 1, got\n%s", written)
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func IntoTableOneOfArray[T tools.TableInputRead | tools.TableInputAdd | tools.TableInputSetCell | tools.TableInputAppendRow | tools.TableInputDeleteRow | tools.TableInputRemove](t ...T) []mcp.OneOf[*tools.TableInputUnion] {
+	entries := []mcp.OneOf[*tools.TableInputUnion]{}
+
+	for _, input := range t {
+		entries = append(entries, mcp.OneOf[*tools.TableInputUnion]{
+			Value: tools.NewTableInputUnion(input),
+		})
+	}
+
+	return entries
+}
+
+func TestTableTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	// Save original test.org content to restore at end
+	originalContent, err := os.ReadFile("./test.org")
+	if err != nil {
+		t.Fatalf("failed to read test.org: %v", err)
+	}
+	defer func() {
+		os.WriteFile("./test.org", originalContent, 0644)
+	}()
+
+	headerUid := "99998888"
+	// the UID of a table follows its cells, so it changes with every edit
+	tableUid := headerUid + ".tblaff67a"
+	editedUid := headerUid + ".tblafed08"
+	appendedUid := headerUid + ".tblaa6dd3"
+	deletedUid := headerUid + ".tblec55bd"
+
+	tests := []ManageTableTest{
+		{
+			name: "AddTable",
+			input: tools.TableInputSchema{
+				Tables: IntoTableOneOfArray(tools.TableInputAdd{
+					Method: "add",
+					Parent: headerUid,
+					Csv:    "Task,Estimate\nParser,3\n",
+					Header: true,
+				}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColContentValue},
+			},
+			expected: []any{"UID,CONTENT\n99998888.tblaff67a,  | Task   | Estimate |\\n  |--------+----------|\\n  | Parser |        3 |\n"},
+		},
+		{
+			name: "SetCell",
+			input: tools.TableInputSchema{
+				Tables:  IntoTableOneOfArray(tools.TableInputSetCell{Method: "set_cell", Uid: tableUid, Row: 1, Column: 1, Value: "5"}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue},
+			},
+			expected: []any{"UID\n" + editedUid + "\n"},
+		},
+		{
+			name: "AppendRow",
+			input: tools.TableInputSchema{
+				Tables:  IntoTableOneOfArray(tools.TableInputAppendRow{Method: "append_row", Uid: editedUid, Cells: []string{"Renderer", "2"}}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue},
+			},
+			expected: []any{"UID\n" + appendedUid + "\n"},
+		},
+		{
+			name: "ReadTable",
+			input: tools.TableInputSchema{
+				Tables: IntoTableOneOfArray(tools.TableInputRead{Method: "read", Uid: appendedUid}),
+			},
+			expected: []any{"Task,Estimate\nParser,5\nRenderer,2\n"},
+		},
+		{
+			name: "DeleteRowOutOfRange",
+			input: tools.TableInputSchema{
+				Tables: IntoTableOneOfArray(tools.TableInputDeleteRow{Method: "delete_row", Uid: appendedUid, Row: 3}),
+			},
+			expected: []any{"row 3 out of range, the table has 3 rows"},
+		},
+		{
+			name: "DeleteRow",
+			input: tools.TableInputSchema{
+				Tables: IntoTableOneOfArray(
+					tools.TableInputDeleteRow{Method: "delete_row", Uid: appendedUid, Row: 1},
+				),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColContentValue},
+			},
+			expected: []any{"UID,CONTENT\n" + deletedUid + ",  | Task     | Estimate |\\n  |----------+----------|\\n  | Renderer |        2 |\n"},
+		},
+		{
+			name: "RemoveTable",
+			input: tools.TableInputSchema{
+				Tables:  IntoTableOneOfArray(tools.TableInputRemove{Method: "remove", Uid: deletedUid}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColChildrenCountValue},
+			},
+			expected: []any{"UID,CHILDREN_COUNT\n99998888,0\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.TableTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: "./test.org"})
+			if err != nil {
+				t.Errorf("TableTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				found := false
+				for _, v := range res {
+					if str, ok := v.(string); ok && EqualString(str, expectedStr.(string)) {
+						found = true
+						break
+					}
+				}
+
+				if !found {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"testing"
 
@@ -13,7 +14,7 @@ import (
 	"github.com/p3rtang/org-mcp/tools"
 )
 
-func IntoOneOfArray[T tools.TextInputAdd | tools.TextInputUpdate | tools.TextInputRemove](t ...T) []mcp.OneOf[*tools.TextInputUnion] {
+func IntoOneOfArray[T tools.TextInputRead | tools.TextInputAdd | tools.TextInputUpdate | tools.TextInputRemove](t ...T) []mcp.OneOf[*tools.TextInputUnion] {
 	entries := []mcp.OneOf[*tools.TextInputUnion]{}
 
 	for _, input := range t {
@@ -43,10 +44,13 @@ func TestTextTool(t *testing.T) {
 	// Use stable UIDs from test.org
 	addHeaderUid := "99998888"
 	updateHeaderUid := "99998889"
-	plainTextUid := "99998889.t0"
+	// the UID of plain text follows its content, so it changes with every update
+	plainTextUid := "99998889.t96796b"
+	updatedUid := "99998889.t62d482"
+	diffUid := "99998889.td70225"
 
-	// UID for a bullet in test.org (header 3 has bullets 3.b0 and 3.b1)
-	bulletWithChildrenUid := "3.b0"
+	// UID for a bullet in test.org (header 3 has bullets Task 1 and Task 2)
+	bulletWithChildrenUid := "3.bdc90af"
 
 	tests := []ManageTextTest{
 		{
@@ -98,7 +102,7 @@ func TestTextTool(t *testing.T) {
 			name: "ShowDiff",
 			input: tools.TextInputSchema{
 				Texts: IntoOneOfArray(tools.TextInputUpdate{
-					Uid:     plainTextUid,
+					Uid:     updatedUid,
 					Method:  "update",
 					Content: "Content with diff",
 				}),
@@ -163,14 +167,14 @@ func TestTextTool(t *testing.T) {
 			name: "RemoveTextContent",
 			input: tools.TextInputSchema{
 				Texts: IntoOneOfArray(tools.TextInputRemove{
-					Uid:    plainTextUid,
+					Uid:    diffUid,
 					Method: "remove",
 				}),
 				Columns: []*orgmcp.Column{
 					&orgmcp.ColUidValue,
 				},
 			},
-			expected: []any{"99998889"},
+			expected: []any{"UID\n99998889\n"},
 		},
 		{
 			name: "AddMultipleTextsToSameHeader",
@@ -230,7 +234,7 @@ func TestTextTool(t *testing.T) {
 			name: "UpdatePlainTextUnderBullet",
 			input: tools.TextInputSchema{
 				Texts: IntoOneOfArray(tools.TextInputUpdate{
-					Uid:     "99998891.b0.t0",
+					Uid:     "99998891.b958a0c.t655c1c",
 					Method:  "update",
 					Content: "Updated plain text under bullet",
 				}),
@@ -261,7 +265,7 @@ func TestTextTool(t *testing.T) {
 			name: "HeaderCorruptionRepro",
 			input: tools.TextInputSchema{
 				Texts: IntoOneOfArray(tools.TextInputUpdate{
-					Uid:     "99998892.t0",
+					Uid:     "99998892.teb9e52",
 					Method:  "update",
 					Content: "Updated text content.",
 				}),
@@ -327,3 +331,78 @@ func TestTextTool(t *testing.T) {
 	// Restore original state
 	os.WriteFile("./test.org", originalContent, 0644)
 }
+
+func TestTextToolDrawer(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	path := t.TempDir() + "/drawer.org"
+	content := "* Header\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  :RESULTS:\n  old\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	tests := []ManageTextTest{
+		{
+			name: "ReadDrawer",
+			input: tools.TextInputSchema{
+				Texts: IntoOneOfArray(tools.TextInputRead{Method: "read", Uid: "1.drwc6a7ce"}),
+			},
+			expected: []any{"old"},
+		},
+		{
+			name: "UpdateDrawer",
+			input: tools.TextInputSchema{
+				Texts:   IntoOneOfArray(tools.TextInputUpdate{Method: "update", Uid: "1.drwc6a7ce", Content: "new\n- kept verbatim"}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColContentValue},
+			},
+			expected: []any{"UID,CONTENT\n1.drweddb56,  :RESULTS:\\n  new\\n  - kept verbatim\\n  :END:\n"},
+		},
+		{
+			name: "AddDrawer",
+			input: tools.TextInputSchema{
+				Texts:   IntoOneOfArray(tools.TextInputAdd{Method: "add", Parent: "1", Drawer: "NOTES", Content: "first\nsecond"}),
+				Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue},
+			},
+			expected: []any{"UID,PREVIEW\n1.drwf65198,:NOTES:\\nfirst\\nsecond\n"},
+		},
+		{
+			name: "UpdateDrawerWithEndLineFails",
+			input: tools.TextInputSchema{
+				Texts: IntoOneOfArray(tools.TextInputUpdate{Method: "update", Uid: "1.drwf65198", Content: ":END:"}),
+			},
+			expected: []any{"drawer content cannot contain the line ':END:'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := tools.TextTool.Callback(context.TODO(), tt.input, mcp.FuncOptions{DefaultPath: path})
+			if err != nil {
+				t.Errorf("TextTool failed: %v", err)
+			}
+
+			for _, expectedStr := range tt.expected {
+				if !slices.ContainsFunc(res, func(v any) bool {
+					str, ok := v.(string)
+					return ok && EqualString(str, expectedStr.(string))
+				}) {
+					t.Errorf("unexpected response: got %#v, expected to contain '%s'", res, expectedStr)
+				}
+			}
+		})
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read org file: %v", err)
+	}
+
+	expected := "* Header\n  :PROPERTIES:\n  :ID: 1\n  :END:\n  :RESULTS:\n  new\n  - kept verbatim\n  :END:\n  :NOTES:\n  first\n  second\n  :END:\n"
+	if string(written) != expected {
+		t.Errorf("unexpected file content\nExpected:\n%s\nGot:\n%s", expected, written)
+	}
+}
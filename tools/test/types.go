@@ -20,3 +20,33 @@ type ManageTextTest struct {
 	expected    []any
 	expectEmpty bool
 }
+
+type ManageBlockTest struct {
+	name     string
+	input    tools.BlockInputSchema
+	expected []any
+}
+
+type ManageTableTest struct {
+	name     string
+	input    tools.TableInputSchema
+	expected []any
+}
+
+type ManageClockTest struct {
+	name     string
+	input    tools.ClockInputSchema
+	expected []any
+}
+
+type ManageMoveTest struct {
+	name     string
+	input    tools.MoveInputSchema
+	expected []any
+}
+
+type ManageCloneTest struct {
+	name     string
+	input    tools.CloneInputSchema
+	expected []any
+}
@@ -0,0 +1,87 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/p3rtang/org-mcp/mcp"
+	"github.com/p3rtang/org-mcp/orgmcp"
+	"github.com/p3rtang/org-mcp/tools"
+)
+
+func TestUndoTool(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	previous := orgmcp.CurrentConfig()
+	t.Cleanup(func() { orgmcp.Configure(previous) })
+
+	config := previous
+	config.JournalSize = 10
+	orgmcp.Configure(config)
+
+	path := t.TempDir() + "/undo.org"
+	content := "* TODO First\n  :PROPERTIES:\n  :ID: 1\n  :END:\n* TODO Second\n  :PROPERTIES:\n  :ID: 2\n  :END:\n"
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write org file: %v", err)
+	}
+
+	options := mcp.FuncOptions{DefaultPath: path, Files: mcp.NewFileCache()}
+
+	for _, uid := range []string{"1", "2"} {
+		_, err := tools.HeaderTool.Callback(context.TODO(), tools.HeaderInput{
+			Headers: []mcp.OneOf[*tools.HeaderInputUnion]{
+				{Value: tools.NewHeaderInputUnion(tools.HeaderInputUpdate{Method: "update", Uid: uid, Status: "NEXT"})},
+			},
+		}, options)
+		if err != nil {
+			t.Fatalf("HeaderTool failed: %v", err)
+		}
+	}
+
+	// a tool that is not journaled
+	if _, err := tools.StatusTool.Callback(context.TODO(), tools.StatusInputSchema{}, options); err != nil {
+		t.Fatalf("StatusTool failed: %v", err)
+	}
+
+	res, err := tools.UndoTool.Callback(context.TODO(), tools.UndoInputSchema{Method: "history"}, options)
+	if err != nil {
+		t.Fatalf("UndoTool failed: %v", err)
+	}
+
+	output, _ := json.Marshal(res)
+	if !strings.Contains(string(output), `"undo":[{"id":2,`) || strings.Count(string(output), `"tool":"manage_header"`) != 2 {
+		t.Errorf("expected two manage_header calls in the history, got %s", output)
+	}
+
+	if _, err := tools.UndoTool.Callback(context.TODO(), tools.UndoInputSchema{Method: "undo", Count: 2}, options); err != nil {
+		t.Fatalf("UndoTool failed: %v", err)
+	}
+
+	if written, _ := os.ReadFile(path); string(written) != content {
+		t.Errorf("expected both updates to be undone, got\n%s", written)
+	}
+
+	if _, err := tools.UndoTool.Callback(context.TODO(), tools.UndoInputSchema{Method: "redo"}, options); err != nil {
+		t.Fatalf("UndoTool failed: %v", err)
+	}
+
+	// the cache must not hand out the file from before the redo
+	res, err = tools.ViewTool.Callback(context.TODO(), tools.ViewInput{
+		Items:   []tools.ViewItem{{Uid: "1"}},
+		Columns: []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColStatusValue},
+	}, options)
+	if err != nil {
+		t.Fatalf("ViewTool failed: %v", err)
+	}
+
+	if output, _ := json.Marshal(res); !strings.Contains(string(output), `1,NEXT`) {
+		t.Errorf("expected the first update to be redone, got %s", output)
+	}
+}
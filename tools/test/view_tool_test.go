@@ -32,6 +32,7 @@ func TestViewTool(t *testing.T) {
 		Todo         = orgmcp.RenderStatus(orgmcp.Todo)
 		twoDaysRange = 2
 		startDate    = "2025-12-31"
+		priorityA    = "a"
 	)
 
 	var testMap = []Test{
@@ -89,7 +90,7 @@ func TestViewTool(t *testing.T) {
 					&orgmcp.ColUidValue,
 				},
 			},
-			expected: []any{"UID\\n3\\n3.b0\\n3.b1"},
+			expected: []any{"UID\\n3\\n3.bdc90af\\n3.bdc9242"},
 		},
 		{
 			name: "GetByRegex",
@@ -106,6 +107,22 @@ func TestViewTool(t *testing.T) {
 			},
 			expected: []any{"UID\\n2"},
 		},
+		{
+			name: "GetKeyword",
+			input: tools.ViewInput{
+				Items: []tools.ViewItem{
+					{
+						Content: "^#\\+TITLE",
+						Depth:   &depth,
+					},
+				},
+				Columns: []*orgmcp.Column{
+					&orgmcp.ColUidValue,
+					&orgmcp.ColPreviewValue,
+				},
+			},
+			expected: []any{"UID,PREVIEW\\n0.k2641e9,#+TITLE: Tool test file"},
+		},
 		{
 			name: "GetByOverdue",
 			input: tools.ViewInput{
@@ -160,6 +177,22 @@ func TestViewTool(t *testing.T) {
 			},
 			expected: []any{"UID\\n95718900"},
 		},
+		{
+			name: "GetByPriority",
+			input: tools.ViewInput{
+				Items: []tools.ViewItem{
+					{
+						Priority: &priorityA,
+						Depth:    &depth,
+					},
+				},
+				Columns: []*orgmcp.Column{
+					&orgmcp.ColUidValue,
+					&orgmcp.ColPriorityValue,
+				},
+			},
+			expected: []any{"UID,PRIORITY\\n95718920,A"},
+		},
 		{
 			name: "GetAllColumns",
 			input: tools.ViewInput{
@@ -171,7 +204,7 @@ func TestViewTool(t *testing.T) {
 				},
 				Columns: slice.Ref(orgmcp.AllColumns),
 			},
-			expected: []any{"TYPE,UID,PREVIEW,CONTENT,STATUS,PROGRESS,PARENT,CHILDREN_COUNT,TAGS,LEVEL,PATH,SCHEDULED,DEADLINE,CLOSED\\n*orgmcp.Header,95718920,All columns,* DONE All columns [1/3] :tag:,DONE,1/3,0,3,tag,1,/95718920,2026-02-02,2026-02-03,2026-02-02 18:16"},
+			expected: []any{"TYPE,UID,PREVIEW,CONTENT,STATUS,PRIORITY,PROGRESS,PARENT,CHILDREN_COUNT,TAGS,LEVEL,PATH,SCHEDULED,DEADLINE,CLOSED,CLOCKED,PROPERTIES\\n*orgmcp.Header,95718920,All columns,* DONE [#A] All columns [1/3] :tag:,DONE,A,1/3,0,3,tag,1,/95718920,2026-02-02,2026-02-03,2026-02-02 18:16,,{\\\"ID\\\":95718920}"},
 		},
 	}
 
@@ -208,3 +241,56 @@ func TestViewTool(t *testing.T) {
 		})
 	}
 }
+
+func TestFilterDateTimes(t *testing.T) {
+	showDebug := os.Getenv("SHOW_DEBUG")
+	if showDebug == "" {
+		os.Stderr, _ = os.OpenFile("/dev/null", os.O_WRONLY, 0644)
+	}
+
+	content := "* TODO Morning\n  SCHEDULED: <2026-10-20 Tue 09:00>\n  :PROPERTIES:\n  :ID: 1\n  :END:\n" +
+		"* TODO Afternoon\n  SCHEDULED: <2026-10-20 Tue 14:00-15:30>\n  :PROPERTIES:\n  :ID: 2\n  :END:\n" +
+		"* TODO Conference\n  SCHEDULED: <2026-10-18 Sun>--<2026-10-21 Wed>\n  :PROPERTIES:\n  :ID: 3\n  :END:\n"
+
+	of, err := orgmcp.OrgFileFromReader(context.TODO(), strings.NewReader(content)).Split()
+	if err != nil {
+		t.Fatalf("failed to parse org file: %v", err)
+	}
+
+	var (
+		noon    = "2026-10-20 12:00"
+		evening = "2026-10-20 18:00"
+		oneDay  = 1
+	)
+
+	tests := []struct {
+		name     string
+		filter   tools.DateFilter
+		expected []int
+	}{
+		{"BeforeNoon", tools.DateFilter{Match: orgmcp.ScheduledValue, Date: &noon}, []int{1, 3}},
+		{"BeforeEvening", tools.DateFilter{Match: orgmcp.ScheduledValue, Date: &evening}, []int{1, 2, 3}},
+		{"AfternoonOfOneDay", tools.DateFilter{Match: orgmcp.ScheduledValue, Date: &noon, Range: &oneDay}, []int{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched := []int{}
+
+			for _, uid := range []int{1, 2, 3} {
+				match, err := tools.FilterDate(of.GetUid(orgmcp.NewUid(uid)).Unwrap(), &tt.filter)
+				if err != nil {
+					t.Fatalf("FilterDate failed: %v", err)
+				}
+
+				if match {
+					matched = append(matched, uid)
+				}
+			}
+
+			if fmt.Sprint(matched) != fmt.Sprint(tt.expected) {
+				t.Errorf("expected %v to match, got %v", tt.expected, matched)
+			}
+		})
+	}
+}
@@ -25,13 +25,19 @@ type TextInputSchema struct {
 type TextInputUnion struct {
 	tag string
 
+	Read   TextInputRead
 	Add    TextInputAdd
 	Update TextInputUpdate
 	Remove TextInputRemove
 }
 
-func NewTextInputUnion[T TextInputAdd | TextInputUpdate | TextInputRemove](input T) *TextInputUnion {
+func NewTextInputUnion[T TextInputRead | TextInputAdd | TextInputUpdate | TextInputRemove](input T) *TextInputUnion {
 	switch any(input).(type) {
+	case TextInputRead:
+		return &TextInputUnion{
+			tag:  "read",
+			Read: any(input).(TextInputRead),
+		}
 	case TextInputAdd:
 		return &TextInputUnion{
 			tag: "add",
@@ -68,6 +74,8 @@ func NewTextInputUnionRemove(remove TextInputRemove) TextInputUnion {
 
 func (t *TextInputUnion) Value() any {
 	switch t.tag {
+	case "read":
+		return t.Read
 	case "add":
 		return t.Add
 	case "update":
@@ -88,6 +96,9 @@ func (t *TextInputUnion) FromJSON(data []byte) error {
 	}
 
 	switch raw["method"] {
+	case "read":
+		t.tag = "read"
+		return json.Unmarshal(data, &t.Read)
 	case "add":
 		fallthrough
 	case nil:
@@ -104,10 +115,52 @@ func (t *TextInputUnion) FromJSON(data []byte) error {
 	}
 }
 
+// Apply applies the operation of the union to the file.
+func (t *TextInputUnion) Apply(ctx context.Context, of *orgmcp.OrgFile) ApplyResult {
+	switch t.tag {
+	case "read":
+		return t.Read.Apply(ctx, of)
+	case "add":
+		return t.Add.Apply(ctx, of)
+	case "update":
+		return t.Update.Apply(ctx, of)
+	case "remove":
+		return t.Remove.Apply(ctx, of)
+	default:
+		return ApplyResult{err: fmt.Errorf("invalid method: %s", t.tag)}
+	}
+}
+
+type TextInputRead struct {
+	Method string `json:"method" jsonschema:"description=Read the verbatim content of a text element or drawer.,enum=read"`
+	Uid    string `json:"uid" jsonschema:"description=The UID of the element to read."`
+}
+
+func (t *TextInputRead) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
+	selected, ok := of.GetUid(orgmcp.NewUid(t.Uid)).Split()
+
+	if !ok {
+		res.err = fmt.Errorf("Item with uid %s not found in %s.", t.Uid, of.Name())
+		return
+	}
+
+	switch item := selected.(type) {
+	case *orgmcp.PlainText:
+		res.output = append(res.output, item.Preview(-1))
+	case *orgmcp.Drawer:
+		res.output = append(res.output, item.Content())
+	default:
+		res.err = fmt.Errorf("Uid %s is not a plain text element or drawer, cannot read content", t.Uid)
+	}
+
+	return
+}
+
 type TextInputAdd struct {
 	Method  string `json:"method" jsonschema:"description=Add new text content under the specified parent element.,enum=add"`
 	Parent  string `json:"parent" jsonschema:"description=The UID of the parent element under which the text will be added. This can be either a header or a bullet point."`
 	Content string `json:"content" jsonschema:"description=The text content to add. Newlines will result in multiple plain text elements being added, one for each line of text."`
+	Drawer  string `json:"drawer,omitempty" jsonschema:"description=When set the content is added as a single drawer with this name (e.g. NOTES) instead. The content of a drawer is kept verbatim including newlines.,required=false"`
 }
 
 func (t *TextInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
@@ -119,6 +172,22 @@ func (t *TextInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res Apply
 		return
 	}
 
+	if t.Drawer != "" {
+		drawer, err := orgmcp.NewDrawer(t.Drawer, t.Content)
+		if err != nil {
+			res.err = err
+			return
+		}
+
+		if err := parentUid.AddChildren(&drawer); err != nil {
+			res.err = err
+			return
+		}
+
+		res.affectedItems[drawer.Uid()] = &drawer
+		return
+	}
+
 	for c := range strings.SplitSeq(t.Content, "\n") {
 		newPlainText := orgmcp.NewPlainText(c)
 		parentUid.AddChildren(&newPlainText)
@@ -132,7 +201,7 @@ func (t *TextInputAdd) Apply(ctx context.Context, of *orgmcp.OrgFile) (res Apply
 type TextInputUpdate struct {
 	Uid     string `json:"uid" jsonschema:"description=The UID of the element to modify or remove."`
 	Method  string `json:"method" jsonschema:"description=Update the content of a text element.,enum=update"`
-	Content string `json:"content,omitempty" jsonschema:"description=The new content of the text element."`
+	Content string `json:"content,omitempty" jsonschema:"description=The new content of the text element. For drawers this replaces the whole content and may contain newlines."`
 }
 
 func (t *TextInputUpdate) Apply(ctx context.Context, of *orgmcp.OrgFile) (res ApplyResult) {
@@ -143,6 +212,17 @@ func (t *TextInputUpdate) Apply(ctx context.Context, of *orgmcp.OrgFile) (res Ap
 		res.err = fmt.Errorf("Item with uid %s not found in %s.", t.Uid, of.Name())
 		return
 	}
+
+	if drawer, ok := selected.(*orgmcp.Drawer); ok {
+		if err := drawer.SetContent(t.Content); err != nil {
+			res.err = err
+			return
+		}
+
+		res.affectedItems[drawer.Uid()] = drawer
+		return
+	}
+
 	if strings.Contains(t.Content, "\n") {
 		res.err = fmt.Errorf("Content with newlines is not allowed for the update method, these will be replaced with spaces. If you want to add content with newlines you should use the 'add' method to add new text elements for each line of text.")
 		t.Content = strings.ReplaceAll(t.Content, "\n", " ")
@@ -189,27 +269,28 @@ var TextTool = mcp.GenericTool[TextInputSchema]{
 Add, update or remove text content in an Org file.
 Plain text is a special type of content that cannot contain any nested elements.
 It is solely used for storing text content within a header or bullet point.
+Drawers like :NOTES: or :RESULTS: are managed by this tool as well, their content is kept verbatim.
 
 Most of the time it will be more correct to use a bullet point to store text content as it allows for better organization and structuring of the content.
 But there are situations where plain text is more appropriate, such as large block of text without structure, like github issues etc.
 
 ## Methods
 ` +
+		"`read`: Returns the verbatim content of a text element or drawer. The element is identified by its uid.\n" +
 		"`add`: Adds new text content to the specified parent element. The parent is passed via the parent parameter.\n" +
 		"`update`: Updates the text content of the specified element. The element is identified by its uid.\n" +
 		"`remove`: Removes the text content of the specified element. The element is identified by its uid.\n" +
 		`
 ## UID Constructions
 You can target either a header or a bullet point when adding text content. The uid will be the parent itself.
-Otherwise the uid construction is similar to the bullet tool.
-The text index is relative to the parent element, you can have multiple text elements under the same parent.
-The index is based on newline separation, but is deligneated by the parent element.
+Otherwise the uid construction is similar to the bullet tool, a text element uses '.t' and a hash of its content.
+Updating the text gives it a new uid which is returned in the output, adding or removing other elements never changes it.
+Drawers use '.drw' and a hash of their name and content, so updating a drawer gives it a new uid as well.
 
 ## Diff
 You always have the options with any modification to show a diff of the changes made.
 This can inform both you as well as the user about what exactly a tool call changed, and always you to undo changes if needed.
-` +
-		"`parent_uid + .t + text_index`\n",
+`,
 	Callback: func(ctx context.Context, input TextInputSchema, options mcp.FuncOptions) (resp []any, err error) {
 		var path string
 		if input.Path == "" {
@@ -218,37 +299,32 @@ This can inform both you as well as the user about what exactly a tool call chan
 			path = input.Path
 		}
 
-		orgFile, err := mcp.LoadOrgFile(ctx, path)
+		orgFile, err := options.Files.Load(ctx, path)
 		if err != nil {
 			return
 		}
 
+		resp = append(resp, fileWarnings(&orgFile)...)
+
 		affectedCount := 0
 		affectedItems := map[orgmcp.Uid]orgmcp.Render{}
 
 		for _, mt := range input.Texts {
-			var res ApplyResult
-
-			switch mt.Value.Tag() {
-			case "add":
-				res = mt.Value.Add.Apply(ctx, &orgFile)
-			case "update":
-				res = mt.Value.Update.Apply(ctx, &orgFile)
-			case "remove":
-				res = mt.Value.Remove.Apply(ctx, &orgFile)
-			}
+			res := mt.Value.Apply(ctx, &orgFile)
+			orgFile.Reindex()
 
 			if res.err != nil {
 				resp = append(resp, res.err.Error())
 			}
 
+			resp = append(resp, res.output...)
 			maps.Copy(affectedItems, res.affectedItems)
 			affectedCount += len(res.affectedItems)
 		}
 
 		ordered := []orgmcp.Render{}
 
-		if input.ShowAffected == nil || *input.ShowAffected == true {
+		if (input.ShowAffected == nil || *input.ShowAffected == true) && affectedCount > 0 {
 			locationTable := orgFile.BuildLocationTable()
 			ordered = append(ordered, itertools.Collect(maps.Values(affectedItems))...)
 
@@ -262,7 +338,7 @@ This can inform both you as well as the user about what exactly a tool call chan
 			})
 		}
 
-		diff, err := mcp.WriteOrgFileToDisk(ctx, orgFile, path)
+		diff, err := writeJournaled(ctx, options, "manage_text", orgFile, path)
 		if input.ShowDiff {
 			resp = append(resp, diff)
 		}
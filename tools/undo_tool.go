@@ -0,0 +1,95 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/p3rtang/org-mcp/mcp"
+)
+
+type UndoInputSchema struct {
+	Method   string `json:"method" jsonschema:"description=undo reverts the last changes; redo applies undone changes again and history lists the changes that can be undone and redone.,enum=undo;redo;history"`
+	Path     string `json:"path,omitempty" jsonschema:"description=The file path to the Org file. It will target the ./.tasks.org by default and you don't have to pass this in unless you want to target a different file.,required=false"`
+	Count    int    `json:"count,omitempty" jsonschema:"description=How many tool calls to undo or redo; default is 1.,default=1,required=false"`
+	ShowDiff bool   `json:"show_diff,omitempty" jsonschema:"description=Whether to show the diff of changes made to the Org file.,default=false"`
+}
+
+var UndoTool = mcp.GenericTool[UndoInputSchema]{
+	Name: "undo",
+	Description: `
+Reverts the changes of the last calls to manage_header, manage_bullet, manage_text and batch_edit, or applies reverted changes again.
+Every call of those tools that changed a file is recorded in a journal next to the file, also across restarts of the server.
+
+## Methods
+` +
+		"`undo`: Reverts the last `count` calls, the newest first.\n" +
+		"`redo`: Applies the last `count` undone calls again. A new change drops the calls that can be redone.\n" +
+		"`history`: Lists the calls that can be undone and redone with their id, tool and time.\n" +
+		`
+Changes made after a call by other tools or in Emacs are kept, each call is reverted with a three-way merge.
+When a reverted call conflicts with a later change nothing is written and the tool fails with a conflict error.
+`,
+
+	Callback: func(ctx context.Context, input UndoInputSchema, options mcp.FuncOptions) (resp []any, err error) {
+		var path string
+		if input.Path == "" {
+			path = options.DefaultPath
+		} else {
+			path = input.Path
+		}
+
+		var diff string
+		var entries []mcp.JournalEntry
+
+		switch input.Method {
+		case "history":
+			journal, err := mcp.LoadJournal(path)
+			if err != nil {
+				return nil, err
+			}
+
+			// the next call to undo or redo comes first
+			slices.Reverse(journal.Done)
+			slices.Reverse(journal.Undone)
+
+			return append(resp, map[string]any{
+				"undo": journalList(journal.Done),
+				"redo": journalList(journal.Undone),
+			}), nil
+		case "undo":
+			diff, entries, err = mcp.Undo(path, input.Count)
+		case "redo":
+			diff, entries, err = mcp.Redo(path, input.Count)
+		default:
+			return nil, fmt.Errorf("invalid method: %s", input.Method)
+		}
+
+		if err != nil {
+			return
+		}
+
+		options.Files.Invalidate(path)
+
+		resp = append(resp, map[string]any{input.Method: journalList(entries)})
+		if input.ShowDiff {
+			resp = append(resp, diff)
+		}
+
+		return
+	},
+}
+
+// journalList lists journal entries without their content.
+func journalList(entries []mcp.JournalEntry) []map[string]any {
+	list := []map[string]any{}
+	for _, entry := range entries {
+		list = append(list, map[string]any{
+			"id":   entry.ID,
+			"tool": entry.Tool,
+			"time": entry.Time.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return list
+}
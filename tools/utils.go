@@ -1,9 +1,14 @@
 package tools
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/p3rtang/org-mcp/mcp"
+
 	"github.com/p3rtang/org-mcp/orgmcp"
 	"github.com/p3rtang/org-mcp/utils/diff"
 )
@@ -25,7 +30,125 @@ func GetDiffOnly(of orgmcp.OrgFile, filePath string) (res string, err error) {
 	return
 }
 
+// fileWarnings returns the warnings of the file, like duplicate IDs or an Emacs lock, to add to a tool response.
+func fileWarnings(of *orgmcp.OrgFile) (warnings []any) {
+	for _, warning := range of.Warnings() {
+		warnings = append(warnings, "Warning: "+warning)
+	}
+
+	if owner, ok := mcp.LockOwner(of.Name()).Split(); ok {
+		warnings = append(warnings, fmt.Sprintf("Warning: %s has unsaved changes in Emacs (locked by %s), "+
+			"changes made now are merged with the file when it is saved or conflict with it", of.Name(), owner))
+	}
+
+	return
+}
+
+// fileSet holds the files a batch of operations changes besides the file the tool was called on.
+// Each file is loaded once, so later operations see the changes of earlier ones.
+type fileSet struct {
+	main     *orgmcp.OrgFile
+	mainPath string
+	paths    []string
+	files    map[string]*orgmcp.OrgFile
+	cache    *mcp.FileCache
+}
+
+func newFileSet(main *orgmcp.OrgFile, mainPath string, cache *mcp.FileCache) *fileSet {
+	return &fileSet{main: main, mainPath: mainPath, files: map[string]*orgmcp.OrgFile{}, cache: cache}
+}
+
+// get returns the file at path, a relative path is relative to the directory of the main file.
+// A file that does not exist is an error unless create is set, then it starts out empty.
+func (fs *fileSet) get(ctx context.Context, path string, create bool) (*orgmcp.OrgFile, error) {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(fs.mainPath), path)
+	}
+
+	path = filepath.Clean(path)
+
+	if path == filepath.Clean(fs.mainPath) {
+		return fs.main, nil
+	}
+
+	if of, ok := fs.files[path]; ok {
+		return of, nil
+	}
+
+	of, err := fs.cache.Load(ctx, path)
+	if os.IsNotExist(err) && create {
+		of, err = orgmcp.OrgFileFromReader(ctx, strings.NewReader("")).Split()
+		of.SetName(path)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	fs.paths = append(fs.paths, path)
+	fs.files[path] = &of
+
+	return &of, nil
+}
+
+// write writes the other files to disk and returns their diffs. It is called before the main file is written,
+// so a subtree that is moved is never lost: when a write fails the main file keeps it.
+func (fs *fileSet) write(ctx context.Context) (diffs []any, err error) {
+	for _, path := range fs.paths {
+		diff, err := fs.cache.Write(ctx, *fs.files[path], path)
+		if err != nil {
+			return diffs, err
+		}
+
+		diffs = append(diffs, diff)
+	}
+
+	return
+}
+
+// check returns the first conflict or lock that would stop one of the files from being written.
+func (fs *fileSet) check() error {
+	for _, path := range fs.paths {
+		if err := mcp.CheckWrite(*fs.files[path], path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// invalidate drops every file of the set from the cache, so changes that are not written are read again.
+func (fs *fileSet) invalidate() {
+	fs.cache.Invalidate(fs.mainPath)
+	for _, path := range fs.paths {
+		fs.cache.Invalidate(path)
+	}
+}
+
+// writeJournaled writes the file like FileCache.Write and records the change in the journal of the file,
+// so the tool call can be undone with the undo tool.
+func writeJournaled(ctx context.Context, options mcp.FuncOptions, tool string, of orgmcp.OrgFile, path string) (string, error) {
+	before, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+
+	diff, err := options.Files.Write(ctx, of, path)
+	if err != nil {
+		return diff, err
+	}
+
+	after, err := os.ReadFile(path)
+	if err != nil {
+		return diff, err
+	}
+
+	return diff, mcp.Record(path, tool, string(before), string(after))
+}
+
 type ApplyResult struct {
 	affectedItems map[orgmcp.Uid]orgmcp.Render
-	err           error
+	// output is returned to the client as is, for operations that read data
+	output []any
+	err    error
 }
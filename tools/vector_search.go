@@ -31,10 +31,11 @@ var VectorSearch = mcp.GenericTool[VectorSearchInput]{
 			input.TopN = 3.0
 		}
 
-		of, err := mcp.LoadOrgFile(ctx, filePath)
+		of, err := options.Files.Load(ctx, filePath)
 		if err != nil {
 			return
 		}
+		defer options.Files.Release(filePath)
 
 		locationTable := of.BuildLocationTable()
 		searchResults, err := of.VectorSearch(input.Query, input.TopN)
@@ -7,6 +7,7 @@ import (
 	"os"
 	"regexp"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/p3rtang/org-mcp/mcp"
@@ -15,12 +16,13 @@ import (
 )
 
 type ViewItem struct {
-	Uid     string               `json:"uid,omitempty" jsonschema:"description=UID of the header to view. If not provided, all headers are considered."`
-	Status  *orgmcp.RenderStatus `json:"status,omitempty" jsonschema:"description=Filter headers by status (e.g. TODO ; DONE). Case insensitive. As well as bullets by their checkbox status (e.g. CHECKED ; UNCHECKED)."`
-	Content string               `json:"content,omitempty" jsonschema:"description=Filter headers with a regex match on content. It will only consider the preview of the header content and not any metadata; children; status or other information."`
-	Tags    []string             `json:"tags,omitempty" jsonschema:"description=Filter headers by tags. Only headers containing all specified tags will be returned."`
-	Depth   *int                 `json:"depth,omitempty" jsonschema:"description=Depth of child headers to include. Default is 1 (only direct children)."`
-	Date    *DateFilter          `json:"date,omitempty" jsonschema:"description=Filter headers by date criteria. Any text containing dates is not yet supported."`
+	Uid      string               `json:"uid,omitempty" jsonschema:"description=UID of the header to view. If not provided, all headers are considered."`
+	Status   *orgmcp.RenderStatus `json:"status,omitempty" jsonschema:"description=Filter headers by status (e.g. TODO ; DONE). Case insensitive. As well as bullets by their checkbox status (e.g. CHECKED ; UNCHECKED)."`
+	Priority *string              `json:"priority,omitempty" jsonschema:"description=Filter headers by priority cookie (e.g. A ; B ; C). Case insensitive. Use NONE to get headers without a priority."`
+	Content  string               `json:"content,omitempty" jsonschema:"description=Filter headers with a regex match on content. It will only consider the preview of the header content and not any metadata; children; status or other information."`
+	Tags     []string             `json:"tags,omitempty" jsonschema:"description=Filter headers by tags. Only headers containing all specified tags will be returned."`
+	Depth    *int                 `json:"depth,omitempty" jsonschema:"description=Depth of child headers to include. Default is 1 (only direct children)."`
+	Date     *DateFilter          `json:"date,omitempty" jsonschema:"description=Filter headers by date criteria. Any text containing dates is not yet supported."`
 }
 
 type ViewInput struct {
@@ -43,14 +45,17 @@ List of columns to include in the output. If not specified defaults to [UID ; PR
   - PREVIEW: A short preview of the item's content (first 100 characters).
   - CONTENT: The full content of the item, including all text and metadata.
   - STATUS: The status of the item (e.g. TODO, DONE, CHECKED, UNCHECKED).
+  - PRIORITY: The priority cookie of a header (e.g. A, B, C), empty when not set.
   - PROGRESS: For items with children, the percentage of completed children.
   - PARENT: The UID of the parent item, if any.
   - CHILDREN_COUNT: The number of direct children this item has.
   - LEVEL: The level of the item in the hierarchy (1 for top-level headers, 2 for their children, etc.)
   - PATH: The file path of the item.
-  - SCHEDULED: The scheduled date of the item, if any.
-  - DEADLINE: The deadline date of the item, if any.
+  - SCHEDULED: The scheduled date of the item, if any. Includes the time of day and ranges (e.g. 2026-10-20 14:00-15:30 or 2026-10-20--2026-10-22).
+  - DEADLINE: The deadline date of the item, if any. Formatted like SCHEDULED.
   - CLOSED: The closed date of the item, if any.
+  - CLOCKED: The total clocked time of a header and its subheaders (e.g. 1:23), empty when nothing was clocked.
+  - PROPERTIES: The properties drawer of a header as a JSON object (e.g. {"ID":12,"OWNER":"alice"}), numbers are JSON numbers and all other values strings.
 `,
 		"type": "array",
 		"items": map[string]any{
@@ -62,7 +67,7 @@ List of columns to include in the output. If not specified defaults to [UID ; PR
 type DateFilter struct {
 	Match      string  `json:"match,omitempty" jsonschema:"description=The type of date match to perform.,enum=SCHEDULED;DEADLINE;CLOSED,required=true"`
 	ShowClosed bool    `json:"show_closed,omitempty" jsonschema:"description=Whether to include closed dates in the filter. Handy when using DEADLINE or SCHEDULED match types to show overdue and current tasks."`
-	Date       *string `json:"date,omitempty" jsonschema:"description=The date to match against in YYYY-MM-DD format. Will default to today."`
+	Date       *string `json:"date,omitempty" jsonschema:"description=The date to match against in YYYY-MM-DD or YYYY-MM-DD HH:MM format. Will default to now. Items with a time of day are compared including their time."`
 	Range      *int    `json:"range,omitempty" jsonschema:"description=The range in days to consider. For example you could request all deadlines this week by setting date to today and range to 7. Range can be negative and can be used in combination with the ommited date to get the week ahead or behind. For example, setting range to -7 will get all deadlines in the past week. When a negative range is used, the date will be considered the end date of the range and not included but up to."`
 }
 
@@ -76,6 +81,7 @@ var ViewTool = mcp.GenericTool[ViewInput]{
   - items: Array of filters (OR logic between items).
     - uid: string (optional)
     - status: "TODO" | "DONE" | "CHECKED" | ...
+    - priority: "A" | "B" | "C" | "NONE"
     - content: string (regex match)
     - tags: Array<string> (all tags must be present)
     - date_filter:
@@ -97,11 +103,13 @@ var ViewTool = mcp.GenericTool[ViewInput]{
 			path = input.Path
 		}
 
-		orgFile, err := mcp.LoadOrgFile(ctx, path)
+		orgFile, err := options.Files.Load(ctx, path)
 		if err != nil {
 			return
 		}
 
+		resp = append(resp, fileWarnings(&orgFile)...)
+
 		if len(input.Columns) == 0 {
 			input.Columns = []*orgmcp.Column{&orgmcp.ColUidValue, &orgmcp.ColPreviewValue}
 		}
@@ -123,6 +131,10 @@ var ViewTool = mcp.GenericTool[ViewInput]{
 					continue
 				}
 
+				if item.Priority != nil && !MatchPriority(render, *item.Priority) {
+					continue
+				}
+
 				if item.Content != "" {
 					reg, err := regexp.Compile(item.Content)
 					if err != nil {
@@ -175,12 +187,26 @@ var ViewTool = mcp.GenericTool[ViewInput]{
 		})
 
 		resp = append(resp, orgmcp.PrintCsv(ordered, input.Columns))
-		_, err = mcp.WriteOrgFileToDisk(ctx, orgFile, path)
+		_, err = options.Files.Write(ctx, orgFile, path)
 
 		return
 	},
 }
 
+// MatchPriority reports whether the render is a header with the given priority, NONE matches headers without a priority cookie.
+func MatchPriority(r orgmcp.Render, priority string) bool {
+	header, ok := r.(*orgmcp.Header)
+	if !ok {
+		return false
+	}
+
+	if strings.EqualFold(priority, string(orgmcp.None)) {
+		return header.Priority.IsNone()
+	}
+
+	return header.Priority.IsSome() && header.Priority == orgmcp.NewPriority(priority)
+}
+
 func FilterDate(r orgmcp.Render, dateFilter *DateFilter) (match bool, err error) {
 	if dateFilter == nil {
 		return true, err
@@ -198,11 +224,13 @@ func FilterDate(r orgmcp.Render, dateFilter *DateFilter) (match bool, err error)
 		return
 	}
 
-	var filterDate = time.Now()
+	var filterDate = orgmcp.Now()
 	if dateFilter.Date != nil {
 		var parsed time.Time
-		if parsed, err = time.Parse("2006-01-02", *dateFilter.Date); err != nil {
-			return
+		if parsed, err = time.ParseInLocation("2006-01-02 15:04", *dateFilter.Date, time.Local); err != nil {
+			if parsed, err = time.ParseInLocation("2006-01-02", *dateFilter.Date, time.Local); err != nil {
+				return
+			}
 		}
 
 		filterDate = parsed
@@ -225,7 +253,10 @@ func FilterDate(r orgmcp.Render, dateFilter *DateFilter) (match bool, err error)
 	if err != nil {
 		return
 	}
-	date := schedule.Values[scheduleStatus]
+	date, ok := schedule.Values[scheduleStatus]
+	if !ok {
+		return
+	}
 
 	fmt.Fprintf(os.Stderr, "Filtering date %v between %v and %v\n", date.T, startDate, endDate)
 
@@ -233,13 +264,10 @@ func FilterDate(r orgmcp.Render, dateFilter *DateFilter) (match bool, err error)
 		return
 	}
 
-	if endDate.IsZero() && date.T.Before(startDate) {
-		return true, nil
-	}
-
-	if date.T.Equal(startDate) || date.T.Before(startDate) || date.T.After(endDate) {
-		return false, nil
+	if endDate.IsZero() {
+		return date.T.Before(startDate), nil
 	}
 
-	return true, nil
+	// a time or date range matches when any part of it falls within the filter range
+	return date.EndOrStart().After(startDate) && !date.T.After(endDate), nil
 }
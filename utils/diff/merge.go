@@ -0,0 +1,127 @@
+package diff
+
+import (
+	"strings"
+
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+)
+
+// hunk replaces the lines [start, end) of the base with lines.
+type hunk struct {
+	start, end int
+	lines      []string
+}
+
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// hunks returns the changes from base to changed, edits that touch each other are joined into one hunk.
+func hunks(base, changed string) (hunks []hunk) {
+	for _, edit := range myers.ComputeEdits(span.URIFromPath(""), base, changed) {
+		h := hunk{start: edit.Span.Start().Line() - 1, end: edit.Span.End().Line() - 1}
+		if edit.NewText != "" {
+			h.lines = splitLines(edit.NewText)
+		}
+
+		if last := len(hunks) - 1; last >= 0 && hunks[last].end >= h.start {
+			hunks[last].end = max(hunks[last].end, h.end)
+			hunks[last].lines = append(hunks[last].lines, h.lines...)
+			continue
+		}
+
+		hunks = append(hunks, h)
+	}
+
+	return
+}
+
+// apply returns the lines [start, end) of the base with the hunks of one side that lie within them applied.
+func apply(base []string, start, end int, side []hunk) (lines []string) {
+	i := start
+	for _, h := range side {
+		lines = append(lines, base[i:h.start]...)
+		lines = append(lines, h.lines...)
+		i = h.end
+	}
+
+	return append(lines, base[i:end]...)
+}
+
+// Merge3 merges the changes from base to ours and from base to theirs line by line, like diff3.
+// Changes to the same or neighbouring lines that differ are a conflict, they are written between conflict
+// markers labelled with the names of both sides. It returns the merged text and the number of conflicts.
+func Merge3(base, ours, theirs, oursName, theirsName string) (string, int) {
+	baseLines := splitLines(base)
+	oursHunks := hunks(base, ours)
+	theirsHunks := hunks(base, theirs)
+
+	builder := strings.Builder{}
+	conflicts := 0
+	i := 0
+
+	for len(oursHunks) > 0 || len(theirsHunks) > 0 {
+		// the region starts at the first change of either side and grows while changes of the other side touch it
+		var start, end int
+		switch {
+		case len(theirsHunks) == 0 || len(oursHunks) > 0 && oursHunks[0].start <= theirsHunks[0].start:
+			start, end = oursHunks[0].start, oursHunks[0].end
+		default:
+			start, end = theirsHunks[0].start, theirsHunks[0].end
+		}
+
+		o, t := 0, 0
+		for grown := true; grown; {
+			grown = false
+
+			for ; o < len(oursHunks) && oursHunks[o].start <= end; o++ {
+				end, grown = max(end, oursHunks[o].end), true
+			}
+
+			for ; t < len(theirsHunks) && theirsHunks[t].start <= end; t++ {
+				end, grown = max(end, theirsHunks[t].end), true
+			}
+		}
+
+		builder.WriteString(strings.Join(baseLines[i:start], ""))
+
+		oursRegion := strings.Join(apply(baseLines, start, end, oursHunks[:o]), "")
+		theirsRegion := strings.Join(apply(baseLines, start, end, theirsHunks[:t]), "")
+
+		switch {
+		case t == 0 || oursRegion == theirsRegion:
+			builder.WriteString(oursRegion)
+		case o == 0:
+			builder.WriteString(theirsRegion)
+		default:
+			conflicts++
+			builder.WriteString("<<<<<<< " + oursName + "\n")
+			builder.WriteString(withNewline(oursRegion))
+			builder.WriteString("=======\n")
+			builder.WriteString(withNewline(theirsRegion))
+			builder.WriteString(">>>>>>> " + theirsName + "\n")
+		}
+
+		oursHunks = oursHunks[o:]
+		theirsHunks = theirsHunks[t:]
+		i = end
+	}
+
+	builder.WriteString(strings.Join(baseLines[i:], ""))
+
+	return builder.String(), conflicts
+}
+
+func withNewline(text string) string {
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		return text + "\n"
+	}
+
+	return text
+}
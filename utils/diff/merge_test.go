@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"testing"
+)
+
+// TestMerge3 tests that changes to different lines are merged and changes to the same lines conflict
+func TestMerge3(t *testing.T) {
+	base := "* One\n* Two\n* Three\n* Four\n* Five\n"
+
+	tests := []struct {
+		name      string
+		ours      string
+		theirs    string
+		expected  string
+		conflicts int
+	}{
+		{
+			name:     "OnlyOurs",
+			ours:     "* One\n* Two\n* Three\n* Four\n* Five\n* Six\n",
+			theirs:   base,
+			expected: "* One\n* Two\n* Three\n* Four\n* Five\n* Six\n",
+		},
+		{
+			name:     "OnlyTheirs",
+			ours:     base,
+			theirs:   "* Zero\n* One\n* Two\n* Three\n* Four\n* Five\n",
+			expected: "* Zero\n* One\n* Two\n* Three\n* Four\n* Five\n",
+		},
+		{
+			name:     "DifferentLines",
+			ours:     "* One\n* TODO Two\n* Three\n* Four\n* Five\n",
+			theirs:   "* One\n* Two\n* Three\n* Four\n* DONE Five\n",
+			expected: "* One\n* TODO Two\n* Three\n* Four\n* DONE Five\n",
+		},
+		{
+			name:     "SameChange",
+			ours:     "* One\n* Two\n* 3\n* Four\n* Five\n",
+			theirs:   "* One\n* Two\n* 3\n* Four\n* Five\n",
+			expected: "* One\n* Two\n* 3\n* Four\n* Five\n",
+		},
+		{
+			name:      "SameLine",
+			ours:      "* One\n* Two\n* TODO Three\n* Four\n* Five\n",
+			theirs:    "* One\n* Two\n* DONE Three\n* Four\n* Five\n",
+			expected:  "* One\n* Two\n<<<<<<< ours\n* TODO Three\n=======\n* DONE Three\n>>>>>>> theirs\n* Four\n* Five\n",
+			conflicts: 1,
+		},
+		{
+			// like git, changes to neighbouring lines conflict
+			name:      "NeighbouringLines",
+			ours:      "* One\n* 2\n* Three\n* Four\n* Five\n",
+			theirs:    "* One\n* Two\n* 3\n* Four\n* Five\n",
+			expected:  "* One\n<<<<<<< ours\n* 2\n* Three\n=======\n* Two\n* 3\n>>>>>>> theirs\n* Four\n* Five\n",
+			conflicts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged, conflicts := Merge3(base, tt.ours, tt.theirs, "ours", "theirs")
+
+			if merged != tt.expected {
+				t.Errorf("unexpected merge\nExpected:\n%s\nGot:\n%s", tt.expected, merged)
+			}
+
+			if conflicts != tt.conflicts {
+				t.Errorf("expected %d conflicts, got %d", tt.conflicts, conflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package diff
+
+import "strings"
+
+// PatchHunk replaces the lines Old at line Start of a text with the lines New.
+type PatchHunk struct {
+	Start int      `json:"start"`
+	Old   []string `json:"old,omitempty"`
+	New   []string `json:"new,omitempty"`
+}
+
+// Patch is the line based change from one text to another. It keeps the replaced lines as well,
+// so it can be reverted on the changed text without a copy of the original.
+type Patch []PatchHunk
+
+// NewPatch returns the patch that changes from into to.
+func NewPatch(from, to string) (patch Patch) {
+	lines := splitLines(from)
+
+	for _, h := range hunks(from, to) {
+		patch = append(patch, PatchHunk{Start: h.start, Old: lines[h.start:h.end], New: h.lines})
+	}
+
+	return
+}
+
+// Apply changes the text the patch was made from into the text it was made to.
+func (p Patch) Apply(from string) string {
+	lines := splitLines(from)
+	builder := strings.Builder{}
+	i := 0
+
+	for _, h := range p {
+		builder.WriteString(strings.Join(lines[i:h.Start], ""))
+		builder.WriteString(strings.Join(h.New, ""))
+		i = h.Start + len(h.Old)
+	}
+
+	builder.WriteString(strings.Join(lines[i:], ""))
+
+	return builder.String()
+}
+
+// Revert changes the text the patch was made to back into the text it was made from.
+func (p Patch) Revert(to string) string {
+	reverse := Patch{}
+	offset := 0
+
+	for _, h := range p {
+		reverse = append(reverse, PatchHunk{Start: h.Start + offset, Old: h.New, New: h.Old})
+		offset += len(h.New) - len(h.Old)
+	}
+
+	return reverse.Apply(to)
+}
@@ -0,0 +1,35 @@
+package diff
+
+import (
+	"testing"
+)
+
+// TestPatch tests that a patch changes one text into the other and back
+func TestPatch(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "Changed", from: "* One\n* Two\n* Three\n", to: "* One\n* TODO Two\n* Three\n"},
+		{name: "Inserted", from: "* One\n* Three\n", to: "* Zero\n* One\n* Two\n* Three\n* Four\n"},
+		{name: "Removed", from: "* One\n* Two\n* Three\n* Four\n", to: "* Two\n* Four\n"},
+		{name: "NoNewline", from: "* One\n* Two", to: "* One\n* Two\n* Three"},
+		{name: "Empty", from: "", to: "* One\n"},
+		{name: "Same", from: "* One\n", to: "* One\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patch := NewPatch(tt.from, tt.to)
+
+			if got := patch.Apply(tt.from); got != tt.to {
+				t.Errorf("Apply: expected %q, got %q", tt.to, got)
+			}
+
+			if got := patch.Revert(tt.to); got != tt.from {
+				t.Errorf("Revert: expected %q, got %q", tt.from, got)
+			}
+		})
+	}
+}
@@ -9,6 +9,8 @@ type PeekReader struct {
 	Reader *bufio.Reader
 
 	peekBuffer []byte
+	// consumed holds everything that was read, so callers can recover the original text
+	consumed []byte
 }
 
 func NewPeekReader(r *bufio.Reader) *PeekReader {
@@ -22,9 +24,20 @@ it will continue after any data previously peeked.
 Calling this method multiple times is safe and has no additional effect.
 */
 func (p *PeekReader) Continue() {
+	p.consumed = append(p.consumed, p.peekBuffer...)
 	p.peekBuffer = nil
 }
 
+// Offset returns the number of bytes consumed so far.
+func (p *PeekReader) Offset() int {
+	return len(p.consumed)
+}
+
+// Text returns the consumed data between two offsets.
+func (p *PeekReader) Text(from int, to int) string {
+	return string(p.consumed[from:to])
+}
+
 func (p *PeekReader) Peek(n int) (bytes []byte, err error) {
 	if len(p.peekBuffer) == 0 {
 		bytes = make([]byte, n)
@@ -68,8 +81,10 @@ func (p *PeekReader) ReadBytes(r byte) (bytes []byte, err error) {
 	// fmt.Fprintf(os.Stderr, "[INFO] ReadBytes\nPeekbuffer content: %s, found char at idx: %d\n", string(p.peekBuffer), idx)
 
 	if idx >= 0 {
+		bytes = bytes[:idx+1]
 		copy(bytes, p.peekBuffer[0:idx+1])
 		p.peekBuffer = p.peekBuffer[idx+1:]
+		p.consumed = append(p.consumed, bytes...)
 
 		return
 	}
@@ -79,6 +94,16 @@ func (p *PeekReader) ReadBytes(r byte) (bytes []byte, err error) {
 
 	extra, err := p.Reader.ReadBytes(r)
 	bytes = append(bytes, extra...)
+	p.consumed = append(p.consumed, bytes...)
 
 	return
 }
+
+/*
+Unread puts data that was already read back in front of the reader,
+the next read or peek will return it again.
+*/
+func (p *PeekReader) Unread(bytes []byte) {
+	p.peekBuffer = append(slices.Clone(bytes), p.peekBuffer...)
+	p.consumed = p.consumed[:max(len(p.consumed)-len(bytes), 0)]
+}